@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"os"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/bloxapp/eth2-key-manager/cli/util/cliflag"
@@ -14,15 +17,27 @@ const (
 
 // AddNetworkFlag adds the network flag to the command
 func AddNetworkFlag(c *cobra.Command) {
-	cliflag.AddPersistentStringFlag(c, networkFlag, string(core.TestNetwork), "Ethereum network", false)
+	cliflag.AddPersistentStringFlag(c, networkFlag, string(core.TestNetwork), "Ethereum network, a network name or the path of a YAML/JSON chain config file", false)
 }
 
-// GetNetworkFlagValue gets the network flag from the command
+// GetNetworkFlagValue gets the network flag from the command, chain config files are loaded and registered.
 func GetNetworkFlagValue(c *cobra.Command) (core.Network, error) {
 	networkValue, err := c.Flags().GetString(networkFlag)
 	if err != nil {
 		return "", err
 	}
 
-	return core.NetworkFromString(networkValue), nil
+	if info, err := os.Stat(networkValue); err == nil && !info.IsDir() {
+		config, err := core.LoadNetworkConfig(networkValue)
+		if err != nil {
+			return "", err
+		}
+		network, err := core.RegisterNetwork(config)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to register network")
+		}
+		return network, nil
+	}
+
+	return core.NetworkFromString(networkValue)
 }
@@ -0,0 +1,67 @@
+package flag
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bloxapp/eth2-key-manager/cli/util/cliflag"
+)
+
+// Flag names.
+const (
+	storageFlag               = "storage"
+	listenFlag                = "listen"
+	slashingDBFlag            = "slashing-db"
+	genesisValidatorsRootFlag = "genesis-validators-root"
+	storagePasswordFlag       = "storage-password"
+	storagePasswordEnvVar     = "STORAGE_PASSWORD"
+)
+
+// AddStorageFlag adds the storage flag to the command
+func AddStorageFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, storageFlag, "", "storage object", true)
+}
+
+// GetStorageFlagValue gets the storage flag from the command
+func GetStorageFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(storageFlag)
+}
+
+// AddListenFlag adds the listen address flag to the command
+func AddListenFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, listenFlag, "localhost:9000", "address to listen on", false)
+}
+
+// GetListenFlagValue gets the listen address flag from the command
+func GetListenFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(listenFlag)
+}
+
+// AddSlashingDBFlag adds the slashing protection db flag to the command
+func AddSlashingDBFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, slashingDBFlag, "", "bolt db file persisting the slashing protection history", true)
+}
+
+// GetSlashingDBFlagValue gets the slashing protection db flag from the command
+func GetSlashingDBFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(slashingDBFlag)
+}
+
+// AddGenesisValidatorsRootFlag adds the genesis validators root flag to the command
+func AddGenesisValidatorsRootFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, genesisValidatorsRootFlag, "", "genesis validators root of the beacon chain, the network's by default. When known, signing domains are checked against the network's fork schedule", false)
+}
+
+// GetGenesisValidatorsRootFlagValue gets the genesis validators root flag from the command
+func GetGenesisValidatorsRootFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(genesisValidatorsRootFlag)
+}
+
+// AddStoragePasswordFlag adds the storage password flag to the command
+func AddStoragePasswordFlag(c *cobra.Command) {
+	cliflag.AddEnvVarPersistentFlag(c, storagePasswordFlag, storagePasswordEnvVar, "password the storage account keys are encrypted with, if they are", false)
+}
+
+// GetStoragePasswordFlagValue gets the storage password flag from the command
+func GetStoragePasswordFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(storagePasswordFlag)
+}
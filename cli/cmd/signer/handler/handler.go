@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"github.com/bloxapp/eth2-key-manager/cli/util/printer"
+)
+
+// Signer contains handler functions of the CLI commands related to the remote signer.
+type Signer struct {
+	printer printer.Printer
+}
+
+// New is the constructor of Signer handler.
+func New(printer printer.Printer) *Signer {
+	return &Signer{
+		printer: printer,
+	}
+}
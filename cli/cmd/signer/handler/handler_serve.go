@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	types "github.com/wealdtech/go-eth2-types/v2"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/signer/flag"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/server/http_signer"
+	prot "github.com/bloxapp/eth2-key-manager/slashing_protection"
+	"github.com/bloxapp/eth2-key-manager/stores/bolt"
+	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+	"github.com/bloxapp/eth2-key-manager/validator_signer"
+)
+
+// how long in-flight requests get to finish once a shutdown signal is received
+const shutdownTimeout = 10 * time.Second
+
+// Serve runs the HTTP remote signer until SIGINT or SIGTERM is received.
+func (h *Signer) Serve(cmd *cobra.Command, args []string) error {
+	err := types.InitBLS()
+	if err != nil {
+		return errors.Wrap(err, "failed to init BLS")
+	}
+
+	// Get storage flag.
+	storageFlagValue, err := flag.GetStorageFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the storage flag value")
+	}
+
+	// Get listen flag.
+	listenFlagValue, err := flag.GetListenFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the listen flag value")
+	}
+
+	// Get slashing db flag.
+	slashingDBFlagValue, err := flag.GetSlashingDBFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the slashing db flag value")
+	}
+
+	// Get genesis validators root flag.
+	genesisValidatorsRootFlagValue, err := flag.GetGenesisValidatorsRootFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the genesis validators root flag value")
+	}
+
+	// Get storage password flag.
+	storagePasswordFlagValue, err := flag.GetStoragePasswordFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the storage password flag value")
+	}
+
+	var genesisValidatorsRoot []byte
+	if len(genesisValidatorsRootFlagValue) > 0 {
+		genesisValidatorsRoot, err = hex.DecodeString(strings.TrimPrefix(genesisValidatorsRootFlagValue, "0x"))
+		if err != nil || len(genesisValidatorsRoot) != 32 {
+			return fmt.Errorf("invalid genesis validators root %s", genesisValidatorsRootFlagValue)
+		}
+	}
+
+	storageBytes, err := hex.DecodeString(storageFlagValue)
+	if err != nil {
+		return errors.Wrap(err, "failed to HEX decode storage")
+	}
+
+	// the network is read from the storage, encrypted account keys are decrypted on first use
+	store := in_memory.NewInMemStore(core.MainNetwork)
+	if len(storagePasswordFlagValue) > 0 {
+		store.SetEncryptor(keystorev4.New(), []byte(storagePasswordFlagValue))
+	}
+	if err := store.UnmarshalJSON(storageBytes); err != nil {
+		return errors.Wrap(err, "failed to JSON un-marshal storage")
+	}
+
+	wallet, err := store.OpenWallet()
+	if err != nil {
+		return errors.Wrap(err, "failed to open wallet")
+	}
+
+	// the slashing protection history must outlive the process, a restarted signer would sign anything otherwise
+	if len(slashingDBFlagValue) == 0 {
+		return fmt.Errorf("a slashing db is required to serve")
+	}
+	db, err := bolt.NewBoltStore(slashingDBFlagValue, store.Network())
+	if err != nil {
+		return errors.Wrap(err, "failed to open slashing db")
+	}
+	defer db.Close()
+
+	if genesisValidatorsRoot == nil {
+		genesisValidatorsRoot = store.Network().GenesisValidatorsRoot()
+	}
+	protector := prot.NewNormalProtection(db)
+	if err := mergeSlashingHistory(store, protector, genesisValidatorsRoot); err != nil {
+		return err
+	}
+
+	signer := validator_signer.NewSimpleSigner(wallet, protector)
+	if genesisValidatorsRoot != nil {
+		if err := signer.SetForkSchedule(store.Network(), genesisValidatorsRoot); err != nil {
+			return errors.Wrap(err, "failed to set the signer fork schedule")
+		}
+	}
+	handler := http_signer.NewServer(signer)
+	handler.OnDenied(func(pubKey []byte, err *core.SlashableError) {
+		verdicts, _ := json.Marshal(err.Verdicts)
+		h.printer.Text(fmt.Sprintf("refused to sign for %x: %s %s", pubKey, err, verdicts))
+	})
+	server := &http.Server{
+		Addr:    listenFlagValue,
+		Handler: handler.Handler(),
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+	h.printer.Text(fmt.Sprintf("signer listening on %s", listenFlagValue))
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case err := <-errs:
+		return errors.Wrap(err, "signer server failed")
+	case <-signals:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		return errors.Wrap(err, "failed to shutdown signer server")
+	}
+	return nil
+}
+
+// mergeSlashingHistory merges the slashing history kept in the storage into the slashing db, so nothing conflicting
+// with what was signed before the storage was served is signed. Records already in the db are kept.
+func mergeSlashingHistory(store *in_memory.InMemStore, protector *prot.NormalProtection, genesisValidatorsRoot []byte) error {
+	keys, err := store.ListSlashingKeys()
+	if err != nil {
+		return errors.Wrap(err, "failed to list the storage slashing keys")
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	history, err := prot.NewNormalProtection(store).ExportInterchange(genesisValidatorsRoot, keys, prot.InterchangeFormatComplete)
+	if err != nil {
+		return errors.Wrap(err, "failed to export the storage slashing history")
+	}
+	if err := protector.ImportInterchange(genesisValidatorsRoot, history); err != nil {
+		return errors.Wrap(err, "failed to merge the storage slashing history")
+	}
+	return nil
+}
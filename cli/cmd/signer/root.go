@@ -0,0 +1,17 @@
+package signer
+
+import (
+	"github.com/spf13/cobra"
+
+	keyvaultcmd "github.com/bloxapp/eth2-key-manager/cli/cmd"
+)
+
+// Command represents the remote signer related command.
+var Command = &cobra.Command{
+	Use:   "signer",
+	Short: "Run a remote signer",
+}
+
+func init() {
+	keyvaultcmd.RootCmd.AddCommand(Command)
+}
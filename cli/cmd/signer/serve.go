@@ -0,0 +1,31 @@
+package signer
+
+import (
+	"github.com/spf13/cobra"
+
+	rootcmd "github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/signer/flag"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/signer/handler"
+)
+
+// serveCmd represents the signer serve command.
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serves the wallet accounts over the Web3Signer eth2 HTTP API.",
+	Long:  `This command runs an HTTP remote signer for the storage accounts until interrupted.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		handler := handler.New(rootcmd.ResultPrinter)
+		return handler.Serve(cmd, args)
+	},
+}
+
+func init() {
+	// Define flags for the command.
+	flag.AddStorageFlag(serveCmd)
+	flag.AddListenFlag(serveCmd)
+	flag.AddSlashingDBFlag(serveCmd)
+	flag.AddGenesisValidatorsRootFlag(serveCmd)
+	flag.AddStoragePasswordFlag(serveCmd)
+
+	Command.AddCommand(serveCmd)
+}
@@ -0,0 +1,142 @@
+package signer_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/util/printer"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/stores/bolt"
+	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+	"github.com/bloxapp/eth2-key-manager/wallet_hd"
+)
+
+const emptyWalletStorage = "7b226163636f756e7473223a2237623764222c226174744d656d6f7279223a2237623764222c226e6574776f726b223a223664363136393665222c2270726f706f73616c4d656d6f7279223a2237623764222c2277616c6c6574223a2237623232363936343232336132323336363333383333333633353333333732643338333533363632326433343631363436333264363136313337333232643336363533393339333536363631333736363339333933393232326332323639366536343635373834643631373037303635373232323361376237643263323237343739373036353232336132323438343432323764227d"
+
+func TestSignerServe(t *testing.T) {
+	t.Run("Invalid storage", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"signer",
+			"serve",
+			"--storage=zz",
+			"--listen=localhost:0",
+			"--slashing-db=",
+		})
+		err := cmd.RootCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to HEX decode storage")
+	})
+
+	t.Run("Invalid slashing db path", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"signer",
+			"serve",
+			"--storage=" + emptyWalletStorage,
+			"--listen=localhost:0",
+			"--slashing-db=/not/existing/dir/slashing.db",
+		})
+		err := cmd.RootCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open slashing db")
+	})
+
+	t.Run("Missing slashing db", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"signer",
+			"serve",
+			"--storage=" + emptyWalletStorage,
+			"--listen=localhost:0",
+			"--slashing-db=",
+		})
+		err := cmd.RootCmd.Execute()
+		require.EqualError(t, err, "a slashing db is required to serve")
+	})
+
+	t.Run("Invalid listen address", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "signer-serve")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"signer",
+			"serve",
+			"--storage=" + emptyWalletStorage,
+			"--listen=localhost:-1",
+			"--slashing-db=" + filepath.Join(dir, "slashing.db"),
+		})
+		err = cmd.RootCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "signer server failed")
+	})
+
+	t.Run("Invalid genesis validators root", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"signer",
+			"serve",
+			"--storage=" + emptyWalletStorage,
+			"--listen=localhost:0",
+			"--slashing-db=",
+			"--genesis-validators-root=0x1234",
+		})
+		err := cmd.RootCmd.Execute()
+		require.EqualError(t, err, "invalid genesis validators root 0x1234")
+	})
+
+	t.Run("Storage slashing history is merged", func(t *testing.T) {
+		require.NoError(t, e2types.InitBLS())
+		store := in_memory.NewInMemStore(core.MainNetwork)
+		wallet := wallet_hd.NewHDWallet(&core.WalletContext{Storage: store})
+		require.NoError(t, store.SaveWallet(wallet))
+		seed, _ := hex.DecodeString("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+		account, err := wallet.CreateValidatorAccount(seed, nil)
+		require.NoError(t, err)
+		key := account.ValidatorPublicKey()
+		require.NoError(t, store.SaveProposal(key, &core.BeaconBlockHeader{Slot: 100, BodyRoot: []byte("A")}))
+		storage, err := store.MarshalJSON()
+		require.NoError(t, err)
+
+		dir, err := ioutil.TempDir("", "signer-serve")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"signer",
+			"serve",
+			"--storage=" + hex.EncodeToString(storage),
+			"--listen=localhost:-1",
+			"--slashing-db=" + filepath.Join(dir, "slashing.db"),
+			"--genesis-validators-root=",
+		})
+		require.Error(t, cmd.RootCmd.Execute())
+
+		db, err := bolt.NewBoltStore(filepath.Join(dir, "slashing.db"), core.MainNetwork)
+		require.NoError(t, err)
+		defer db.Close()
+		proposal, err := db.RetrieveProposal(key, 100)
+		require.NoError(t, err)
+		require.NotNil(t, proposal)
+		watermark, err := db.RetrieveWatermark(key)
+		require.NoError(t, err)
+		require.EqualValues(t, 100, *watermark.MinProposalSlot)
+	})
+}
@@ -0,0 +1,38 @@
+package validator
+
+import (
+	"github.com/spf13/cobra"
+
+	rootcmd "github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/validator/flag"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/validator/handler"
+)
+
+// blsToExecutionChangeCmd represents the bls-to-execution-change validator command.
+var blsToExecutionChangeCmd = &cobra.Command{
+	Use:   "bls-to-execution-change",
+	Short: "Signs BLS to execution changes.",
+	Long:  `This command signs, with the withdrawal keys derived from the seed, changes of the validators' BLS withdrawal credentials to the given execution address and writes them to a file, ready to be submitted to a beacon node.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		network, err := rootcmd.GetNetworkFlagValue(cmd)
+		if err != nil {
+			return err
+		}
+
+		handler := handler.New(rootcmd.ResultPrinter, ResultFactory, network)
+		return handler.BLSToExecutionChange(cmd, args)
+	},
+}
+
+func init() {
+	// Define flags for the command.
+	flag.AddStorageFlag(blsToExecutionChangeCmd)
+	flag.AddSeedFlag(blsToExecutionChangeCmd)
+	flag.AddValidatorsFlag(blsToExecutionChangeCmd)
+	flag.AddExecutionAddressFlag(blsToExecutionChangeCmd)
+	flag.AddGenesisValidatorsRootFlag(blsToExecutionChangeCmd)
+	flag.AddOutputFileFlag(blsToExecutionChangeCmd)
+	rootcmd.AddNetworkFlag(blsToExecutionChangeCmd)
+
+	Command.AddCommand(blsToExecutionChangeCmd)
+}
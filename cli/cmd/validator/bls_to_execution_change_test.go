@@ -0,0 +1,94 @@
+package validator_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/util/printer"
+)
+
+const executionAddress = "0x8ba1f109551bd432803012645ac136ddd64dba72"
+
+func TestValidatorBLSToExecutionChange(t *testing.T) {
+	storage, pubKey := walletStorage(t)
+	dir, err := ioutil.TempDir("", "bls-to-execution-change")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("Successfully sign a bls to execution change", func(t *testing.T) {
+		outputFile := filepath.Join(dir, "changes.json")
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"validator",
+			"bls-to-execution-change",
+			"--storage=" + storage,
+			"--seed=" + seedHex,
+			"--validators=" + pubKey + ":25",
+			"--execution-address=" + executionAddress,
+			"--genesis-validators-root=" + genesisValidatorsRoot,
+			"--output-file=" + outputFile,
+			"--network=test",
+		})
+		require.NoError(t, cmd.RootCmd.Execute())
+		require.EqualValues(t, outputFile, strings.TrimSpace(output.String()))
+
+		byts, err := ioutil.ReadFile(outputFile)
+		require.NoError(t, err)
+		var changes []struct {
+			Message   map[string]string `json:"message"`
+			Signature string            `json:"signature"`
+		}
+		require.NoError(t, json.Unmarshal(byts, &changes))
+		require.Len(t, changes, 1)
+		require.EqualValues(t, "25", changes[0].Message["validator_index"])
+		require.EqualValues(t, executionAddress, changes[0].Message["to_execution_address"])
+		require.Regexp(t, "^0x[0-9a-f]{96}$", changes[0].Message["from_bls_pubkey"])
+		require.Regexp(t, "^0x[0-9a-f]{192}$", changes[0].Signature)
+	})
+
+	t.Run("Wrong seed", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"validator",
+			"bls-to-execution-change",
+			"--storage=" + storage,
+			"--seed=" + strings.Repeat("ab", 32),
+			"--validators=" + pubKey + ":25",
+			"--execution-address=" + executionAddress,
+			"--genesis-validators-root=" + genesisValidatorsRoot,
+			"--output-file=" + filepath.Join(dir, "wrong.json"),
+			"--network=test",
+		})
+		err := cmd.RootCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "seed does not match the account's withdrawal key")
+	})
+
+	t.Run("Invalid validator", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"validator",
+			"bls-to-execution-change",
+			"--storage=" + storage,
+			"--seed=" + seedHex,
+			"--validators=" + pubKey,
+			"--execution-address=" + executionAddress,
+			"--genesis-validators-root=" + genesisValidatorsRoot,
+			"--output-file=" + filepath.Join(dir, "invalid.json"),
+			"--network=test",
+		})
+		err := cmd.RootCmd.Execute()
+		require.EqualError(t, err, "invalid validator "+pubKey+", expected <public-key>:<validator-index>")
+	})
+}
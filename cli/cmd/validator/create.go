@@ -48,6 +48,14 @@ func init() {
 	flag.AddWalletAddressFlag(createCmd)
 	flag.AddWalletPrivateKeyFlag(createCmd)
 	flag.AddWeb3AddrFlag(createCmd)
+	flag.AddWithdrawalAddressFlag(createCmd)
+	flag.AddAmountFlag(createCmd)
+	flag.AddDepositContractFlag(createCmd)
+	flag.AddOfflineFlag(createCmd)
+	flag.AddNonceFlag(createCmd)
+	flag.AddGasPriceFlag(createCmd)
+	flag.AddChainIDFlag(createCmd)
+	flag.AddOutputDirFlag(createCmd)
 	rootcmd.AddNetworkFlag(createCmd)
 
 	Command.AddCommand(createCmd)
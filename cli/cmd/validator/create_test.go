@@ -1,16 +1,24 @@
 package validator_test
 
 import (
+	"archive/zip"
 	"bytes"
 	"encoding/json"
 	"io"
+	"io/ioutil"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/require"
 
 	"github.com/bloxapp/eth2-key-manager/cli/cmd"
@@ -186,4 +194,86 @@ func TestValidatorCreate(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to HEX decode the given wallet address")
 	})
+	t.Run("successfully create offline deposit transactions", func(t *testing.T) {
+		outputDir, err := ioutil.TempDir("", "deposit-txs")
+		require.NoError(t, err)
+		defer os.RemoveAll(outputDir)
+
+		var resultOut bytes.Buffer
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		validator.ResultFactory = func(name string) (io.Writer, func(), error) {
+			return &resultOut, func() {}, nil
+		}
+		cmd.RootCmd.SetArgs([]string{
+			"validator",
+			"create",
+			"--wallet-private-key", walletPK,
+			"--validators-per-seed", "2",
+			"--seeds-count", "1",
+			"--offline",
+			"--nonce", "7",
+			"--gas-price", "20000000000",
+			"--chain-id", "5",
+			"--output-dir", outputDir,
+		})
+		err = cmd.RootCmd.Execute()
+		require.NoError(t, err)
+		require.NotEmpty(t, resultOut.String())
+
+		// the seed files aren't named like keystores, they hold the seed and not a validator key
+		results, err := zip.NewReader(bytes.NewReader(resultOut.Bytes()), int64(resultOut.Len()))
+		require.NoError(t, err)
+		require.Len(t, results.File, 2)
+		for _, file := range results.File {
+			require.Regexp(t, "^[a-z ]+/seed-m_12381_3600_[01]\\.json$", file.Name)
+		}
+
+		privKey, err := crypto.HexToECDSA(walletPK)
+		require.NoError(t, err)
+
+		files, err := ioutil.ReadDir(outputDir)
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+
+		nonces := make(map[uint64]bool)
+		for _, file := range files {
+			raw, err := ioutil.ReadFile(filepath.Join(outputDir, file.Name()))
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(string(raw), "0x"))
+
+			txBytes, err := hexutil.Decode(string(raw))
+			require.NoError(t, err)
+			tx := new(types.Transaction)
+			require.NoError(t, rlp.DecodeBytes(txBytes, tx))
+			require.EqualValues(t, common.HexToAddress("0x07b39F4fDE4A38bACe212b546dAc87C58DfE3fDC"), *tx.To())
+			require.EqualValues(t, new(big.Int).Mul(big.NewInt(32*1e9), big.NewInt(1e9)), tx.Value())
+			require.EqualValues(t, big.NewInt(20000000000), tx.GasPrice())
+
+			from, err := types.Sender(types.NewEIP155Signer(big.NewInt(5)), tx)
+			require.NoError(t, err)
+			require.EqualValues(t, crypto.PubkeyToAddress(privKey.PublicKey), from)
+			nonces[tx.Nonce()] = true
+		}
+		require.True(t, nonces[7])
+		require.True(t, nonces[8])
+	})
+
+	// zinken has no deposit chain ID to default to
+	t.Run("failed offline without chain ID", func(t *testing.T) {
+		cmd.RootCmd.SetArgs([]string{
+			"validator",
+			"create",
+			"--wallet-private-key", walletPK,
+			"--validators-per-seed", "1",
+			"--seeds-count", "1",
+			"--network", "zinken",
+			"--deposit-contract", "0x07b39F4fDE4A38bACe212b546dAc87C58DfE3fDC",
+			"--offline",
+			"--gas-price", "20000000000",
+			"--chain-id", "0",
+		})
+		err := cmd.RootCmd.Execute()
+		require.EqualError(t, err, "chain ID is required to sign offline transactions")
+	})
 }
@@ -0,0 +1,38 @@
+package validator
+
+import (
+	"github.com/spf13/cobra"
+
+	rootcmd "github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/validator/flag"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/validator/handler"
+)
+
+// exitCmd represents the exit validator command.
+var exitCmd = &cobra.Command{
+	Use:   "exit",
+	Short: "Signs a voluntary exit.",
+	Long:  `This command signs a voluntary exit for the given account and writes it to a file, ready to be submitted to a beacon node.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		network, err := rootcmd.GetNetworkFlagValue(cmd)
+		if err != nil {
+			return err
+		}
+
+		handler := handler.New(rootcmd.ResultPrinter, ResultFactory, network)
+		return handler.Exit(cmd, args)
+	},
+}
+
+func init() {
+	// Define flags for the command.
+	flag.AddStorageFlag(exitCmd)
+	flag.AddPublicKeyFlag(exitCmd)
+	flag.AddValidatorIndexFlag(exitCmd)
+	flag.AddEpochFlag(exitCmd)
+	flag.AddGenesisValidatorsRootFlag(exitCmd)
+	flag.AddOutputFileFlag(exitCmd)
+	rootcmd.AddNetworkFlag(exitCmd)
+
+	Command.AddCommand(exitCmd)
+}
@@ -0,0 +1,119 @@
+package validator_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	eth2keymanager "github.com/bloxapp/eth2-key-manager"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/util/printer"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+)
+
+const (
+	genesisValidatorsRoot = "0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673"
+	seedHex               = "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff"
+)
+
+// walletStorage returns a hex encoded storage holding one account and its public key
+func walletStorage(t *testing.T) (string, string) {
+	require.NoError(t, e2types.InitBLS())
+	seed, _ := hex.DecodeString(seedHex)
+	store := in_memory.NewInMemStore(core.TestNetwork)
+	options := &eth2keymanager.KeyVaultOptions{}
+	options.SetStorage(store)
+	options.SetSeed(seed)
+	vault, err := eth2keymanager.NewKeyVault(options)
+	require.NoError(t, err)
+	wallet, err := vault.Wallet()
+	require.NoError(t, err)
+	account, err := wallet.CreateValidatorAccount(seed, nil)
+	require.NoError(t, err)
+
+	byts, err := store.MarshalJSON()
+	require.NoError(t, err)
+	return hex.EncodeToString(byts), hex.EncodeToString(account.ValidatorPublicKey().Marshal())
+}
+
+func TestValidatorExit(t *testing.T) {
+	storage, pubKey := walletStorage(t)
+	dir, err := ioutil.TempDir("", "exit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("Successfully sign a voluntary exit", func(t *testing.T) {
+		outputFile := filepath.Join(dir, "exit.json")
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"validator",
+			"exit",
+			"--storage=" + storage,
+			"--public-key=" + pubKey,
+			"--validator-index=25",
+			"--epoch=1024",
+			"--genesis-validators-root=" + genesisValidatorsRoot,
+			"--output-file=" + outputFile,
+			"--network=test",
+		})
+		require.NoError(t, cmd.RootCmd.Execute())
+		require.EqualValues(t, outputFile, strings.TrimSpace(output.String()))
+
+		byts, err := ioutil.ReadFile(outputFile)
+		require.NoError(t, err)
+		exit := struct {
+			Message   map[string]string `json:"message"`
+			Signature string            `json:"signature"`
+		}{}
+		require.NoError(t, json.Unmarshal(byts, &exit))
+		require.EqualValues(t, map[string]string{"epoch": "1024", "validator_index": "25"}, exit.Message)
+		require.Regexp(t, "^0x[0-9a-f]{192}$", exit.Signature)
+	})
+
+	t.Run("Unknown public key", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"validator",
+			"exit",
+			"--storage=" + storage,
+			"--public-key=" + strings.Repeat("ab", 48),
+			"--validator-index=25",
+			"--epoch=1024",
+			"--genesis-validators-root=" + genesisValidatorsRoot,
+			"--output-file=" + filepath.Join(dir, "unknown.json"),
+			"--network=test",
+		})
+		err := cmd.RootCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to sign voluntary exit")
+	})
+
+	t.Run("Invalid genesis validators root", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"validator",
+			"exit",
+			"--storage=" + storage,
+			"--public-key=" + pubKey,
+			"--validator-index=25",
+			"--epoch=1024",
+			"--genesis-validators-root=0x1234",
+			"--output-file=" + filepath.Join(dir, "invalid.json"),
+			"--network=test",
+		})
+		err := cmd.RootCmd.Execute()
+		require.EqualError(t, err, "invalid genesis validators root 0x1234")
+	})
+}
@@ -0,0 +1,44 @@
+package flag
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bloxapp/eth2-key-manager/cli/util/cliflag"
+)
+
+// Flag names.
+const (
+	seedFlag             = "seed"
+	validatorsFlag       = "validators"
+	executionAddressFlag = "execution-address"
+)
+
+// AddSeedFlag adds the seed flag to the command
+func AddSeedFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, seedFlag, "", "key-vault seed the accounts were derived from", true)
+}
+
+// GetSeedFlagValue gets the seed flag from the command
+func GetSeedFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(seedFlag)
+}
+
+// AddValidatorsFlag adds the validators flag to the command
+func AddValidatorsFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringSliceFlag(c, validatorsFlag, nil, "validators to change as <public-key>:<validator-index> pairs", true)
+}
+
+// GetValidatorsFlagValue gets the validators flag from the command
+func GetValidatorsFlagValue(c *cobra.Command) ([]string, error) {
+	return c.Flags().GetStringSlice(validatorsFlag)
+}
+
+// AddExecutionAddressFlag adds the execution address flag to the command
+func AddExecutionAddressFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, executionAddressFlag, "", "execution address to withdraw to", true)
+}
+
+// GetExecutionAddressFlagValue gets the execution address flag from the command
+func GetExecutionAddressFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(executionAddressFlag)
+}
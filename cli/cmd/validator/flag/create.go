@@ -13,11 +13,20 @@ const (
 	walletAddrFlag        = "wallet-addr"
 	walletPrivateKeyFlag  = "wallet-private-key"
 	web3AddrFlag          = "web3-addr"
+	withdrawalAddrFlag    = "withdrawal-address"
+	amountFlag            = "amount"
+	depositContractFlag   = "deposit-contract"
+	offlineFlag           = "offline"
+	nonceFlag             = "nonce"
+	gasPriceFlag          = "gas-price"
+	chainIDFlag           = "chain-id"
+	outputDirFlag         = "output-dir"
 )
 
 // Default values
 const (
 	web3AddrDefault = "https://goerli.prylabs.net"
+	amountDefault   = "32"
 )
 
 // AddSeedsCountFlag adds the seeds count flag to the command
@@ -52,7 +61,7 @@ func GetWalletPrivateKeyFlagValue(c *cobra.Command) (string, error) {
 
 // AddWalletAddressFlag adds the wallet address flag to the command
 func AddWalletAddressFlag(c *cobra.Command) {
-	cliflag.AddPersistentStringFlag(c, walletAddrFlag, "", "ETH wallet address", true)
+	cliflag.AddPersistentStringFlag(c, walletAddrFlag, "", "ETH wallet address, required unless --offline", false)
 }
 
 // GetWalletAddressFlagValue gets the wallet address flag from the command
@@ -69,3 +78,83 @@ func AddWeb3AddrFlag(c *cobra.Command) {
 func GetWeb3AddrFlagValue(c *cobra.Command) (string, error) {
 	return c.Flags().GetString(web3AddrFlag)
 }
+
+// AddWithdrawalAddressFlag adds the withdrawal address flag to the command
+func AddWithdrawalAddressFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, withdrawalAddrFlag, "", "execution address to withdraw to (0x01 credentials), the withdrawal key is used if not set", false)
+}
+
+// GetWithdrawalAddressFlagValue gets the withdrawal address flag from the command
+func GetWithdrawalAddressFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(withdrawalAddrFlag)
+}
+
+// AddAmountFlag adds the amount flag to the command
+func AddAmountFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, amountFlag, amountDefault, "ETH amount to deposit per validator, at least 1 ETH in whole gwei", false)
+}
+
+// GetAmountFlagValue gets the amount flag from the command
+func GetAmountFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(amountFlag)
+}
+
+// AddDepositContractFlag adds the deposit contract flag to the command
+func AddDepositContractFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, depositContractFlag, "", "deposit contract address, the network's by default", false)
+}
+
+// GetDepositContractFlagValue gets the deposit contract flag from the command
+func GetDepositContractFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(depositContractFlag)
+}
+
+// AddOfflineFlag adds the offline flag to the command
+func AddOfflineFlag(c *cobra.Command) {
+	cliflag.AddPersistentBoolFlag(c, offlineFlag, false, "write signed raw deposit transactions to --output-dir instead of sending them, no web3 endpoint is used", false)
+}
+
+// GetOfflineFlagValue gets the offline flag from the command
+func GetOfflineFlagValue(c *cobra.Command) (bool, error) {
+	return c.Flags().GetBool(offlineFlag)
+}
+
+// AddNonceFlag adds the nonce flag to the command
+func AddNonceFlag(c *cobra.Command) {
+	cliflag.AddPersistentIntFlag(c, nonceFlag, 0, "nonce of the first offline deposit transaction, the next ones are incremented", false)
+}
+
+// GetNonceFlagValue gets the nonce flag from the command
+func GetNonceFlagValue(c *cobra.Command) (int, error) {
+	return c.Flags().GetInt(nonceFlag)
+}
+
+// AddGasPriceFlag adds the gas price flag to the command
+func AddGasPriceFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, gasPriceFlag, "", "gas price in wei of offline deposit transactions", false)
+}
+
+// GetGasPriceFlagValue gets the gas price flag from the command
+func GetGasPriceFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(gasPriceFlag)
+}
+
+// AddChainIDFlag adds the chain ID flag to the command
+func AddChainIDFlag(c *cobra.Command) {
+	cliflag.AddPersistentIntFlag(c, chainIDFlag, 0, "eth1 chain ID offline deposit transactions are signed for, the network's by default", false)
+}
+
+// GetChainIDFlagValue gets the chain ID flag from the command
+func GetChainIDFlagValue(c *cobra.Command) (int, error) {
+	return c.Flags().GetInt(chainIDFlag)
+}
+
+// AddOutputDirFlag adds the output dir flag to the command
+func AddOutputDirFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, outputDirFlag, ".", "directory to write offline deposit transactions to", false)
+}
+
+// GetOutputDirFlagValue gets the output dir flag from the command
+func GetOutputDirFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(outputDirFlag)
+}
@@ -0,0 +1,77 @@
+package flag
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bloxapp/eth2-key-manager/cli/util/cliflag"
+)
+
+// Flag names.
+const (
+	storageFlag               = "storage"
+	publicKeyFlag             = "public-key"
+	validatorIndexFlag        = "validator-index"
+	epochFlag                 = "epoch"
+	genesisValidatorsRootFlag = "genesis-validators-root"
+	outputFileFlag            = "output-file"
+)
+
+// AddStorageFlag adds the storage flag to the command
+func AddStorageFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, storageFlag, "", "storage object", true)
+}
+
+// GetStorageFlagValue gets the storage flag from the command
+func GetStorageFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(storageFlag)
+}
+
+// AddPublicKeyFlag adds the public key flag to the command
+func AddPublicKeyFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, publicKeyFlag, "", "public key of the validator to exit", true)
+}
+
+// GetPublicKeyFlagValue gets the public key flag from the command
+func GetPublicKeyFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(publicKeyFlag)
+}
+
+// AddValidatorIndexFlag adds the validator index flag to the command
+func AddValidatorIndexFlag(c *cobra.Command) {
+	cliflag.AddPersistentIntFlag(c, validatorIndexFlag, 0, "beacon chain index of the validator", true)
+}
+
+// GetValidatorIndexFlagValue gets the validator index flag from the command
+func GetValidatorIndexFlagValue(c *cobra.Command) (int, error) {
+	return c.Flags().GetInt(validatorIndexFlag)
+}
+
+// AddEpochFlag adds the epoch flag to the command
+func AddEpochFlag(c *cobra.Command) {
+	cliflag.AddPersistentIntFlag(c, epochFlag, 0, "earliest epoch the exit can be processed at", true)
+}
+
+// GetEpochFlagValue gets the epoch flag from the command
+func GetEpochFlagValue(c *cobra.Command) (int, error) {
+	return c.Flags().GetInt(epochFlag)
+}
+
+// AddGenesisValidatorsRootFlag adds the genesis validators root flag to the command
+func AddGenesisValidatorsRootFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, genesisValidatorsRootFlag, "", "genesis validators root of the beacon chain, the network's by default", false)
+}
+
+// GetGenesisValidatorsRootFlagValue gets the genesis validators root flag from the command
+func GetGenesisValidatorsRootFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(genesisValidatorsRootFlag)
+}
+
+// AddOutputFileFlag adds the output file flag to the command
+func AddOutputFileFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, outputFileFlag, "", "file to write the signed message(s) to", true)
+}
+
+// GetOutputFileFlagValue gets the output file flag from the command
+func GetOutputFileFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(outputFileFlag)
+}
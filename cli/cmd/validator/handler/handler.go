@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"encoding/hex"
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/bloxapp/eth2-key-manager/cli/util/printer"
 	"github.com/bloxapp/eth2-key-manager/core"
@@ -25,3 +28,19 @@ func New(printer printer.Printer, resultWriterFactory ResultWriterFactory, netwo
 		network:             network,
 	}
 }
+
+// genesisValidatorsRoot decodes the genesis validators root flag value, the network's root is used when not set.
+func (h *Handler) genesisValidatorsRoot(flagValue string) ([]byte, error) {
+	if len(flagValue) == 0 {
+		if root := h.network.GenesisValidatorsRoot(); len(root) > 0 {
+			return root, nil
+		}
+		return nil, fmt.Errorf("genesis validators root is required for network %s", h.network)
+	}
+
+	root, err := hex.DecodeString(strings.TrimPrefix(flagValue, "0x"))
+	if err != nil || len(root) != 32 {
+		return nil, fmt.Errorf("invalid genesis validators root %s", flagValue)
+	}
+	return root, nil
+}
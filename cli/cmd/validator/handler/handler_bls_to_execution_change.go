@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/validator/flag"
+	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+	"github.com/bloxapp/eth2-key-manager/wallet_hd"
+)
+
+// BLSToExecutionChange is the handler to sign BLS to execution changes and write them, as a batch, to a file.
+func (h *Handler) BLSToExecutionChange(cmd *cobra.Command, args []string) error {
+	err := types.InitBLS()
+	if err != nil {
+		return errors.Wrap(err, "failed to init BLS")
+	}
+
+	// Get storage flag.
+	storageFlagValue, err := flag.GetStorageFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the storage flag value")
+	}
+
+	// Get seed flag.
+	seedFlagValue, err := flag.GetSeedFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the seed flag value")
+	}
+
+	// Get validators flag.
+	validatorsFlagValue, err := flag.GetValidatorsFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the validators flag value")
+	}
+
+	// Get execution address flag.
+	executionAddressFlagValue, err := flag.GetExecutionAddressFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the execution address flag value")
+	}
+
+	// Get genesis validators root flag.
+	genesisValidatorsRootFlagValue, err := flag.GetGenesisValidatorsRootFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the genesis validators root flag value")
+	}
+
+	// Get output file flag.
+	outputFileFlagValue, err := flag.GetOutputFileFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the output file flag value")
+	}
+
+	validatorIndices := make(map[string]uint64)
+	for _, validator := range validatorsFlagValue {
+		parts := strings.Split(validator, ":")
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid validator %s, expected <public-key>:<validator-index>", validator)
+		}
+		index, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid validator index %s", parts[1])
+		}
+		validatorIndices[strings.TrimPrefix(parts[0], "0x")] = index
+	}
+
+	executionAddress, err := hex.DecodeString(strings.TrimPrefix(executionAddressFlagValue, "0x"))
+	if err != nil || len(executionAddress) != 20 {
+		return fmt.Errorf("invalid execution address %s", executionAddressFlagValue)
+	}
+
+	genesisValidatorsRoot, err := h.genesisValidatorsRoot(genesisValidatorsRootFlagValue)
+	if err != nil {
+		return err
+	}
+
+	seed, err := hex.DecodeString(seedFlagValue)
+	if err != nil {
+		return errors.Wrap(err, "failed to HEX decode seed")
+	}
+
+	storageBytes, err := hex.DecodeString(storageFlagValue)
+	if err != nil {
+		return errors.Wrap(err, "failed to HEX decode storage")
+	}
+
+	store := in_memory.NewInMemStore(h.network)
+	if err := store.UnmarshalJSON(storageBytes); err != nil {
+		return errors.Wrap(err, "failed to JSON un-marshal storage")
+	}
+
+	wallet, err := store.OpenWallet()
+	if err != nil {
+		return errors.Wrap(err, "failed to open wallet")
+	}
+	hdWallet, ok := wallet.(*wallet_hd.HDWallet)
+	if !ok {
+		return fmt.Errorf("wallet is not a hierarchical deterministic wallet")
+	}
+
+	changes, err := hdWallet.SignBLSToExecutionChanges(seed, validatorIndices, executionAddress, genesisValidatorsRoot)
+	if err != nil {
+		return errors.Wrap(err, "failed to sign bls to execution changes")
+	}
+
+	byts, err := json.Marshal(changes)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal signed bls to execution changes")
+	}
+	if err := ioutil.WriteFile(outputFileFlagValue, byts, 0644); err != nil {
+		return errors.Wrap(err, "failed to write signed bls to execution changes")
+	}
+
+	h.printer.Text(outputFileFlagValue)
+	return nil
+}
@@ -29,11 +29,11 @@ import (
 	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
 )
 
-const (
-	depositAddress = "0x07b39F4fDE4A38bACe212b546dAc87C58DfE3fDC"
-)
+// depositGasLimit is the gas limit of deposit transactions
+const depositGasLimit = 500000
 
-// ValidatorConfig represents the validator config data
+// ValidatorConfig represents the validator config data, Crypto is the seed encrypted with an empty password (not an
+// EIP-2335 keystore of the validator key) so it's written as a seed file.
 type ValidatorConfig struct {
 	UUID    string                 `json:"uuid"`
 	Crypto  map[string]interface{} `json:"crypto"`
@@ -74,18 +74,64 @@ func (h *Handler) Create(cmd *cobra.Command, args []string) error {
 		return errors.Wrap(err, "failed to get web3 address flag value")
 	}
 
-	// Initialize connection with web3 API
-	rpcClient, err := rpc.Dial(web3Addr)
+	// Get withdrawal address
+	withdrawalAddress, err := flag.GetWithdrawalAddressFlagValue(cmd)
 	if err != nil {
-		return errors.Wrap(err, "failed to create connection with web3 API")
+		return errors.Wrap(err, "failed to get withdrawal address flag value")
+	}
+	var executionAddress []byte
+	if len(withdrawalAddress) > 0 {
+		if executionAddress, err = eth1_deposit.ParseExecutionAddress(withdrawalAddress); err != nil {
+			return errors.Wrap(err, "invalid withdrawal address")
+		}
 	}
-	defer rpcClient.Close()
-	client := ethclient.NewClient(rpcClient)
 
-	// Fetch wallet balance
-	walletBalance, err := h.getWalletBalance(client, walletAddress)
+	// Get deposit amount
+	amount, err := flag.GetAmountFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to get amount flag value")
+	}
+	amountInGwei, err := eth1_deposit.ParseDepositAmount(amount)
 	if err != nil {
-		return errors.Wrap(err, "failed to get wallet balance")
+		return err
+	}
+	if err := eth1_deposit.ValidateDepositAmount(amountInGwei); err != nil {
+		return err
+	}
+
+	// Get deposit contract address
+	depositContractAddress, err := flag.GetDepositContractFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to get deposit contract flag value")
+	}
+	if len(depositContractAddress) == 0 {
+		if depositContractAddress = h.network.DepositContractAddress(); len(depositContractAddress) == 0 {
+			return fmt.Errorf("deposit contract address is required for network %s", h.network)
+		}
+	}
+	if !common.IsHexAddress(depositContractAddress) {
+		return fmt.Errorf("invalid deposit contract address %s", depositContractAddress)
+	}
+
+	// Get offline flag
+	offline, err := flag.GetOfflineFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to get offline flag value")
+	}
+
+	var deposit depositFunc
+	if offline {
+		deposit, err = h.offlineDeposit(cmd, walletPrivateKey, common.HexToAddress(depositContractAddress), executionAddress, amountInGwei)
+		if err != nil {
+			return err
+		}
+	} else {
+		var closeClient func()
+		deposit, closeClient, err = h.liveDeposit(web3Addr, walletAddress, walletPrivateKey, common.HexToAddress(depositContractAddress), executionAddress, amountInGwei, seedsCount*validatorsPerSeed)
+		if err != nil {
+			return err
+		}
+		defer closeClient()
 	}
 
 	store := in_memory.NewInMemStore(h.network)
@@ -101,26 +147,6 @@ func (h *Handler) Create(cmd *cobra.Command, args []string) error {
 		return errors.Wrap(err, "failed to open wallet")
 	}
 
-	// Check balance
-	minBalance := big.NewInt(0).
-		SetUint64(eth1_deposit.MaxEffectiveBalanceInGwei * uint64(seedsCount) * uint64(validatorsPerSeed))
-	minBalance = minBalance.Mul(minBalance, big.NewInt(1e9))
-	if walletBalance.Cmp(minBalance) < 0 {
-		return errors.New("insufficient funds for transfer")
-	}
-
-	// Create deposit contract client
-	depositContract, err := contracts.NewDepositContract(common.HexToAddress(depositAddress), client)
-	if err != nil {
-		return err
-	}
-
-	// Create transaction options
-	txOpts, err := buildTransactionOpts(walletPrivateKey)
-	if err != nil {
-		return errors.Wrap(err, "failed to build transaction options")
-	}
-
 	// Generate seed
 	encryptor := keystorev4.New()
 	seedToAccounts := make(map[string][]ValidatorConfig)
@@ -159,7 +185,7 @@ func (h *Handler) Create(cmd *cobra.Command, args []string) error {
 			}
 
 			// Make transaction
-			if err := h.makeTransaction(depositContract, txOpts, account); err != nil {
+			if err := deposit(account); err != nil {
 				return errors.Wrap(err, "failed to make deposit")
 			}
 
@@ -179,6 +205,57 @@ func (h *Handler) Create(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// depositFunc makes the deposit of an account
+type depositFunc func(account core.ValidatorAccount) error
+
+// liveDeposit checks the wallet can fund validatorsCount deposits and returns a depositFunc sending them through the
+// web3 endpoint, the returned func closes the web3 connection.
+func (h *Handler) liveDeposit(web3Addr string, walletAddress string, walletPrivateKey string, depositContractAddress common.Address, executionAddress []byte, amountInGwei uint64, validatorsCount int) (depositFunc, func(), error) {
+	if len(walletAddress) == 0 {
+		return nil, nil, errors.New("wallet address is required to send deposits")
+	}
+
+	// Initialize connection with web3 API
+	rpcClient, err := rpc.Dial(web3Addr)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create connection with web3 API")
+	}
+	client := ethclient.NewClient(rpcClient)
+
+	// Fetch wallet balance
+	walletBalance, err := h.getWalletBalance(client, walletAddress)
+	if err != nil {
+		rpcClient.Close()
+		return nil, nil, errors.Wrap(err, "failed to get wallet balance")
+	}
+
+	// Check balance
+	minBalance := eth1_deposit.DepositValueInWei(amountInGwei)
+	minBalance = minBalance.Mul(minBalance, big.NewInt(int64(validatorsCount)))
+	if walletBalance.Cmp(minBalance) < 0 {
+		rpcClient.Close()
+		return nil, nil, errors.New("insufficient funds for transfer")
+	}
+
+	// Create deposit contract client
+	depositContract, err := contracts.NewDepositContract(depositContractAddress, client)
+	if err != nil {
+		rpcClient.Close()
+		return nil, nil, err
+	}
+
+	// Create transaction options
+	txOpts, err := buildTransactionOpts(walletPrivateKey, amountInGwei)
+	if err != nil {
+		rpcClient.Close()
+		return nil, nil, errors.Wrap(err, "failed to build transaction options")
+	}
+
+	return func(account core.ValidatorAccount) error {
+		return h.makeTransaction(depositContract, txOpts, account, executionAddress, amountInGwei)
+	}, rpcClient.Close, nil
+}
+
 func (h *Handler) getWalletBalance(client *ethclient.Client, walletAddr string) (*big.Int, error) {
 	address, err := hex.DecodeString(walletAddr)
 	if err != nil {
@@ -193,9 +270,9 @@ func (h *Handler) getWalletBalance(client *ethclient.Client, walletAddr string)
 	return res, nil
 }
 
-func (h *Handler) makeTransaction(depositContract *contracts.DepositContract, txOpts *bind.TransactOpts, account core.ValidatorAccount) error {
+func (h *Handler) makeTransaction(depositContract *contracts.DepositContract, txOpts *bind.TransactOpts, account core.ValidatorAccount, executionAddress []byte, amountInGwei uint64) error {
 	// Get deposit data for account
-	depositData, err := account.GetDepositData()
+	depositData, err := account.GetDepositData(executionAddress, amountInGwei)
 	if err != nil {
 		return errors.Wrap(err, "failed to get deposit data")
 	}
@@ -260,7 +337,7 @@ func (h *Handler) writeResultToFiles(results map[string][]ValidatorConfig) error
 	for mnemonic, validators := range results {
 		for _, validator := range validators {
 			// Create file
-			f, err := w.Create(mnemonic + "/keystore-" + strings.ReplaceAll(validator.Path, "/", "_") + ".json")
+			f, err := w.Create(mnemonic + "/seed-" + strings.ReplaceAll(validator.Path, "/", "_") + ".json")
 			if err != nil {
 				return errors.Wrap(err, "failed to create result file")
 			}
@@ -280,7 +357,8 @@ func (h *Handler) writeResultToFiles(results map[string][]ValidatorConfig) error
 	return nil
 }
 
-func buildTransactionOpts(privateKey string) (*bind.TransactOpts, error) {
+// buildTransactionOpts builds the options of deposit transactions, each depositing amountInGwei.
+func buildTransactionOpts(privateKey string, amountInGwei uint64) (*bind.TransactOpts, error) {
 	// User inputs private key, sign tx with private key
 	privKey, err := crypto.HexToECDSA(privateKey)
 	if err != nil {
@@ -288,7 +366,7 @@ func buildTransactionOpts(privateKey string) (*bind.TransactOpts, error) {
 	}
 
 	txOps := bind.NewKeyedTransactor(privKey)
-	txOps.Value = new(big.Int).Mul(big.NewInt(int64(eth1_deposit.MaxEffectiveBalanceInGwei)), big.NewInt(1e9))
-	txOps.GasLimit = 500000
+	txOps.Value = eth1_deposit.DepositValueInWei(amountInGwei)
+	txOps.GasLimit = depositGasLimit
 	return txOps, nil
 }
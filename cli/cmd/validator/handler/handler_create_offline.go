@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	contracts "github.com/prysmaticlabs/prysm/contracts/deposit-contract"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/spf13/cobra"
+
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/validator/flag"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/eth1_deposit"
+)
+
+// offlineDeposit returns a depositFunc which, instead of sending deposits, signs them as raw eth1 transactions and
+// writes them (RLP-encoded, 0x prefixed hex) to the output dir so they can be broadcast from another machine.
+// Transactions take consecutive nonces starting at the nonce flag value.
+func (h *Handler) offlineDeposit(cmd *cobra.Command, walletPrivateKey string, depositContractAddress common.Address, executionAddress []byte, amountInGwei uint64) (depositFunc, error) {
+	// Get nonce
+	nonce, err := flag.GetNonceFlagValue(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get nonce flag value")
+	}
+
+	// Get gas price
+	gasPriceValue, err := flag.GetGasPriceFlagValue(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get gas price flag value")
+	}
+
+	// Get chain ID
+	chainID, err := flag.GetChainIDFlagValue(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get chain ID flag value")
+	}
+
+	// Get output dir
+	outputDir, err := flag.GetOutputDirFlagValue(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get output dir flag value")
+	}
+
+	if nonce < 0 {
+		return nil, fmt.Errorf("nonce can't be negative")
+	}
+	if chainID == 0 {
+		chainID = int(h.network.DepositChainID())
+	}
+	if chainID <= 0 {
+		return nil, fmt.Errorf("chain ID is required to sign offline transactions")
+	}
+	gasPrice, ok := new(big.Int).SetString(gasPriceValue, 10)
+	if !ok || gasPrice.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid gas price %s, expected a positive amount of wei", gasPriceValue)
+	}
+
+	privKey, err := crypto.HexToECDSA(walletPrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode private key")
+	}
+	signer := types.NewEIP155Signer(big.NewInt(int64(chainID)))
+
+	depositABI, err := abi.JSON(strings.NewReader(contracts.DepositContractABI))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse deposit contract ABI")
+	}
+
+	if err := os.MkdirAll(outputDir, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create output dir")
+	}
+
+	txNonce := uint64(nonce)
+	return func(account core.ValidatorAccount) error {
+		depositData, err := account.GetDepositData(executionAddress, amountInGwei)
+		if err != nil {
+			return errors.Wrap(err, "failed to get deposit data")
+		}
+
+		callData, err := depositCallData(depositABI, depositData)
+		if err != nil {
+			return err
+		}
+
+		tx := types.NewTransaction(
+			txNonce,
+			depositContractAddress,
+			eth1_deposit.DepositValueInWei(amountInGwei),
+			depositGasLimit,
+			gasPrice,
+			callData,
+		)
+		signedTx, err := types.SignTx(tx, signer, privKey)
+		if err != nil {
+			return errors.Wrap(err, "failed to sign deposit transaction")
+		}
+		rawTx, err := rlp.EncodeToBytes(signedTx)
+		if err != nil {
+			return errors.Wrap(err, "failed to RLP encode deposit transaction")
+		}
+
+		file := filepath.Join(outputDir, fmt.Sprintf("deposit_tx-%d-%s.txt", txNonce, depositData["publicKey"]))
+		if err := ioutil.WriteFile(file, []byte("0x"+hex.EncodeToString(rawTx)), 0644); err != nil {
+			return errors.Wrap(err, "failed to write deposit transaction")
+		}
+		txNonce++
+
+		h.printer.Text(file)
+		return nil
+	}, nil
+}
+
+// depositCallData encodes the deposit contract deposit(pubkey, withdrawal_credentials, signature, deposit_data_root)
+// call of the deposit data.
+func depositCallData(depositABI abi.ABI, depositData map[string]interface{}) ([]byte, error) {
+	publicKey, err := hex.DecodeString(depositData["publicKey"].(string))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to HEX decode account public key")
+	}
+	withdrawalCredentials, err := hex.DecodeString(depositData["withdrawalCredentials"].(string))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to HEX decode account withdrawal credentials")
+	}
+	signature, err := hex.DecodeString(depositData["signature"].(string))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to HEX decode account signature")
+	}
+	depositDataRoot, err := hex.DecodeString(depositData["depositDataRoot"].(string))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to HEX decode account deposit data root")
+	}
+
+	callData, err := depositABI.Pack("deposit", publicKey, withdrawalCredentials, signature, bytesutil.ToBytes32(depositDataRoot))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode deposit call data")
+	}
+	return callData, nil
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/validator/flag"
+	"github.com/bloxapp/eth2-key-manager/core"
+	prot "github.com/bloxapp/eth2-key-manager/slashing_protection"
+	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+	"github.com/bloxapp/eth2-key-manager/validator_signer"
+)
+
+// Exit is the handler to sign a voluntary exit and write it to a file.
+func (h *Handler) Exit(cmd *cobra.Command, args []string) error {
+	err := types.InitBLS()
+	if err != nil {
+		return errors.Wrap(err, "failed to init BLS")
+	}
+
+	// Get storage flag.
+	storageFlagValue, err := flag.GetStorageFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the storage flag value")
+	}
+
+	// Get public key flag.
+	publicKeyFlagValue, err := flag.GetPublicKeyFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the public key flag value")
+	}
+
+	// Get validator index flag.
+	validatorIndexFlagValue, err := flag.GetValidatorIndexFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the validator index flag value")
+	}
+
+	// Get epoch flag.
+	epochFlagValue, err := flag.GetEpochFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the epoch flag value")
+	}
+
+	// Get genesis validators root flag.
+	genesisValidatorsRootFlagValue, err := flag.GetGenesisValidatorsRootFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the genesis validators root flag value")
+	}
+
+	// Get output file flag.
+	outputFileFlagValue, err := flag.GetOutputFileFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the output file flag value")
+	}
+
+	if validatorIndexFlagValue < 0 || epochFlagValue < 0 {
+		return fmt.Errorf("validator index and epoch can't be negative")
+	}
+
+	genesisValidatorsRoot, err := h.genesisValidatorsRoot(genesisValidatorsRootFlagValue)
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := hex.DecodeString(strings.TrimPrefix(publicKeyFlagValue, "0x"))
+	if err != nil {
+		return errors.Wrap(err, "failed to HEX decode public key")
+	}
+
+	storageBytes, err := hex.DecodeString(storageFlagValue)
+	if err != nil {
+		return errors.Wrap(err, "failed to HEX decode storage")
+	}
+
+	store := in_memory.NewInMemStore(h.network)
+	if err := store.UnmarshalJSON(storageBytes); err != nil {
+		return errors.Wrap(err, "failed to JSON un-marshal storage")
+	}
+
+	wallet, err := store.OpenWallet()
+	if err != nil {
+		return errors.Wrap(err, "failed to open wallet")
+	}
+
+	// exits are not slashable
+	signer := validator_signer.NewSimpleSigner(wallet, &prot.NoProtection{})
+	exit := &core.VoluntaryExit{
+		Epoch:          uint64(epochFlagValue),
+		ValidatorIndex: uint64(validatorIndexFlagValue),
+	}
+	forkInfo := &core.ForkInfo{
+		Fork:                  h.network.VoluntaryExitFork(exit.Epoch),
+		GenesisValidatorsRoot: genesisValidatorsRoot,
+	}
+	res, err := signer.SignVoluntaryExit(publicKey, exit, forkInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to sign voluntary exit")
+	}
+
+	byts, err := json.Marshal(core.NewSignedVoluntaryExit(exit, res.GetSignature()))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal signed voluntary exit")
+	}
+	if err := ioutil.WriteFile(outputFileFlagValue, byts, 0644); err != nil {
+		return errors.Wrap(err, "failed to write signed voluntary exit")
+	}
+
+	h.printer.Text(outputFileFlagValue)
+	return nil
+}
@@ -23,6 +23,8 @@ func init() {
 	// Define flags for the command.
 	flag.AddPublicKeyFlag(depositDataCmd)
 	flag.AddStorageFlag(depositDataCmd)
+	flag.AddWithdrawalAddressFlag(depositDataCmd)
+	flag.AddAmountFlag(depositDataCmd)
 	rootcmd.AddNetworkFlag(depositDataCmd)
 
 	Command.AddCommand(depositDataCmd)
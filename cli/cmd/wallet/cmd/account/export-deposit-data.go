@@ -0,0 +1,31 @@
+package account
+
+import (
+	"github.com/spf13/cobra"
+
+	rootcmd "github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/flag"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/handler"
+)
+
+// exportDepositDataCmd represents the export-deposit-data account command.
+var exportDepositDataCmd = &cobra.Command{
+	Use:   "export-deposit-data",
+	Short: "Exports accounts deposit data in the staking-deposit-cli format.",
+	Long:  `This command writes the deposit data of all accounts (or of the given public key) into a deposit_data-<timestamp>.json file, as the staking-deposit-cli does, in the output directory.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		handler := handler.New(rootcmd.ResultPrinter)
+		return handler.ExportDepositData(cmd, args)
+	},
+}
+
+func init() {
+	// Define flags for the command.
+	flag.AddOutputDirFlag(exportDepositDataCmd)
+	flag.AddOptionalPublicKeyFlag(exportDepositDataCmd)
+	flag.AddStorageFlag(exportDepositDataCmd)
+	flag.AddWithdrawalAddressFlag(exportDepositDataCmd)
+	flag.AddAmountFlag(exportDepositDataCmd)
+
+	Command.AddCommand(exportDepositDataCmd)
+}
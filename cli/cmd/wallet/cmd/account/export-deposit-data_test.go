@@ -0,0 +1,102 @@
+package account_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/util/printer"
+)
+
+func TestAccountExportAndVerifyDepositData(t *testing.T) {
+	storage, pubKeys := walletStorage(t, 2)
+	dir, err := ioutil.TempDir("", "deposit-data")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var file string
+	t.Run("Successfully export deposit data", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"export-deposit-data",
+			"--output-dir=" + dir,
+			"--storage=" + storage,
+		})
+		require.NoError(t, cmd.RootCmd.Execute())
+
+		file = strings.TrimSpace(output.String())
+		require.True(t, strings.HasPrefix(filepath.Base(file), "deposit_data-"))
+		byts, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		var depositData []map[string]interface{}
+		require.NoError(t, json.Unmarshal(byts, &depositData))
+		require.Len(t, depositData, 2)
+		for i, entry := range depositData {
+			require.EqualValues(t, pubKeys[len(pubKeys)-1-i], entry["pubkey"])
+			require.EqualValues(t, 32000000000, entry["amount"])
+			require.EqualValues(t, "00000001", entry["fork_version"])
+			require.EqualValues(t, "test", entry["network_name"])
+			require.NotEmpty(t, entry["deposit_message_root"])
+			require.NotEmpty(t, entry["deposit_data_root"])
+		}
+	})
+
+	t.Run("Successfully verify deposit data", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"verify-deposit-data",
+			"--file=" + file,
+			"--network=test",
+		})
+		require.NoError(t, cmd.RootCmd.Execute())
+		require.EqualValues(t, "2 deposits verified", strings.TrimSpace(output.String()))
+	})
+
+	t.Run("Deposit data of another network", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"verify-deposit-data",
+			"--file=" + file,
+			"--network=main",
+		})
+		err := cmd.RootCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "made for network test (fork version 00000001), not main")
+	})
+
+	t.Run("Tampered deposit data", func(t *testing.T) {
+		byts, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		tampered := filepath.Join(dir, "tampered.json")
+		require.NoError(t, ioutil.WriteFile(tampered, bytes.Replace(byts, []byte("32000000000"), []byte("31000000000"), 1), 0644))
+
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"verify-deposit-data",
+			"--file=" + tampered,
+			"--network=test",
+		})
+		err = cmd.RootCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "deposit_message_root mismatch")
+	})
+}
@@ -0,0 +1,31 @@
+package account
+
+import (
+	"github.com/spf13/cobra"
+
+	rootcmd "github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/flag"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/handler"
+)
+
+// exportCmd represents the export account command.
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports wallet accounts as EIP-2335 keystores.",
+	Long:  `This command writes a keystore file per account (or for the given public key) into the output directory.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		handler := handler.New(rootcmd.ResultPrinter)
+		return handler.Export(cmd, args)
+	},
+}
+
+func init() {
+	// Define flags for the command.
+	flag.AddOutputDirFlag(exportCmd)
+	flag.AddPasswordFlag(exportCmd)
+	flag.AddKDFFlag(exportCmd)
+	flag.AddOptionalPublicKeyFlag(exportCmd)
+	flag.AddStorageFlag(exportCmd)
+
+	Command.AddCommand(exportCmd)
+}
@@ -0,0 +1,164 @@
+package account_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	eth2keymanager "github.com/bloxapp/eth2-key-manager"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/util/printer"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+)
+
+// walletStorage returns a hex storage with count derived accounts and their public keys
+func walletStorage(t *testing.T, count int) (string, []string) {
+	require.NoError(t, e2types.InitBLS())
+	seed, _ := hex.DecodeString("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+	store := in_memory.NewInMemStore(core.TestNetwork)
+	options := &eth2keymanager.KeyVaultOptions{}
+	options.SetStorage(store)
+	options.SetSeed(seed)
+	vault, err := eth2keymanager.NewKeyVault(options)
+	require.NoError(t, err)
+	wallet, err := vault.Wallet()
+	require.NoError(t, err)
+
+	pubKeys := make([]string, 0)
+	for i := 0; i < count; i++ {
+		account, err := wallet.CreateValidatorAccount(seed, nil)
+		require.NoError(t, err)
+		pubKeys = append(pubKeys, hex.EncodeToString(account.ValidatorPublicKey().Marshal()))
+	}
+
+	byts, err := store.MarshalJSON()
+	require.NoError(t, err)
+	return hex.EncodeToString(byts), pubKeys
+}
+
+func TestAccountExport(t *testing.T) {
+	storage, pubKeys := walletStorage(t, 2)
+
+	t.Run("Successfully export all accounts", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "export")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"export",
+			"--output-dir=" + dir,
+			"--password=password",
+			"--kdf=pbkdf2",
+			"--public-key=",
+			"--storage=" + storage,
+		})
+		require.NoError(t, cmd.RootCmd.Execute())
+
+		var files []string
+		require.NoError(t, json.Unmarshal(output.Bytes(), &files))
+		require.Len(t, files, 2)
+		for _, file := range files {
+			byts, err := ioutil.ReadFile(file)
+			require.NoError(t, err)
+			keystore := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal(byts, &keystore))
+			require.EqualValues(t, 4, keystore["version"])
+			require.Contains(t, pubKeys, keystore["pubkey"])
+			require.Contains(t, file, "keystore-m_12381_3600_")
+
+			key, err := core.HDKeyFromKeystore(keystore, "password")
+			require.NoError(t, err)
+			require.EqualValues(t, keystore["pubkey"], hex.EncodeToString(key.PublicKey().Marshal()))
+			require.EqualValues(t, keystore["path"], key.Path())
+		}
+
+		// exported keystores can be imported back
+		output.Reset()
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"import",
+			"--keystore=" + dir,
+			"--password=password",
+			"--storage=" + emptyWalletStorage,
+		})
+		require.NoError(t, cmd.RootCmd.Execute())
+	})
+
+	t.Run("Successfully export a single account", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "export")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"export",
+			"--output-dir=" + dir,
+			"--password=password",
+			"--kdf=scrypt",
+			"--public-key=" + pubKeys[1],
+			"--storage=" + storage,
+		})
+		require.NoError(t, cmd.RootCmd.Execute())
+
+		var files []string
+		require.NoError(t, json.Unmarshal(output.Bytes(), &files))
+		require.Len(t, files, 1)
+	})
+
+	t.Run("Fail with an empty password", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "export")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"export",
+			"--output-dir=" + dir,
+			"--password=",
+			"--kdf=scrypt",
+			"--public-key=" + pubKeys[0],
+			"--storage=" + storage,
+		})
+		err = cmd.RootCmd.Execute()
+		require.EqualError(t, err, "failed to export account account-0: keystore password can't be empty")
+	})
+
+	t.Run("Fail with an unsupported kdf", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "export")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"export",
+			"--output-dir=" + dir,
+			"--password=password",
+			"--kdf=argon2",
+			"--public-key=" + pubKeys[0],
+			"--storage=" + storage,
+		})
+		err = cmd.RootCmd.Execute()
+		require.EqualError(t, err, "failed to export account account-0: unsupported kdf argon2, supported are scrypt and pbkdf2")
+	})
+}
@@ -8,7 +8,9 @@ import (
 
 // Flag names.
 const (
-	publicKeyFlag = "public-key"
+	publicKeyFlag         = "public-key"
+	withdrawalAddressFlag = "withdrawal-address"
+	amountFlag            = "amount"
 )
 
 // AddPublicKeyFlag adds the public key flag to the command
@@ -20,3 +22,23 @@ func AddPublicKeyFlag(c *cobra.Command) {
 func GetPublicKeyFlagValue(c *cobra.Command) (string, error) {
 	return c.Flags().GetString(publicKeyFlag)
 }
+
+// AddWithdrawalAddressFlag adds the withdrawal address flag to the command
+func AddWithdrawalAddressFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, withdrawalAddressFlag, "", "execution address to withdraw to (0x01 credentials), the withdrawal key is used if not set", false)
+}
+
+// GetWithdrawalAddressFlagValue gets the withdrawal address flag from the command
+func GetWithdrawalAddressFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(withdrawalAddressFlag)
+}
+
+// AddAmountFlag adds the amount flag to the command
+func AddAmountFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, amountFlag, "32", "ETH amount to deposit, at least 1 ETH in whole gwei (less than 32 for partial deposits or top-ups)", false)
+}
+
+// GetAmountFlagValue gets the amount flag from the command
+func GetAmountFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(amountFlag)
+}
@@ -0,0 +1,39 @@
+package flag
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bloxapp/eth2-key-manager/cli/util/cliflag"
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// Flag names.
+const (
+	outputDirFlag = "output-dir"
+	kdfFlag       = "kdf"
+)
+
+// AddOutputDirFlag adds the output directory flag to the command
+func AddOutputDirFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, outputDirFlag, "", "directory to write the files to", true)
+}
+
+// GetOutputDirFlagValue gets the output directory flag from the command
+func GetOutputDirFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(outputDirFlag)
+}
+
+// AddKDFFlag adds the kdf flag to the command
+func AddKDFFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, kdfFlag, core.KDFScrypt, "keystore key derivation function, scrypt or pbkdf2", false)
+}
+
+// GetKDFFlagValue gets the kdf flag from the command
+func GetKDFFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(kdfFlag)
+}
+
+// AddOptionalPublicKeyFlag adds a not required public key flag to the command
+func AddOptionalPublicKeyFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, publicKeyFlag, "", "public key, all accounts if not set", false)
+}
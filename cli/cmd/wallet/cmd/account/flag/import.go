@@ -0,0 +1,34 @@
+package flag
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bloxapp/eth2-key-manager/cli/util/cliflag"
+)
+
+// Flag names.
+const (
+	keystoreFlag   = "keystore"
+	passwordFlag   = "password"
+	passwordEnvVar = "KEYSTORE_PASSWORD"
+)
+
+// AddKeystoreFlag adds the keystore flag to the command
+func AddKeystoreFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, keystoreFlag, "", "EIP-2335 keystore file or a directory of keystore*.json files", true)
+}
+
+// GetKeystoreFlagValue gets the keystore flag from the command
+func GetKeystoreFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(keystoreFlag)
+}
+
+// AddPasswordFlag adds the keystore password flag to the command
+func AddPasswordFlag(c *cobra.Command) {
+	cliflag.AddEnvVarPersistentFlag(c, passwordFlag, passwordEnvVar, "keystore password", true)
+}
+
+// GetPasswordFlagValue gets the keystore password flag from the command
+func GetPasswordFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(passwordFlag)
+}
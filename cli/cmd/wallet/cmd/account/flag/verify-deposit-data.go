@@ -0,0 +1,22 @@
+package flag
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bloxapp/eth2-key-manager/cli/util/cliflag"
+)
+
+// Flag names.
+const (
+	fileFlag = "file"
+)
+
+// AddFileFlag adds the file flag to the command
+func AddFileFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, fileFlag, "", "deposit_data json file to verify", true)
+}
+
+// GetFileFlagValue gets the file flag from the command
+func GetFileFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(fileFlag)
+}
@@ -8,6 +8,7 @@ import (
 	types "github.com/wealdtech/go-eth2-types/v2"
 
 	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/flag"
+	"github.com/bloxapp/eth2-key-manager/eth1_deposit"
 	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
 )
 
@@ -30,6 +31,30 @@ func (h *Account) DepositData(cmd *cobra.Command, args []string) error {
 		return errors.Wrap(err, "failed to retrieve the storage flag value")
 	}
 
+	// Get withdrawal address flag.
+	withdrawalAddressFlagValue, err := flag.GetWithdrawalAddressFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the withdrawal address flag value")
+	}
+
+	// Get amount flag.
+	amountFlagValue, err := flag.GetAmountFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the amount flag value")
+	}
+
+	amountInGwei, err := eth1_deposit.ParseDepositAmount(amountFlagValue)
+	if err != nil {
+		return err
+	}
+
+	var executionAddress []byte
+	if len(withdrawalAddressFlagValue) > 0 {
+		if executionAddress, err = eth1_deposit.ParseExecutionAddress(withdrawalAddressFlagValue); err != nil {
+			return errors.Wrap(err, "invalid withdrawal address")
+		}
+	}
+
 	storageBytes, err := hex.DecodeString(storageFlagValue)
 	if err != nil {
 		return errors.Wrap(err, "failed to HEX decode storage")
@@ -51,7 +76,7 @@ func (h *Account) DepositData(cmd *cobra.Command, args []string) error {
 		return errors.Wrap(err, "failed to get account by public key")
 	}
 
-	depositData, err := account.GetDepositData()
+	depositData, err := account.GetDepositData(executionAddress, amountInGwei)
 	if err != nil {
 		return errors.Wrap(err, "failed to get deposit data")
 	}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/flag"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/eth1_deposit"
+	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+)
+
+// ExportDepositData writes the accounts deposit data in the staking-deposit-cli format and prints the file path.
+func (h *Account) ExportDepositData(cmd *cobra.Command, args []string) error {
+	err := types.InitBLS()
+	if err != nil {
+		return errors.Wrap(err, "failed to init BLS")
+	}
+
+	// Get output dir flag.
+	outputDirFlagValue, err := flag.GetOutputDirFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the output dir flag value")
+	}
+
+	// Get public key flag.
+	publicKeyFlagValue, err := flag.GetPublicKeyFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the public key flag value")
+	}
+
+	// Get storage flag.
+	storageFlagValue, err := flag.GetStorageFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the storage flag value")
+	}
+
+	// Get withdrawal address flag.
+	withdrawalAddressFlagValue, err := flag.GetWithdrawalAddressFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the withdrawal address flag value")
+	}
+
+	// Get amount flag.
+	amountFlagValue, err := flag.GetAmountFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the amount flag value")
+	}
+
+	amountInGwei, err := eth1_deposit.ParseDepositAmount(amountFlagValue)
+	if err != nil {
+		return err
+	}
+
+	var executionAddress []byte
+	if len(withdrawalAddressFlagValue) > 0 {
+		if executionAddress, err = eth1_deposit.ParseExecutionAddress(withdrawalAddressFlagValue); err != nil {
+			return errors.Wrap(err, "invalid withdrawal address")
+		}
+	}
+
+	storageBytes, err := hex.DecodeString(storageFlagValue)
+	if err != nil {
+		return errors.Wrap(err, "failed to HEX decode storage")
+	}
+
+	var store in_memory.InMemStore
+	if err := store.UnmarshalJSON(storageBytes); err != nil {
+		return errors.Wrap(err, "failed to JSON un-marshal storage")
+	}
+
+	wallet, err := store.OpenWallet()
+	if err != nil {
+		return errors.Wrap(err, "failed to open wallet")
+	}
+
+	accounts := wallet.Accounts()
+	if len(publicKeyFlagValue) > 0 {
+		account, err := wallet.AccountByPublicKey(publicKeyFlagValue)
+		if err != nil {
+			return errors.Wrap(err, "failed to get account by public key")
+		}
+		accounts = []core.ValidatorAccount{account}
+	}
+
+	depositData, err := eth1_deposit.ExportDepositData(accounts, store.Network(), executionAddress, amountInGwei)
+	if err != nil {
+		return errors.Wrap(err, "failed to export deposit data")
+	}
+
+	byts, err := json.Marshal(depositData)
+	if err != nil {
+		return errors.Wrap(err, "failed to JSON marshal deposit data")
+	}
+
+	if err := os.MkdirAll(outputDirFlagValue, 0700); err != nil {
+		return errors.Wrap(err, "failed to create output dir")
+	}
+	file := filepath.Join(outputDirFlagValue, eth1_deposit.DepositDataFileName(time.Now().Unix()))
+	if err := ioutil.WriteFile(file, byts, 0644); err != nil {
+		return errors.Wrap(err, "failed to write deposit data")
+	}
+
+	h.printer.Text(file)
+	return nil
+}
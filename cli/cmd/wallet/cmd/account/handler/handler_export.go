@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/flag"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+)
+
+// Account exports wallet accounts as EIP-2335 keystore files and prints their paths.
+func (h *Account) Export(cmd *cobra.Command, args []string) error {
+	err := types.InitBLS()
+	if err != nil {
+		return errors.Wrap(err, "failed to init BLS")
+	}
+
+	// Get output dir flag.
+	outputDirFlagValue, err := flag.GetOutputDirFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the output dir flag value")
+	}
+
+	// Get password flag.
+	passwordFlagValue, err := flag.GetPasswordFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the password flag value")
+	}
+
+	// Get kdf flag.
+	kdfFlagValue, err := flag.GetKDFFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the kdf flag value")
+	}
+
+	// Get public key flag.
+	publicKeyFlagValue, err := flag.GetPublicKeyFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the public key flag value")
+	}
+
+	// Get storage flag.
+	storageFlagValue, err := flag.GetStorageFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the storage flag value")
+	}
+
+	storageBytes, err := hex.DecodeString(storageFlagValue)
+	if err != nil {
+		return errors.Wrap(err, "failed to HEX decode storage")
+	}
+
+	var store in_memory.InMemStore
+	if err := store.UnmarshalJSON(storageBytes); err != nil {
+		return errors.Wrap(err, "failed to JSON un-marshal storage")
+	}
+
+	wallet, err := store.OpenWallet()
+	if err != nil {
+		return errors.Wrap(err, "failed to open wallet")
+	}
+
+	accounts := wallet.Accounts()
+	if len(publicKeyFlagValue) > 0 {
+		account, err := wallet.AccountByPublicKey(publicKeyFlagValue)
+		if err != nil {
+			return errors.Wrap(err, "failed to get account by public key")
+		}
+		accounts = []core.ValidatorAccount{account}
+	}
+
+	if err := os.MkdirAll(outputDirFlagValue, 0700); err != nil {
+		return errors.Wrap(err, "failed to create output dir")
+	}
+
+	files := make([]string, 0)
+	created := time.Now().Unix()
+	for _, account := range accounts {
+		keystore, err := account.ExportKeystore(passwordFlagValue, kdfFlagValue)
+		if err != nil {
+			return errors.Wrapf(err, "failed to export account %s", account.Name())
+		}
+
+		byts, err := json.Marshal(keystore)
+		if err != nil {
+			return errors.Wrap(err, "failed to JSON marshal keystore")
+		}
+
+		file := filepath.Join(outputDirFlagValue, keystoreFileName(keystore, created))
+		if err := ioutil.WriteFile(file, byts, 0600); err != nil {
+			return errors.Wrap(err, "failed to write keystore")
+		}
+		files = append(files, file)
+	}
+
+	err = h.printer.JSON(files)
+	if err != nil {
+		return errors.Wrap(err, "failed to print keystore files JSON")
+	}
+	return nil
+}
+
+// keystoreFileName names keystores like the eth2.0-deposit-cli does (keystore-m_12381_3600_0_0_0-<timestamp>.json),
+// keys without a path are named after their public key.
+func keystoreFileName(keystore map[string]interface{}, created int64) string {
+	name := keystore["pubkey"].(string)
+	if path, _ := keystore["path"].(string); len(path) > 0 {
+		name = strings.ReplaceAll(path, "/", "_")
+	}
+	return fmt.Sprintf("keystore-%s-%d.json", name, created)
+}
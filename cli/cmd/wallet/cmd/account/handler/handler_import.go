@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/flag"
+	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+)
+
+// keystore files as generated by the eth2.0-deposit-cli, e.g. keystore-m_12381_3600_0_0_0-1600000000.json
+const keystoreFilePattern = "keystore*.json"
+
+// Account imports accounts from EIP-2335 keystores and prints the storage.
+func (h *Account) Import(cmd *cobra.Command, args []string) error {
+	err := types.InitBLS()
+	if err != nil {
+		return errors.Wrap(err, "failed to init BLS")
+	}
+
+	// Get keystore flag.
+	keystoreFlagValue, err := flag.GetKeystoreFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the keystore flag value")
+	}
+
+	files, err := keystoreFiles(keystoreFlagValue)
+	if err != nil {
+		return err
+	}
+
+	// Get password flag.
+	passwordFlagValue, err := flag.GetPasswordFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the password flag value")
+	}
+
+	// Get storage flag.
+	storageFlagValue, err := flag.GetStorageFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the storage flag value")
+	}
+
+	storageBytes, err := hex.DecodeString(storageFlagValue)
+	if err != nil {
+		return errors.Wrap(err, "failed to HEX decode storage")
+	}
+
+	var store in_memory.InMemStore
+	if err := store.UnmarshalJSON(storageBytes); err != nil {
+		return errors.Wrap(err, "failed to JSON un-marshal storage")
+	}
+
+	wallet, err := store.OpenWallet()
+	if err != nil {
+		return errors.Wrap(err, "failed to open wallet")
+	}
+
+	for _, file := range files {
+		byts, err := ioutil.ReadFile(file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read keystore %s", file)
+		}
+		keystore := make(map[string]interface{})
+		if err := json.Unmarshal(byts, &keystore); err != nil {
+			return errors.Wrapf(err, "failed to JSON un-marshal keystore %s", file)
+		}
+		if _, err := wallet.ImportValidatorAccount(keystore, passwordFlagValue); err != nil {
+			return errors.Wrapf(err, "failed to import keystore %s", file)
+		}
+	}
+
+	// marshal storage
+	bytes, err := store.MarshalJSON()
+	if err != nil {
+		return errors.Wrap(err, "failed to JSON marshal storage")
+	}
+
+	h.printer.Text(hex.EncodeToString(bytes))
+	return nil
+}
+
+// keystoreFiles returns path if it's a file, otherwise the keystore files in it
+func keystoreFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find keystore")
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, keystoreFilePattern))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list keystores")
+	}
+	if len(files) == 0 {
+		return nil, errors.Errorf("no %s files found in %s", keystoreFilePattern, path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -46,7 +46,10 @@ func (h *Account) List(cmd *cobra.Command, args []string) error {
 			"id":               a.ID().String(),
 			"name":             a.Name(),
 			"validationPubKey": hex.EncodeToString(a.ValidatorPublicKey().Marshal()),
-			"withdrawalPubKey": hex.EncodeToString(a.WithdrawalPublicKey().Marshal()),
+		}
+		// imported accounts have no withdrawal key
+		if a.WithdrawalPublicKey() != nil {
+			accObj["withdrawalPubKey"] = hex.EncodeToString(a.WithdrawalPublicKey().Marshal())
 		}
 		accounts = append(accounts, accObj)
 	}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/flag"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/eth1_deposit"
+)
+
+// VerifyDepositData verifies a staking-deposit-cli deposit data file was made for the network and prints how many
+// deposits it holds.
+func (h *Account) VerifyDepositData(cmd *cobra.Command, args []string, network core.Network) error {
+	err := types.InitBLS()
+	if err != nil {
+		return errors.Wrap(err, "failed to init BLS")
+	}
+
+	// Get file flag.
+	fileFlagValue, err := flag.GetFileFlagValue(cmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the file flag value")
+	}
+
+	byts, err := ioutil.ReadFile(fileFlagValue)
+	if err != nil {
+		return errors.Wrap(err, "failed to read deposit data file")
+	}
+
+	var depositData []*eth1_deposit.DepositDataJSON
+	if err := json.Unmarshal(byts, &depositData); err != nil {
+		return errors.Wrap(err, "failed to JSON un-marshal deposit data")
+	}
+
+	if err := eth1_deposit.VerifyDepositData(depositData, network); err != nil {
+		return err
+	}
+
+	h.printer.Text(fmt.Sprintf("%d deposits verified", len(depositData)))
+	return nil
+}
@@ -0,0 +1,29 @@
+package account
+
+import (
+	"github.com/spf13/cobra"
+
+	rootcmd "github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/flag"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/handler"
+)
+
+// importCmd represents the import account command.
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Imports wallet accounts from EIP-2335 keystores.",
+	Long:  `This command imports accounts from a keystore file (or a directory of keystore*.json files) into the storage.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		handler := handler.New(rootcmd.ResultPrinter)
+		return handler.Import(cmd, args)
+	},
+}
+
+func init() {
+	// Define flags for the command.
+	flag.AddKeystoreFlag(importCmd)
+	flag.AddPasswordFlag(importCmd)
+	flag.AddStorageFlag(importCmd)
+
+	Command.AddCommand(importCmd)
+}
@@ -0,0 +1,136 @@
+package account_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+
+	"github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/util/printer"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+)
+
+const emptyWalletStorage = "7b226163636f756e7473223a2237623764222c226174744d656d6f7279223a2237623764222c226e6574776f726b223a223664363136393665222c2270726f706f73616c4d656d6f7279223a2237623764222c2277616c6c6574223a2237623232363936343232336132323336363333383333333633353333333732643338333533363632326433343631363436333264363136313337333232643336363533393339333536363631333736363339333933393232326332323639366536343635373834643631373037303635373232323361376237643263323237343739373036353232336132323438343432323764227d"
+
+// writeKeystores writes count keystores, named like the eth2.0-deposit-cli does, and returns their public keys
+func writeKeystores(t *testing.T, dir string, count int, password string) []string {
+	require.NoError(t, e2types.InitBLS())
+	ret := make([]string, 0)
+	for i := 0; i < count; i++ {
+		priv, err := e2types.GenerateBLSPrivateKey()
+		require.NoError(t, err)
+		crypto, err := keystorev4.New().Encrypt(priv.Marshal(), password)
+		require.NoError(t, err)
+		pubKey := hex.EncodeToString(priv.PublicKey().Marshal())
+		byts, err := json.Marshal(map[string]interface{}{
+			"crypto":  crypto,
+			"pubkey":  pubKey,
+			"path":    fmt.Sprintf("m/12381/3600/%d/0/0", i),
+			"uuid":    uuid.New().String(),
+			"version": 4,
+		})
+		require.NoError(t, err)
+		name := fmt.Sprintf("keystore-m_12381_3600_%d_0_0-1600000000.json", i)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), byts, 0600))
+		ret = append(ret, pubKey)
+	}
+	return ret
+}
+
+func TestAccountImport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystores")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	pubKeys := writeKeystores(t, dir, 2, "password")
+
+	t.Run("Successfully import a directory of keystores", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"import",
+			"--keystore=" + dir,
+			"--password=password",
+			"--storage=" + emptyWalletStorage,
+		})
+		require.NoError(t, cmd.RootCmd.Execute())
+
+		storageBytes, err := hex.DecodeString(strings.TrimSpace(output.String()))
+		require.NoError(t, err)
+		var store in_memory.InMemStore
+		require.NoError(t, store.UnmarshalJSON(storageBytes))
+		wallet, err := store.OpenWallet()
+		require.NoError(t, err)
+		require.Len(t, wallet.Accounts(), 2)
+		for _, pubKey := range pubKeys {
+			account, err := wallet.AccountByPublicKey(pubKey)
+			require.NoError(t, err)
+			require.NotNil(t, account)
+		}
+	})
+
+	t.Run("Successfully import a single keystore", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"import",
+			"--keystore=" + filepath.Join(dir, "keystore-m_12381_3600_0_0_0-1600000000.json"),
+			"--password=password",
+			"--storage=" + emptyWalletStorage,
+		})
+		require.NoError(t, cmd.RootCmd.Execute())
+		require.NotEmpty(t, output.String())
+	})
+
+	t.Run("Fail to decrypt keystore", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"import",
+			"--keystore=" + dir,
+			"--password=wrong",
+			"--storage=" + emptyWalletStorage,
+		})
+		err := cmd.RootCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decrypt keystore")
+		require.True(t, errors.Is(err, core.ErrDecryptionFailed))
+	})
+
+	t.Run("Fail to find keystores", func(t *testing.T) {
+		empty, err := ioutil.TempDir("", "keystores")
+		require.NoError(t, err)
+		defer os.RemoveAll(empty)
+
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"account",
+			"import",
+			"--keystore=" + empty,
+			"--password=password",
+			"--storage=" + emptyWalletStorage,
+		})
+		err = cmd.RootCmd.Execute()
+		require.EqualError(t, err, fmt.Sprintf("no keystore*.json files found in %s", empty))
+	})
+}
@@ -0,0 +1,33 @@
+package account
+
+import (
+	"github.com/spf13/cobra"
+
+	rootcmd "github.com/bloxapp/eth2-key-manager/cli/cmd"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/flag"
+	"github.com/bloxapp/eth2-key-manager/cli/cmd/wallet/cmd/account/handler"
+)
+
+// verifyDepositDataCmd represents the verify-deposit-data account command.
+var verifyDepositDataCmd = &cobra.Command{
+	Use:   "verify-deposit-data",
+	Short: "Verifies a staking-deposit-cli deposit data file.",
+	Long:  `This command re-checks the network, roots and signatures of every deposit in a deposit_data-<timestamp>.json file.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		network, err := rootcmd.GetNetworkFlagValue(cmd)
+		if err != nil {
+			return err
+		}
+
+		handler := handler.New(rootcmd.ResultPrinter)
+		return handler.VerifyDepositData(cmd, args, network)
+	},
+}
+
+func init() {
+	// Define flags for the command.
+	flag.AddFileFlag(verifyDepositDataCmd)
+	rootcmd.AddNetworkFlag(verifyDepositDataCmd)
+
+	Command.AddCommand(verifyDepositDataCmd)
+}
@@ -2,6 +2,8 @@ package wallet_test
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -21,4 +23,35 @@ func TestAccountCreate(t *testing.T) {
 		err := cmd.RootCmd.Execute()
 		require.NoError(t, err)
 	})
+
+	t.Run("Successfully create wallet on a custom network", func(t *testing.T) {
+		configFile, err := ioutil.TempFile("", "config-*.yaml")
+		require.NoError(t, err)
+		defer os.Remove(configFile.Name())
+		_, err = configFile.WriteString("CONFIG_NAME: 'devnet'\nGENESIS_FORK_VERSION: 0x10000038\nDEPOSIT_CHAIN_ID: 1337\n")
+		require.NoError(t, err)
+		require.NoError(t, configFile.Close())
+
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"create",
+			"--network=" + configFile.Name(),
+		})
+		err = cmd.RootCmd.Execute()
+		require.NoError(t, err)
+	})
+
+	t.Run("Unknown network", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd.ResultPrinter = printer.New(&output)
+		cmd.RootCmd.SetArgs([]string{
+			"wallet",
+			"create",
+			"--network=unknown",
+		})
+		err := cmd.RootCmd.Execute()
+		require.EqualError(t, err, "undefined network unknown")
+	})
 }
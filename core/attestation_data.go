@@ -32,10 +32,12 @@ const (
 	DoubleVote      VoteDetectionType = "DoubleVote"
 	SurroundingVote VoteDetectionType = "SurroundingVote"
 	SurroundedVote  VoteDetectionType = "SurroundedVote"
+	// BelowWatermarkVote is an attestation below the key's watermark, see Watermark
+	BelowWatermarkVote VoteDetectionType = "BelowWatermark"
 )
 
 type AttestationSlashStatus struct {
-	Attestation *BeaconAttestation
+	Attestation *BeaconAttestation // nil if the conflicting attestation isn't in the history (e.g. below the watermark)
 	Status      VoteDetectionType
 }
 
@@ -117,5 +119,8 @@ func detectSurroundingVote(att *BeaconAttestation, other *BeaconAttestation) *Be
 }
 
 func detectSurroundedVote(att *BeaconAttestation, other *BeaconAttestation) *BeaconAttestation {
-	return detectSurroundingVote(other, att)
+	if detectSurroundingVote(other, att) != nil {
+		return other
+	}
+	return nil
 }
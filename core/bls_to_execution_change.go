@@ -0,0 +1,54 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+// DomainBLSToExecutionChange is the Capella DOMAIN_BLS_TO_EXECUTION_CHANGE domain type
+var DomainBLSToExecutionChange = e2types.DomainType{0x0a, 0x00, 0x00, 0x00}
+
+// BLSToExecutionChange is the message a withdrawal key signs to replace 0x00 (BLS) withdrawal credentials with
+// 0x01 credentials pointing to an execution address.
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/capella/beacon-chain.md#blstoexecutionchange
+type BLSToExecutionChange struct {
+	ValidatorIndex     uint64 `json:"validator_index"`
+	FromBLSPubkey      []byte `ssz-size:"48" json:"from_bls_pubkey"`
+	ToExecutionAddress []byte `ssz-size:"20" json:"to_execution_address"`
+}
+
+// SignedBLSToExecutionChange marshals to the json the beacon node API expects on
+// /eth/v1/beacon/pool/bls_to_execution_changes
+type SignedBLSToExecutionChange struct {
+	Message   *BLSToExecutionChange
+	Signature []byte
+}
+
+// NewSignedBLSToExecutionChange is the constructor of SignedBLSToExecutionChange.
+func NewSignedBLSToExecutionChange(change *BLSToExecutionChange, signature []byte) *SignedBLSToExecutionChange {
+	return &SignedBLSToExecutionChange{
+		Message:   change,
+		Signature: signature,
+	}
+}
+
+// MarshalJSON encodes uint64 as decimal strings and bytes as 0x prefixed hex, as the beacon node API does.
+func (change *SignedBLSToExecutionChange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"message": map[string]string{
+			"validator_index":      strconv.FormatUint(change.Message.ValidatorIndex, 10),
+			"from_bls_pubkey":      "0x" + hex.EncodeToString(change.Message.FromBLSPubkey),
+			"to_execution_address": "0x" + hex.EncodeToString(change.Message.ToExecutionAddress),
+		},
+		"signature": "0x" + hex.EncodeToString(change.Signature),
+	})
+}
+
+// BLSToExecutionChangeDomain computes the signing domain of BLS to execution changes, it is always computed with the
+// network's genesis fork version so changes stay valid across forks.
+func BLSToExecutionChangeDomain(network Network, genesisValidatorsRoot []byte) []byte {
+	return e2types.Domain(DomainBLSToExecutionChange, network.ForkVersion(), genesisValidatorsRoot)
+}
@@ -0,0 +1,28 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedBLSToExecutionChangeJSON(t *testing.T) {
+	pubKey, _ := hex.DecodeString("b323537b2867d9f2bae068f93e75a9e2e1c8d594e3696c34dc8010dc403eaeeaf43756a440fc82e1c6f45c6e8348343f")
+	address, _ := hex.DecodeString("8ba1f109551bd432803012645ac136ddd64dba72")
+	change := NewSignedBLSToExecutionChange(&BLSToExecutionChange{
+		ValidatorIndex:     25,
+		FromBLSPubkey:      pubKey,
+		ToExecutionAddress: address,
+	}, []byte{0xab, 0xcd})
+	byts, err := json.Marshal(change)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"message":{"validator_index":"25","from_bls_pubkey":"0xb323537b2867d9f2bae068f93e75a9e2e1c8d594e3696c34dc8010dc403eaeeaf43756a440fc82e1c6f45c6e8348343f","to_execution_address":"0x8ba1f109551bd432803012645ac136ddd64dba72"},"signature":"0xabcd"}`, string(byts))
+}
+
+func TestBLSToExecutionChangeDomain(t *testing.T) {
+	genesisValidatorsRoot, _ := hex.DecodeString("4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95")
+	domain := BLSToExecutionChangeDomain(MainNetwork, genesisValidatorsRoot)
+	require.EqualValues(t, "0a000000e2da244e514e528a68c526921e7c0493d9f8bd594e3bb8f938b7ca40", hex.EncodeToString(domain))
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"fmt"
+)
+
+// Predefined errors, use errors.Is to check an error's kind (e.g. errors.Is(err, core.ErrNotFound)) and errors.As
+// to get its details.
+var (
+	// ErrNotFound matches every NotFoundError
+	ErrNotFound = &NotFoundError{}
+	// ErrAlreadyExists matches every AlreadyExistsError
+	ErrAlreadyExists = &AlreadyExistsError{}
+	// ErrSlashable matches every SlashableError
+	ErrSlashable = &SlashableError{}
+	// ErrDecryptionFailed matches every DecryptionError
+	ErrDecryptionFailed = &DecryptionError{}
+	// ErrWrongNetwork matches every WrongNetworkError
+	ErrWrongNetwork = &WrongNetworkError{}
+)
+
+// NotFoundError is returned when a wallet, account or slashing record doesn't exist.
+type NotFoundError struct {
+	Kind string // e.g. "account", empty matches every kind
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found", e.Kind)
+}
+
+// Is matches not found errors of the same kind, or of any kind if the target has none.
+func (e *NotFoundError) Is(target error) bool {
+	t, ok := target.(*NotFoundError)
+	return ok && (len(t.Kind) == 0 || t.Kind == e.Kind)
+}
+
+// AlreadyExistsError is returned when saving something that can't be replaced, e.g. importing an account twice.
+type AlreadyExistsError struct {
+	Kind string // e.g. "account", empty matches every kind
+}
+
+func (e *AlreadyExistsError) Error() string {
+	return fmt.Sprintf("%s already exists", e.Kind)
+}
+
+// Is matches already exists errors of the same kind, or of any kind if the target has none.
+func (e *AlreadyExistsError) Is(target error) bool {
+	t, ok := target.(*AlreadyExistsError)
+	return ok && (len(t.Kind) == 0 || t.Kind == e.Kind)
+}
+
+// SlashableError is returned when the slashing protector refuses to sign a message, with the verdicts it was refused
+// for (none for messages refused without a slashing detection, e.g. randao reveals).
+type SlashableError struct {
+	Msg      string
+	Verdicts []*SlashingVerdict
+}
+
+func (e *SlashableError) Error() string {
+	return e.Msg
+}
+
+// Is matches every slashable error.
+func (e *SlashableError) Is(target error) bool {
+	_, ok := target.(*SlashableError)
+	return ok
+}
+
+// DecryptionError is returned when key material can't be decrypted, e.g. with a wrong password.
+type DecryptionError struct {
+	Msg string
+	Err error // the decryption failure, could be nil
+}
+
+func (e *DecryptionError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s", e.Msg, e.Err)
+	}
+	return e.Msg
+}
+
+// Unwrap returns the decryption failure.
+func (e *DecryptionError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches every decryption error.
+func (e *DecryptionError) Is(target error) bool {
+	_, ok := target.(*DecryptionError)
+	return ok
+}
+
+// WrongNetworkError is returned when something made for a network is used with another one.
+type WrongNetworkError struct {
+	Subject  string // what was made for the network, e.g. "store"
+	Expected Network
+	Actual   Network
+}
+
+func (e *WrongNetworkError) Error() string {
+	return fmt.Sprintf("%s was created for network %s, not %s", e.Subject, e.Expected, e.Actual)
+}
+
+// Is matches every wrong network error.
+func (e *WrongNetworkError) Is(target error) bool {
+	_, ok := target.(*WrongNetworkError)
+	return ok
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+// SlotsPerEpoch is the number of slots in an epoch
+const SlotsPerEpoch = 32
+
+// Fork is the beacon chain fork at the time of signing.
+type Fork struct {
+	PreviousVersion []byte `ssz-size:"4" json:"previous_version"`
+	CurrentVersion  []byte `ssz-size:"4" json:"current_version"`
+	Epoch           uint64 `json:"epoch"`
+}
+
+// ForkInfo holds what's needed, on top of the domain type, to compute a signing domain.
+type ForkInfo struct {
+	Fork                  *Fork  `json:"fork"`
+	GenesisValidatorsRoot []byte `ssz-size:"32" json:"genesis_validators_root"`
+}
+
+// Version returns the fork version active at the given epoch.
+func (info *ForkInfo) Version(epoch uint64) []byte {
+	if epoch < info.Fork.Epoch {
+		return info.Fork.PreviousVersion
+	}
+	return info.Fork.CurrentVersion
+}
+
+// Domain computes the signing domain of the domain type at the given epoch.
+// https://github.com/ethereum/eth2.0-specs/blob/dev/specs/phase0/beacon-chain.md#get_domain
+func (info *ForkInfo) Domain(domainType e2types.DomainType, epoch uint64) []byte {
+	return e2types.Domain(domainType, info.Version(epoch), info.GenesisValidatorsRoot)
+}
+
+// EpochAtSlot returns the epoch of the slot.
+func EpochAtSlot(slot uint64) uint64 {
+	return slot / SlotsPerEpoch
+}
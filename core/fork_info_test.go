@@ -0,0 +1,25 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+func TestForkInfoDomain(t *testing.T) {
+	info := &ForkInfo{
+		Fork: &Fork{
+			PreviousVersion: []byte{0, 0, 0, 0},
+			CurrentVersion:  []byte{1, 0, 0, 0},
+			Epoch:           10,
+		},
+		GenesisValidatorsRoot: _byteArray("04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673"),
+	}
+
+	require.EqualValues(t, []byte{0, 0, 0, 0}, info.Version(9))
+	require.EqualValues(t, []byte{1, 0, 0, 0}, info.Version(10))
+	require.EqualValues(t, e2types.Domain(e2types.DomainBeaconAttester, []byte{0, 0, 0, 0}, info.GenesisValidatorsRoot), info.Domain(e2types.DomainBeaconAttester, 9))
+	require.EqualValues(t, e2types.Domain(e2types.DomainBeaconAttester, []byte{1, 0, 0, 0}, info.GenesisValidatorsRoot), info.Domain(e2types.DomainBeaconAttester, 10))
+	require.EqualValues(t, 3, EpochAtSlot(127))
+}
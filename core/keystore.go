@@ -0,0 +1,88 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+// Key derivation functions supported by EIP-2335 keystores.
+const (
+	KDFScrypt = "scrypt"
+	KDFPbkdf2 = "pbkdf2"
+)
+
+// keystoreVersion is the EIP-2335 keystore version
+const keystoreVersion = 4
+
+// KeystoreFromHDKey encrypts the key into an EIP-2335 keystore (as json marshalable map) using the given kdf.
+// https://eips.ethereum.org/EIPS/eip-2335
+func KeystoreFromHDKey(key *HDKey, password string, kdf string) (map[string]interface{}, error) {
+	if len(password) == 0 {
+		return nil, fmt.Errorf("keystore password can't be empty")
+	}
+	if kdf != KDFScrypt && kdf != KDFPbkdf2 {
+		return nil, fmt.Errorf("unsupported kdf %s, supported are %s and %s", kdf, KDFScrypt, KDFPbkdf2)
+	}
+
+	crypto, err := keystorev4.New(keystorev4.WithCipher(kdf)).Encrypt(key.privKey.Marshal(), password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt key")
+	}
+
+	return map[string]interface{}{
+		"crypto":  crypto,
+		"pubkey":  hex.EncodeToString(key.PublicKey().Marshal()),
+		"path":    key.path,
+		"uuid":    key.id.String(),
+		"version": keystoreVersion,
+	}, nil
+}
+
+// HDKeyFromKeystore decrypts an EIP-2335 keystore (as unmarshaled json) into a HDKey.
+// The keystore's path is kept (could be empty for keys not derived by EIP-2334).
+// https://eips.ethereum.org/EIPS/eip-2335
+func HDKeyFromKeystore(keystore map[string]interface{}, password string) (*HDKey, error) {
+	if version, ok := keystore["version"].(float64); !ok || version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version, only version 4 is supported")
+	}
+	crypto, ok := keystore["crypto"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("could not find var: crypto")
+	}
+
+	priv, err := keystorev4.New().Decrypt(crypto, password)
+	if err != nil {
+		return nil, &DecryptionError{Msg: "failed to decrypt keystore", Err: err}
+	}
+
+	path, _ := keystore["path"].(string)
+	key, err := NewHDKeyFromPrivateKey(priv, path)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid keystore private key")
+	}
+
+	// the public key is optional in a keystore but must match if present
+	if val, ok := keystore["pubkey"].(string); ok && len(val) > 0 {
+		pubKey, err := hex.DecodeString(strings.TrimPrefix(val, "0x"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode keystore public key")
+		}
+		if !bytes.Equal(pubKey, key.PublicKey().Marshal()) {
+			return nil, fmt.Errorf("keystore public key does not match its private key")
+		}
+	}
+
+	if val, ok := keystore["uuid"].(string); ok {
+		if id, err := uuid.Parse(val); err == nil {
+			key.id = id
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+func testKeystore(t *testing.T, priv e2types.PrivateKey, password string) map[string]interface{} {
+	crypto, err := keystorev4.New().Encrypt(priv.Marshal(), password)
+	require.NoError(t, err)
+
+	// round trip through json as keystores are read from files
+	byts, err := json.Marshal(map[string]interface{}{
+		"crypto":  crypto,
+		"pubkey":  hex.EncodeToString(priv.PublicKey().Marshal()),
+		"path":    "m/12381/3600/0/0/0",
+		"uuid":    "264daa3f-303d-4259-a9e2-5a0e10350e3e",
+		"version": 4,
+	})
+	require.NoError(t, err)
+	ret := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(byts, &ret))
+	return ret
+}
+
+func TestHDKeyFromKeystore(t *testing.T) {
+	require.NoError(t, e2types.InitBLS())
+	priv, err := e2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		key, err := HDKeyFromKeystore(testKeystore(t, priv, "password"), "password")
+		require.NoError(t, err)
+		require.EqualValues(t, priv.PublicKey().Marshal(), key.PublicKey().Marshal())
+		require.EqualValues(t, "m/12381/3600/0/0/0", key.Path())
+		require.EqualValues(t, "264daa3f-303d-4259-a9e2-5a0e10350e3e", key.id.String())
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := HDKeyFromKeystore(testKeystore(t, priv, "password"), "other")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrDecryptionFailed))
+	})
+
+	t.Run("public key mismatch", func(t *testing.T) {
+		other, err := e2types.GenerateBLSPrivateKey()
+		require.NoError(t, err)
+		keystore := testKeystore(t, priv, "password")
+		keystore["pubkey"] = hex.EncodeToString(other.PublicKey().Marshal())
+		_, err = HDKeyFromKeystore(keystore, "password")
+		require.EqualError(t, err, "keystore public key does not match its private key")
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		keystore := testKeystore(t, priv, "password")
+		keystore["version"] = float64(3)
+		_, err := HDKeyFromKeystore(keystore, "password")
+		require.EqualError(t, err, "unsupported keystore version, only version 4 is supported")
+	})
+}
+
+func TestKeystoreFromHDKey(t *testing.T) {
+	require.NoError(t, e2types.InitBLS())
+	priv, err := e2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+	key, err := NewHDKeyFromPrivateKey(priv.Marshal(), "m/12381/3600/0/0/0")
+	require.NoError(t, err)
+
+	for _, kdf := range []string{KDFScrypt, KDFPbkdf2} {
+		t.Run(kdf, func(t *testing.T) {
+			keystore, err := KeystoreFromHDKey(key, "password", kdf)
+			require.NoError(t, err)
+
+			// round trip through json as keystores are written to files
+			byts, err := json.Marshal(keystore)
+			require.NoError(t, err)
+			parsed := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal(byts, &parsed))
+			require.EqualValues(t, kdf, parsed["crypto"].(map[string]interface{})["kdf"].(map[string]interface{})["function"])
+			require.EqualValues(t, hex.EncodeToString(priv.PublicKey().Marshal()), parsed["pubkey"])
+			require.EqualValues(t, key.id.String(), parsed["uuid"])
+
+			decrypted, err := HDKeyFromKeystore(parsed, "password")
+			require.NoError(t, err)
+			require.EqualValues(t, priv.Marshal(), decrypted.privKey.Marshal())
+			require.EqualValues(t, key.Path(), decrypted.Path())
+		})
+	}
+
+	t.Run("empty password", func(t *testing.T) {
+		_, err := KeystoreFromHDKey(key, "", KDFScrypt)
+		require.EqualError(t, err, "keystore password can't be empty")
+	})
+
+	t.Run("unsupported kdf", func(t *testing.T) {
+		_, err := KeystoreFromHDKey(key, "password", "argon2")
+		require.EqualError(t, err, "unsupported kdf argon2, supported are scrypt and pbkdf2")
+	})
+}
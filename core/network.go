@@ -0,0 +1,306 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Network represents the network.
+type Network string
+
+// Available networks.
+const (
+	// TestNetwork represents the test network.
+	TestNetwork Network = "test"
+
+	// ZinkenNetwork represents Zinken network.
+	ZinkenNetwork Network = "zinken"
+
+	// MainNetwork represents the main network.
+	MainNetwork Network = "main"
+)
+
+// ScheduledFork is a fork of the network's fork schedule.
+type ScheduledFork struct {
+	Name    string
+	Version []byte
+	Epoch   uint64
+}
+
+// NetworkConfig is the chain config of a network.
+type NetworkConfig struct {
+	Name                   string
+	GenesisForkVersion     []byte
+	GenesisValidatorsRoot  []byte // optional
+	DepositContractAddress string // optional
+	DepositChainID         uint64 // optional
+	Forks                  []*ScheduledFork
+}
+
+var (
+	networksLock sync.RWMutex
+	networks     = map[Network]*NetworkConfig{
+		TestNetwork: {
+			Name:                   string(TestNetwork),
+			GenesisForkVersion:     []byte{0, 0, 0, 1},
+			GenesisValidatorsRoot:  mustDecodeHex("04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673"),
+			DepositContractAddress: "0x07b39F4fDE4A38bACe212b546dAc87C58DfE3fDC",
+			DepositChainID:         5,
+		},
+		ZinkenNetwork: {
+			Name:               string(ZinkenNetwork),
+			GenesisForkVersion: []byte{0, 0, 0, 3},
+		},
+		MainNetwork: {
+			Name:               string(MainNetwork),
+			GenesisForkVersion: []byte{0, 0, 0, 4},
+		},
+	}
+)
+
+// RegisterNetwork registers a network so it can be used like the built-in ones, registering the same name again
+// replaces the previous config. Built-in networks can't be replaced.
+func RegisterNetwork(config *NetworkConfig) (Network, error) {
+	if err := config.validate(); err != nil {
+		return "", errors.Wrapf(err, "invalid network %s", config.Name)
+	}
+
+	// keep the schedule ordered without changing the caller's config
+	forks := make([]*ScheduledFork, len(config.Forks))
+	copy(forks, config.Forks)
+	sortForks(forks)
+	registered := *config
+	registered.Forks = forks
+
+	network := Network(config.Name)
+	networksLock.Lock()
+	defer networksLock.Unlock()
+	switch network {
+	case TestNetwork, ZinkenNetwork, MainNetwork:
+		return "", fmt.Errorf("network %s is built-in and can't be registered", network)
+	}
+	networks[network] = &registered
+	return network, nil
+}
+
+// NetworkFromString returns network from the given string value, the network must be built-in or registered.
+func NetworkFromString(n string) (Network, error) {
+	if Network(n).config() == nil {
+		return "", fmt.Errorf("undefined network %s", n)
+	}
+	return Network(n), nil
+}
+
+// LoadNetworkConfig reads a network chain config from a YAML (or JSON) file using the consensus specs config keys:
+// CONFIG_NAME, GENESIS_FORK_VERSION, DEPOSIT_CONTRACT_ADDRESS, DEPOSIT_CHAIN_ID, GENESIS_VALIDATORS_ROOT and
+// <FORK>_FORK_VERSION/<FORK>_FORK_EPOCH pairs for the fork schedule. Other keys are ignored.
+func LoadNetworkConfig(path string) (*NetworkConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read network config")
+	}
+	return ParseNetworkConfig(data)
+}
+
+// ParseNetworkConfig parses a YAML (or JSON) network chain config, see LoadNetworkConfig.
+func ParseNetworkConfig(data []byte) (*NetworkConfig, error) {
+	// values are kept as their raw text, hex values like fork versions aren't quoted in spec configs
+	nodes := make(map[string]yaml.Node)
+	if err := yaml.Unmarshal(data, &nodes); err != nil {
+		return nil, errors.Wrap(err, "failed to parse network config")
+	}
+	values := make(map[string]string)
+	for key, node := range nodes {
+		if node.Kind == yaml.ScalarNode {
+			values[key] = node.Value
+		}
+	}
+
+	config := &NetworkConfig{
+		Name:                   values["CONFIG_NAME"],
+		DepositContractAddress: values["DEPOSIT_CONTRACT_ADDRESS"],
+	}
+	if len(config.Name) == 0 {
+		return nil, fmt.Errorf("network config has no CONFIG_NAME")
+	}
+
+	var err error
+	if config.GenesisForkVersion, err = decodeConfigHex(values, "GENESIS_FORK_VERSION"); err != nil {
+		return nil, err
+	}
+	if config.GenesisValidatorsRoot, err = decodeConfigHex(values, "GENESIS_VALIDATORS_ROOT"); err != nil {
+		return nil, err
+	}
+	if val, ok := values["DEPOSIT_CHAIN_ID"]; ok {
+		if config.DepositChainID, err = strconv.ParseUint(val, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid DEPOSIT_CHAIN_ID %s", val)
+		}
+	}
+
+	for key := range values {
+		if !strings.HasSuffix(key, "_FORK_VERSION") || key == "GENESIS_FORK_VERSION" {
+			continue
+		}
+		name := strings.TrimSuffix(key, "_FORK_VERSION")
+		version, err := decodeConfigHex(values, key)
+		if err != nil {
+			return nil, err
+		}
+		epochKey := name + "_FORK_EPOCH"
+		epoch, err := strconv.ParseUint(values[epochKey], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %s", epochKey, values[epochKey])
+		}
+		config.Forks = append(config.Forks, &ScheduledFork{
+			Name:    strings.ToLower(name),
+			Version: version,
+			Epoch:   epoch,
+		})
+	}
+	sortForks(config.Forks)
+
+	return config, nil
+}
+
+// ForkVersion returns the genesis fork version of the network, nil if unknown.
+func (n Network) ForkVersion() []byte {
+	if config := n.config(); config != nil {
+		return config.GenesisForkVersion
+	}
+	return nil
+}
+
+// ForkAtEpoch returns the fork of the network's fork schedule active at the given epoch, a zero fork if the network
+// is unknown.
+func (n Network) ForkAtEpoch(epoch uint64) *Fork {
+	config := n.config()
+	if config == nil {
+		return &Fork{}
+	}
+	ret := &Fork{
+		PreviousVersion: config.GenesisForkVersion,
+		CurrentVersion:  config.GenesisForkVersion,
+	}
+	for _, fork := range config.Forks {
+		if fork.Epoch > epoch {
+			break
+		}
+		ret = &Fork{
+			PreviousVersion: ret.CurrentVersion,
+			CurrentVersion:  fork.Version,
+			Epoch:           fork.Epoch,
+		}
+	}
+	return ret
+}
+
+// VoluntaryExitFork returns the fork voluntary exits at the given epoch are signed with. Once a fork after Capella
+// (Deneb onwards) is scheduled exits are always signed with the Capella fork version, see EIP-7044.
+// https://eips.ethereum.org/EIPS/eip-7044
+func (n Network) VoluntaryExitFork(epoch uint64) *Fork {
+	if config := n.config(); config != nil {
+		for i, fork := range config.Forks {
+			if fork.Name == "capella" && i < len(config.Forks)-1 {
+				return &Fork{
+					PreviousVersion: fork.Version,
+					CurrentVersion:  fork.Version,
+					Epoch:           fork.Epoch,
+				}
+			}
+		}
+	}
+	return n.ForkAtEpoch(epoch)
+}
+
+// GenesisValidatorsRoot returns the genesis validators root of the network, nil if unknown.
+func (n Network) GenesisValidatorsRoot() []byte {
+	if config := n.config(); config != nil {
+		return config.GenesisValidatorsRoot
+	}
+	return nil
+}
+
+// DepositContractAddress returns the deposit contract address of the network, empty if unknown.
+func (n Network) DepositContractAddress() string {
+	if config := n.config(); config != nil {
+		return config.DepositContractAddress
+	}
+	return ""
+}
+
+// DepositChainID returns the chain ID of the network's deposit contract, 0 if unknown.
+func (n Network) DepositChainID() uint64 {
+	if config := n.config(); config != nil {
+		return config.DepositChainID
+	}
+	return 0
+}
+
+// FullPath returns the full EIP-2334 path of the relative path.
+func (n Network) FullPath(relativePath string) string {
+	return BaseEIP2334Path + relativePath
+}
+
+// sortForks orders a fork schedule by epoch, ForkAtEpoch relies on it. Forks at the same epoch (e.g. several at
+// genesis) are ordered by version, which is how networks number them.
+func sortForks(forks []*ScheduledFork) {
+	sort.Slice(forks, func(i, j int) bool {
+		if forks[i].Epoch == forks[j].Epoch {
+			return bytes.Compare(forks[i].Version, forks[j].Version) < 0
+		}
+		return forks[i].Epoch < forks[j].Epoch
+	})
+}
+
+func (n Network) config() *NetworkConfig {
+	networksLock.RLock()
+	defer networksLock.RUnlock()
+	return networks[n]
+}
+
+func (config *NetworkConfig) validate() error {
+	if len(config.Name) == 0 {
+		return fmt.Errorf("network name can't be empty")
+	}
+	if len(config.GenesisForkVersion) != 4 {
+		return fmt.Errorf("genesis fork version must be 4 bytes")
+	}
+	if len(config.GenesisValidatorsRoot) != 0 && len(config.GenesisValidatorsRoot) != 32 {
+		return fmt.Errorf("genesis validators root must be 32 bytes")
+	}
+	for _, fork := range config.Forks {
+		if len(fork.Version) != 4 {
+			return fmt.Errorf("%s fork version must be 4 bytes", fork.Name)
+		}
+	}
+	return nil
+}
+
+func decodeConfigHex(values map[string]string, key string) ([]byte, error) {
+	val, ok := values[key]
+	if !ok {
+		return nil, nil
+	}
+	ret, err := hex.DecodeString(strings.TrimPrefix(val, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %s", key, val)
+	}
+	return ret, nil
+}
+
+func mustDecodeHex(val string) []byte {
+	ret, err := hex.DecodeString(val)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
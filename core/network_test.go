@@ -0,0 +1,133 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const networkConfig = `
+PRESET_BASE: 'mainnet'
+CONFIG_NAME: 'holesky'
+GENESIS_FORK_VERSION: 0x01017000
+GENESIS_VALIDATORS_ROOT: 0x9143aa7c615a7f7115e2b6aac319c03529df8242ae705fba9df39b79c59fa8b1
+ALTAIR_FORK_VERSION: 0x02017000
+ALTAIR_FORK_EPOCH: 0
+BELLATRIX_FORK_VERSION: 0x03017000
+BELLATRIX_FORK_EPOCH: 0
+CAPELLA_FORK_VERSION: 0x04017000
+CAPELLA_FORK_EPOCH: 256
+DENEB_FORK_VERSION: 0x05017000
+DENEB_FORK_EPOCH: 29696
+DEPOSIT_CHAIN_ID: 17000
+DEPOSIT_CONTRACT_ADDRESS: 0x4242424242424242424242424242424242424242
+BLOB_SCHEDULE:
+  - EPOCH: 29696
+    MAX_BLOBS_PER_BLOCK: 6
+`
+
+func TestParseNetworkConfig(t *testing.T) {
+	config, err := ParseNetworkConfig([]byte(networkConfig))
+	require.NoError(t, err)
+	require.EqualValues(t, "holesky", config.Name)
+	require.EqualValues(t, []byte{1, 1, 0x70, 0}, config.GenesisForkVersion)
+	require.EqualValues(t, _byteArray("9143aa7c615a7f7115e2b6aac319c03529df8242ae705fba9df39b79c59fa8b1"), config.GenesisValidatorsRoot)
+	require.EqualValues(t, 17000, config.DepositChainID)
+	require.EqualValues(t, "0x4242424242424242424242424242424242424242", config.DepositContractAddress)
+	require.Len(t, config.Forks, 4)
+	require.EqualValues(t, "capella", config.Forks[2].Name)
+	require.EqualValues(t, 256, config.Forks[2].Epoch)
+	require.EqualValues(t, 29696, config.Forks[3].Epoch)
+
+	t.Run("json", func(t *testing.T) {
+		config, err := ParseNetworkConfig([]byte(`{"CONFIG_NAME": "devnet", "GENESIS_FORK_VERSION": "0x10000038", "DEPOSIT_CHAIN_ID": 1337}`))
+		require.NoError(t, err)
+		require.EqualValues(t, "devnet", config.Name)
+		require.EqualValues(t, []byte{0x10, 0, 0, 0x38}, config.GenesisForkVersion)
+		require.EqualValues(t, 1337, config.DepositChainID)
+		require.Len(t, config.Forks, 0)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		_, err := ParseNetworkConfig([]byte(`GENESIS_FORK_VERSION: 0x10000038`))
+		require.EqualError(t, err, "network config has no CONFIG_NAME")
+	})
+
+	t.Run("fork without epoch", func(t *testing.T) {
+		_, err := ParseNetworkConfig([]byte("CONFIG_NAME: devnet\nGENESIS_FORK_VERSION: 0x10000038\nALTAIR_FORK_VERSION: 0x20000038"))
+		require.EqualError(t, err, "invalid ALTAIR_FORK_EPOCH ")
+	})
+}
+
+func TestRegisterNetwork(t *testing.T) {
+	config, err := ParseNetworkConfig([]byte(networkConfig))
+	require.NoError(t, err)
+
+	network, err := RegisterNetwork(config)
+	require.NoError(t, err)
+	require.EqualValues(t, "holesky", network)
+
+	fromString, err := NetworkFromString("holesky")
+	require.NoError(t, err)
+	require.EqualValues(t, network, fromString)
+	require.EqualValues(t, []byte{1, 1, 0x70, 0}, network.ForkVersion())
+	require.EqualValues(t, config.GenesisValidatorsRoot, network.GenesisValidatorsRoot())
+	require.EqualValues(t, 17000, network.DepositChainID())
+	require.EqualValues(t, "0x4242424242424242424242424242424242424242", network.DepositContractAddress())
+
+	t.Run("fork schedule", func(t *testing.T) {
+		require.EqualValues(t, &Fork{PreviousVersion: []byte{2, 1, 0x70, 0}, CurrentVersion: []byte{3, 1, 0x70, 0}, Epoch: 0}, network.ForkAtEpoch(255))
+		require.EqualValues(t, &Fork{PreviousVersion: []byte{3, 1, 0x70, 0}, CurrentVersion: []byte{4, 1, 0x70, 0}, Epoch: 256}, network.ForkAtEpoch(256))
+		require.EqualValues(t, &Fork{PreviousVersion: []byte{4, 1, 0x70, 0}, CurrentVersion: []byte{5, 1, 0x70, 0}, Epoch: 29696}, network.ForkAtEpoch(30000))
+
+		// no schedule
+		require.EqualValues(t, &Fork{PreviousVersion: []byte{0, 0, 0, 1}, CurrentVersion: []byte{0, 0, 0, 1}}, TestNetwork.ForkAtEpoch(100))
+	})
+
+	t.Run("voluntary exits are signed with capella after deneb", func(t *testing.T) {
+		capella := &Fork{PreviousVersion: []byte{4, 1, 0x70, 0}, CurrentVersion: []byte{4, 1, 0x70, 0}, Epoch: 256}
+		require.EqualValues(t, capella, network.VoluntaryExitFork(30000))
+		require.EqualValues(t, capella, network.VoluntaryExitFork(100))
+
+		// no fork after capella
+		require.EqualValues(t, TestNetwork.ForkAtEpoch(100), TestNetwork.VoluntaryExitFork(100))
+	})
+
+	t.Run("same epoch forks are ordered by version", func(t *testing.T) {
+		network, err := RegisterNetwork(&NetworkConfig{
+			Name:               "devnet",
+			GenesisForkVersion: []byte{0, 0, 0, 9},
+			Forks: []*ScheduledFork{
+				{Name: "capella", Version: []byte{3, 0, 0, 9}, Epoch: 0},
+				{Name: "altair", Version: []byte{1, 0, 0, 9}, Epoch: 0},
+				{Name: "bellatrix", Version: []byte{2, 0, 0, 9}, Epoch: 0},
+			},
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, &Fork{PreviousVersion: []byte{2, 0, 0, 9}, CurrentVersion: []byte{3, 0, 0, 9}}, network.ForkAtEpoch(0))
+	})
+
+	t.Run("unknown network", func(t *testing.T) {
+		require.Nil(t, Network("unknown").ForkVersion())
+		require.EqualValues(t, &Fork{}, Network("unknown").ForkAtEpoch(100))
+	})
+
+	t.Run("built-in network", func(t *testing.T) {
+		_, err := RegisterNetwork(&NetworkConfig{Name: "main", GenesisForkVersion: []byte{0, 0, 0, 0}})
+		require.EqualError(t, err, "network main is built-in and can't be registered")
+	})
+
+	t.Run("invalid fork version", func(t *testing.T) {
+		_, err := RegisterNetwork(&NetworkConfig{Name: "devnet", GenesisForkVersion: []byte{0, 0}})
+		require.EqualError(t, err, "invalid network devnet: genesis fork version must be 4 bytes")
+	})
+}
+
+func TestNetworkFromString(t *testing.T) {
+	network, err := NetworkFromString("main")
+	require.NoError(t, err)
+	require.EqualValues(t, MainNetwork, network)
+
+	_, err = NetworkFromString("unknown")
+	require.EqualError(t, err, "undefined network unknown")
+}
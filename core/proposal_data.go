@@ -40,10 +40,13 @@ const (
 	DoubleProposal ProposalDetectionType = "DoubleProposal"
 	ValidProposal  ProposalDetectionType = "Valid"
 	Error          ProposalDetectionType = "Error"
+	// BelowWatermarkProposal is a proposal at or below the key's watermark, see Watermark
+	BelowWatermarkProposal ProposalDetectionType = "BelowWatermark"
 )
 
 type ProposalSlashStatus struct {
-	Proposal *BeaconBlockHeader
-	Status   ProposalDetectionType
-	Error    error
+	Proposal    *BeaconBlockHeader
+	Conflicting *BeaconBlockHeader // the proposal of the history it conflicts with, nil if none
+	Status      ProposalDetectionType
+	Error       error
 }
@@ -12,6 +12,14 @@ type SlashingProtector interface {
 	SaveProposal(key e2types.PublicKey, req *pb.SignBeaconProposalRequest) error
 	SaveLatestAttestation(key e2types.PublicKey, req *pb.SignBeaconAttestationRequest) error
 	RetrieveLatestAttestation(key e2types.PublicKey) (*BeaconAttestation, error)
+	// RetrieveHighestProposal returns the signed proposal with the highest slot, nil if none
+	RetrieveHighestProposal(key e2types.PublicKey) (*BeaconBlockHeader, error)
+	// CheckAndRecordAttestation checks the attestation and records it in a single store transaction (see
+	// SlashingStore.Atomically), slashable attestations aren't recorded and their statuses are returned
+	CheckAndRecordAttestation(key e2types.PublicKey, req *pb.SignBeaconAttestationRequest) ([]*AttestationSlashStatus, error)
+	// CheckAndRecordProposal checks the proposal and records it in a single store transaction (see
+	// SlashingStore.Atomically), slashable proposals aren't recorded
+	CheckAndRecordProposal(key e2types.PublicKey, req *pb.SignBeaconProposalRequest) *ProposalSlashStatus
 }
 
 type SlashingStore interface {
@@ -21,6 +29,88 @@ type SlashingStore interface {
 	ListAttestations(key e2types.PublicKey, epochStart uint64, epochEnd uint64) ([]*BeaconAttestation, error)
 	SaveProposal(key e2types.PublicKey, req *BeaconBlockHeader) error
 	RetrieveProposal(key e2types.PublicKey, slot uint64) (*BeaconBlockHeader, error)
+	// both slotStart and slotEnd are inclusive
+	ListProposals(key e2types.PublicKey, slotStart uint64, slotEnd uint64) ([]*BeaconBlockHeader, error)
 	SaveLatestAttestation(key e2types.PublicKey, req *BeaconAttestation) error
 	RetrieveLatestAttestation(key e2types.PublicKey) (*BeaconAttestation, error)
+	// SaveAttestationSpans saves the given epoch spans (replacing existing ones) together with the span bounds
+	SaveAttestationSpans(key e2types.PublicKey, bounds *SpanBounds, spans map[uint64]*EpochSpan) error
+	// RetrieveAttestationSpanBounds returns nil if no spans were saved for the key
+	RetrieveAttestationSpanBounds(key e2types.PublicKey) (*SpanBounds, error)
+	// RetrieveAttestationSpan returns nil if no span was saved for the epoch
+	RetrieveAttestationSpan(key e2types.PublicKey, epoch uint64) (*EpochSpan, error)
+	SaveWatermark(key e2types.PublicKey, watermark *Watermark) error
+	// RetrieveWatermark returns nil if no watermark was saved for the key
+	RetrieveWatermark(key e2types.PublicKey) (*Watermark, error)
+	// ListSlashingKeys returns the keys with saved attestations or proposals
+	ListSlashingKeys() ([]e2types.PublicKey, error)
+	// PruneAttestations deletes the attestations with a target epoch lower than targetEpoch and the spans of epochs
+	// lower than spanEpoch, the latest attestation, span bounds and watermark are kept
+	PruneAttestations(key e2types.PublicKey, targetEpoch uint64, spanEpoch uint64) error
+	// PruneProposals deletes the proposals with a slot lower than the given slot
+	PruneProposals(key e2types.PublicKey, slot uint64) error
+	// Atomically calls fn with exclusive access to the key's slashing records, fn must only use the given store.
+	// Stores with transactions commit what fn wrote only if it returns nil, others write as fn goes.
+	// Atomically can't be nested.
+	Atomically(key e2types.PublicKey, fn func(store SlashingStore) error) error
+}
+
+// Watermark is the low watermark of a key whose history isn't fully known (e.g. imported), signing at or below it is
+// refused: attestations with a source below MinSourceEpoch or a target at or below MinTargetEpoch and proposals at or
+// below MinProposalSlot. A nil MinTargetEpoch/ MinProposalSlot refuses nothing, a watermark at 0 refuses epoch/ slot 0.
+// https://eips.ethereum.org/EIPS/eip-3076#conditions
+type Watermark struct {
+	MinSourceEpoch  uint64  `json:"min_source_epoch"`
+	MinTargetEpoch  *uint64 `json:"min_target_epoch,omitempty"`
+	MinProposalSlot *uint64 `json:"min_proposal_slot,omitempty"`
+}
+
+// EpochSpan holds the min and max spans of an epoch, used to detect surround votes without going through the
+// attestation history (0 means no span).
+// The min span is the distance from the epoch to the lowest target of attestations with a higher source, the max span
+// the distance to the highest target of attestations with a lower source and a higher target.
+// https://github.com/protolambda/eth2-surround#min-max-surround
+type EpochSpan struct {
+	MinSpan uint64 `json:"min_span"`
+	MaxSpan uint64 `json:"max_span"`
+}
+
+// SpanBounds covers the epochs below the lowest attested source, all attestations have a higher source so their min
+// span is the distance to the lowest attested target and isn't saved per epoch.
+type SpanBounds struct {
+	LowestSource uint64 `json:"lowest_source"`
+	LowestTarget uint64 `json:"lowest_target"`
+}
+
+// SlashingVerdict is why signing a message was refused: the detection, the refused request and the record of the
+// history it conflicts with (nil if it isn't in the history, e.g. below the watermark).
+// Only the attestation or the proposal fields are set, depending on the message.
+type SlashingVerdict struct {
+	Detection              string             `json:"detection"`
+	Attestation            *BeaconAttestation `json:"attestation,omitempty"`
+	ConflictingAttestation *BeaconAttestation `json:"conflicting_attestation,omitempty"`
+	Proposal               *BeaconBlockHeader `json:"proposal,omitempty"`
+	ConflictingProposal    *BeaconBlockHeader `json:"conflicting_proposal,omitempty"`
+}
+
+// AttestationVerdicts returns the verdicts of the refused attestation, one per slash status.
+func AttestationVerdicts(att *BeaconAttestation, statuses []*AttestationSlashStatus) []*SlashingVerdict {
+	ret := make([]*SlashingVerdict, 0)
+	for _, status := range statuses {
+		ret = append(ret, &SlashingVerdict{
+			Detection:              string(status.Status),
+			Attestation:            att,
+			ConflictingAttestation: status.Attestation,
+		})
+	}
+	return ret
+}
+
+// ProposalVerdict returns the verdict of the refused proposal.
+func ProposalVerdict(proposal *BeaconBlockHeader, status *ProposalSlashStatus) *SlashingVerdict {
+	return &SlashingVerdict{
+		Detection:           string(status.Status),
+		Proposal:            proposal,
+		ConflictingProposal: status.Conflicting,
+	}
 }
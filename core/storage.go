@@ -1,60 +1,10 @@
 package core
 
 import (
-	"fmt"
-
 	"github.com/google/uuid"
 	types "github.com/wealdtech/go-eth2-wallet-types/v2"
 )
 
-// Network represents the network.
-type Network string
-
-// NetworkFromString returns network from the given string value
-func NetworkFromString(n string) Network {
-	switch n {
-	case string(TestNetwork):
-		return TestNetwork
-	case string(ZinkenNetwork):
-		return ZinkenNetwork
-	case string(MainNetwork):
-		return MainNetwork
-	default:
-		panic(fmt.Sprintf("undefined network %s", n))
-	}
-}
-
-// ForkVersion returns the fork version of the network.
-func (n Network) ForkVersion() []byte {
-	switch n {
-	case TestNetwork:
-		return []byte{0, 0, 0, 1}
-	case ZinkenNetwork:
-		return []byte{0, 0, 0, 3}
-	case MainNetwork:
-		return []byte{0, 0, 0, 4}
-	default:
-		panic(fmt.Sprintf("undefined network %s", n))
-	}
-}
-
-// ForkVersion returns the fork version of the network.
-func (n Network) FullPath(relativePath string) string {
-	return BaseEIP2334Path + relativePath
-}
-
-// Available networks.
-const (
-	// TestNetwork represents the test network.
-	TestNetwork Network = "test"
-
-	// ZinkenNetwork represents Zinken network.
-	ZinkenNetwork Network = "zinken"
-
-	// MainNetwork represents the main network.
-	MainNetwork Network = "main"
-)
-
 // Implements methods to store and retrieve data
 // Any encryption is done on the implementation level but is not obligatory
 type Storage interface {
@@ -69,7 +19,7 @@ type Storage interface {
 	//-------------------------
 	// SaveWallet stores the given wallet.
 	SaveWallet(wallet Wallet) error
-	// OpenWallet returns nil,err if no wallet was found
+	// OpenWallet returns a NotFoundError if no wallet was found
 	OpenWallet() (Wallet, error)
 	// ListAccounts returns an empty array for no accounts
 	ListAccounts() ([]ValidatorAccount, error)
@@ -78,9 +28,9 @@ type Storage interface {
 	//	Account specific
 	//-------------------------
 	SaveAccount(account ValidatorAccount) error
-	// Delete account by uuid
+	// Delete account by uuid, returns a NotFoundError if no account was found
 	DeleteAccount(accountId uuid.UUID) error
-	// will return nil,nil if no account was found
+	// OpenAccount returns a NotFoundError if no account was found
 	OpenAccount(accountId uuid.UUID) (ValidatorAccount, error)
 
 	// SetEncryptor sets the given encryptor to the wallet.
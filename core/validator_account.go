@@ -28,10 +28,14 @@ type ValidatorAccount interface {
 	WithdrawalPublicKey() e2types.PublicKey
 	// Sign signs data with the validation key.
 	ValidationKeySign(data []byte) (e2types.Signature, error)
-	// Get Deposit Data
-	GetDepositData() (map[string]interface{}, error)
-	//// Sign signs data with the withdrawal key.
-	//WithdrawalKeySign(data []byte) (e2types.Signature,error)
-	//
+	// GetDepositData returns the deposit data of amountInGwei, withdrawing to executionAddress (0x01 credentials) if
+	// given or to the withdrawal key (0x00 credentials) otherwise.
+	GetDepositData(executionAddress []byte, amountInGwei uint64) (map[string]interface{}, error)
+	// ExportKeystore encrypts the validation key into an EIP-2335 keystore, kdf is core.KDFScrypt or core.KDFPbkdf2.
+	ExportKeystore(password string, kdf string) (map[string]interface{}, error)
+	// WithdrawalKeySign signs data with the withdrawal key.
+	// The withdrawal key is never stored, it is derived from the given seed which must be the one the account was
+	// derived from.
+	WithdrawalKeySign(seed []byte, data []byte) (e2types.Signature, error)
 	SetContext(ctx *WalletContext)
 }
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+)
+
+// VoluntaryExit is the message a validator signs to exit the validator set.
+// https://github.com/ethereum/eth2.0-specs/blob/dev/specs/phase0/beacon-chain.md#voluntaryexit
+type VoluntaryExit struct {
+	Epoch          uint64 `json:"epoch"`
+	ValidatorIndex uint64 `json:"validator_index"`
+}
+
+// SignedVoluntaryExit marshals to the json the beacon node API expects on /eth/v1/beacon/pool/voluntary_exits
+type SignedVoluntaryExit struct {
+	Message   *VoluntaryExit
+	Signature []byte
+}
+
+// NewSignedVoluntaryExit is the constructor of SignedVoluntaryExit.
+func NewSignedVoluntaryExit(exit *VoluntaryExit, signature []byte) *SignedVoluntaryExit {
+	return &SignedVoluntaryExit{
+		Message:   exit,
+		Signature: signature,
+	}
+}
+
+// MarshalJSON encodes uint64 as decimal strings and the signature as 0x prefixed hex, as the beacon node API does.
+func (exit *SignedVoluntaryExit) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"message": map[string]string{
+			"epoch":           strconv.FormatUint(exit.Message.Epoch, 10),
+			"validator_index": strconv.FormatUint(exit.Message.ValidatorIndex, 10),
+		},
+		"signature": "0x" + hex.EncodeToString(exit.Signature),
+	})
+}
@@ -0,0 +1,15 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedVoluntaryExitJSON(t *testing.T) {
+	exit := NewSignedVoluntaryExit(&VoluntaryExit{Epoch: 1024, ValidatorIndex: 25}, []byte{0xab, 0xcd})
+	byts, err := json.Marshal(exit)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"message":{"epoch":"1024","validator_index":"25"},"signature":"0xabcd"}`, string(byts))
+}
@@ -18,6 +18,9 @@ type Wallet interface {
 	Type() WalletType
 	// CreateValidatorKey creates a new validation (validator) key pair in the wallet.
 	CreateValidatorAccount(seed []byte, indexPointer *int) (ValidatorAccount, error)
+	// ImportValidatorAccount adds an account from an EIP-2335 keystore, the keystore's path is kept if present.
+	// Imported accounts have no base path and no withdrawal key.
+	ImportValidatorAccount(keystore map[string]interface{}, password string) (ValidatorAccount, error)
 	// Accounts provides all accounts in the wallet.
 	Accounts() []ValidatorAccount
 	// AccountByID provides a single account from the wallet given its ID.
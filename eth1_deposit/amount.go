@@ -0,0 +1,61 @@
+package eth1_deposit
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// gweiDecimals is the number of ETH decimals a gwei amount holds
+const gweiDecimals = 9
+
+// ValidateDepositAmount checks the amount can be deposited, the deposit contract refuses deposits under 1 ETH.
+func ValidateDepositAmount(amountInGwei uint64) error {
+	if amountInGwei < MinDepositAmountInGwei {
+		return fmt.Errorf("deposit amount %d gwei is lower than the minimum of %d gwei", amountInGwei, MinDepositAmountInGwei)
+	}
+	return nil
+}
+
+// ParseDepositAmount parses an ETH decimal amount (e.g. "32" or "1.5") into gwei.
+// The deposit contract only accepts whole gwei, amounts with more than 9 decimals are refused.
+func ParseDepositAmount(amountInEth string) (uint64, error) {
+	parts := strings.Split(amountInEth, ".")
+	if len(parts) > 2 || len(parts[0]) == 0 {
+		return 0, fmt.Errorf("invalid deposit amount %s", amountInEth)
+	}
+
+	eth, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid deposit amount %s", amountInEth)
+	}
+	if eth > math.MaxUint64/MinDepositAmountInGwei {
+		return 0, fmt.Errorf("deposit amount %s is too large", amountInEth)
+	}
+
+	var gwei uint64
+	if len(parts) == 2 {
+		decimals := strings.TrimRight(parts[1], "0")
+		if len(decimals) > gweiDecimals {
+			return 0, fmt.Errorf("deposit amount %s must be a multiple of 1 gwei", amountInEth)
+		}
+		if len(decimals) > 0 {
+			if gwei, err = strconv.ParseUint(decimals+strings.Repeat("0", gweiDecimals-len(decimals)), 10, 64); err != nil {
+				return 0, fmt.Errorf("invalid deposit amount %s", amountInEth)
+			}
+		}
+	}
+
+	ret := eth*MinDepositAmountInGwei + gwei
+	if ret < gwei {
+		return 0, fmt.Errorf("deposit amount %s is too large", amountInEth)
+	}
+	return ret, nil
+}
+
+// DepositValueInWei returns the value, in wei, of a deposit transaction depositing amountInGwei.
+func DepositValueInWei(amountInGwei uint64) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(amountInGwei), big.NewInt(1e9))
+}
@@ -0,0 +1,50 @@
+package eth1_deposit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDepositAmount(t *testing.T) {
+	tests := []struct {
+		amount      string
+		expected    uint64
+		expectedErr string
+	}{
+		{amount: "32", expected: MaxEffectiveBalanceInGwei},
+		{amount: "1", expected: MinDepositAmountInGwei},
+		{amount: "1.5", expected: 1500000000},
+		{amount: "0.000000001", expected: 1},
+		{amount: "2.100000000000", expected: 2100000000},
+		{amount: "0.0000000001", expectedErr: "deposit amount 0.0000000001 must be a multiple of 1 gwei"},
+		{amount: "1.2.3", expectedErr: "invalid deposit amount 1.2.3"},
+		{amount: ".5", expectedErr: "invalid deposit amount .5"},
+		{amount: "-1", expectedErr: "invalid deposit amount -1"},
+		{amount: "1.-5", expectedErr: "invalid deposit amount 1.-5"},
+		{amount: "thirty", expectedErr: "invalid deposit amount thirty"},
+		{amount: "18446744074", expectedErr: "deposit amount 18446744074 is too large"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.amount, func(t *testing.T) {
+			amount, err := ParseDepositAmount(test.amount)
+			if len(test.expectedErr) > 0 {
+				require.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, test.expected, amount)
+		})
+	}
+}
+
+func TestValidateDepositAmount(t *testing.T) {
+	require.NoError(t, ValidateDepositAmount(MinDepositAmountInGwei))
+	require.NoError(t, ValidateDepositAmount(MaxEffectiveBalanceInGwei))
+	require.EqualError(t, ValidateDepositAmount(MinDepositAmountInGwei-1), "deposit amount 999999999 gwei is lower than the minimum of 1000000000 gwei")
+}
+
+func TestDepositValueInWei(t *testing.T) {
+	require.EqualValues(t, "32000000000000000000", DepositValueInWei(MaxEffectiveBalanceInGwei).String())
+}
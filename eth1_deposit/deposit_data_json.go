@@ -0,0 +1,203 @@
+package eth1_deposit
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	ssz "github.com/prysmaticlabs/go-ssz"
+	types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// DepositCLIVersion is the staking-deposit-cli version whose deposit_data format is produced
+const DepositCLIVersion = "2.7.0"
+
+// DepositDataJSON is an entry of the deposit_data-<timestamp>.json files produced by the staking-deposit-cli and
+// consumed by the launchpad. Bytes are hex encoded without a 0x prefix.
+// https://github.com/ethereum/staking-deposit-cli
+type DepositDataJSON struct {
+	PubKey                string `json:"pubkey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	Amount                uint64 `json:"amount"`
+	Signature             string `json:"signature"`
+	DepositMessageRoot    string `json:"deposit_message_root"`
+	DepositDataRoot       string `json:"deposit_data_root"`
+	ForkVersion           string `json:"fork_version"`
+	NetworkName           string `json:"network_name"`
+	DepositCLIVersion     string `json:"deposit_cli_version"`
+}
+
+// DepositDataFileName names deposit data files like the staking-deposit-cli does.
+func DepositDataFileName(created int64) string {
+	return fmt.Sprintf("deposit_data-%d.json", created)
+}
+
+// NewDepositDataJSON is the constructor of DepositDataJSON.
+func NewDepositDataJSON(depositData *ethpb.Deposit_Data, network core.Network) (*DepositDataJSON, error) {
+	messageRoot, err := depositMessageRoot(depositData.GetPublicKey(), depositData.GetWithdrawalCredentials(), depositData.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+	dataRoot, err := ssz.HashTreeRoot(depositData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine the root hash of deposit data")
+	}
+	name, err := networkName(network)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DepositDataJSON{
+		PubKey:                hex.EncodeToString(depositData.GetPublicKey()),
+		WithdrawalCredentials: hex.EncodeToString(depositData.GetWithdrawalCredentials()),
+		Amount:                depositData.GetAmount(),
+		Signature:             hex.EncodeToString(depositData.GetSignature()),
+		DepositMessageRoot:    hex.EncodeToString(messageRoot[:]),
+		DepositDataRoot:       hex.EncodeToString(dataRoot[:]),
+		ForkVersion:           hex.EncodeToString(network.ForkVersion()),
+		NetworkName:           name,
+		DepositCLIVersion:     DepositCLIVersion,
+	}, nil
+}
+
+// ExportDepositData returns the deposit data of the accounts in the staking-deposit-cli format.
+// See ValidatorAccount.GetDepositData for executionAddress and amountInGwei.
+func ExportDepositData(accounts []core.ValidatorAccount, network core.Network, executionAddress []byte, amountInGwei uint64) ([]*DepositDataJSON, error) {
+	ret := make([]*DepositDataJSON, 0, len(accounts))
+	for _, account := range accounts {
+		data, err := account.GetDepositData(executionAddress, amountInGwei)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get deposit data of account %s", account.Name())
+		}
+
+		depositData := &ethpb.Deposit_Data{Amount: data["amount"].(uint64)}
+		if depositData.PublicKey, err = hex.DecodeString(data["publicKey"].(string)); err != nil {
+			return nil, errors.Wrap(err, "failed to HEX decode public key")
+		}
+		if depositData.WithdrawalCredentials, err = hex.DecodeString(data["withdrawalCredentials"].(string)); err != nil {
+			return nil, errors.Wrap(err, "failed to HEX decode withdrawal credentials")
+		}
+		if depositData.Signature, err = hex.DecodeString(data["signature"].(string)); err != nil {
+			return nil, errors.Wrap(err, "failed to HEX decode signature")
+		}
+
+		entry, err := NewDepositDataJSON(depositData, network)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, entry)
+	}
+	return ret, nil
+}
+
+// VerifyDepositData re-checks the deposit data entries were made for the network, their roots and signatures.
+func VerifyDepositData(entries []*DepositDataJSON, network core.Network) error {
+	for i, entry := range entries {
+		if err := verifyDepositDataEntry(entry, network); err != nil {
+			return errors.Wrapf(err, "invalid deposit data %d (%s)", i, entry.PubKey)
+		}
+	}
+	return nil
+}
+
+func verifyDepositDataEntry(entry *DepositDataJSON, network core.Network) error {
+	name, err := networkName(network)
+	if err != nil {
+		return err
+	}
+	if entry.ForkVersion != hex.EncodeToString(network.ForkVersion()) || entry.NetworkName != name {
+		return fmt.Errorf("made for network %s (fork version %s), not %s", entry.NetworkName, entry.ForkVersion, name)
+	}
+
+	pubKey, err := decodeHexField("pubkey", entry.PubKey, 48)
+	if err != nil {
+		return err
+	}
+	withdrawalCredentials, err := decodeHexField("withdrawal_credentials", entry.WithdrawalCredentials, 32)
+	if err != nil {
+		return err
+	}
+	signature, err := decodeHexField("signature", entry.Signature, 96)
+	if err != nil {
+		return err
+	}
+	if err := validateWithdrawalCredentials(withdrawalCredentials); err != nil {
+		return err
+	}
+	if err := ValidateDepositAmount(entry.Amount); err != nil {
+		return err
+	}
+
+	// roots
+	messageRoot, err := depositMessageRoot(pubKey, withdrawalCredentials, entry.Amount)
+	if err != nil {
+		return err
+	}
+	if entry.DepositMessageRoot != hex.EncodeToString(messageRoot[:]) {
+		return fmt.Errorf("deposit_message_root mismatch")
+	}
+	dataRoot, err := ssz.HashTreeRoot(&ethpb.Deposit_Data{
+		PublicKey:             pubKey,
+		WithdrawalCredentials: withdrawalCredentials,
+		Amount:                entry.Amount,
+		Signature:             signature,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to determine the root hash of deposit data")
+	}
+	if entry.DepositDataRoot != hex.EncodeToString(dataRoot[:]) {
+		return fmt.Errorf("deposit_data_root mismatch")
+	}
+
+	// signature
+	signingRoot, err := depositSigningRoot(messageRoot, network.ForkVersion())
+	if err != nil {
+		return err
+	}
+	blsPubKey, err := types.BLSPublicKeyFromBytes(pubKey)
+	if err != nil {
+		return errors.Wrap(err, "invalid pubkey")
+	}
+	blsSignature, err := types.BLSSignatureFromBytes(signature)
+	if err != nil {
+		return errors.Wrap(err, "invalid signature")
+	}
+	if !blsSignature.Verify(signingRoot[:], blsPubKey) {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
+
+func decodeHexField(name string, value string, length int) ([]byte, error) {
+	ret, err := hex.DecodeString(value)
+	if err != nil || len(ret) != length {
+		return nil, fmt.Errorf("%s must be %d hex encoded bytes", name, length)
+	}
+	return ret, nil
+}
+
+// launchpadNetworks are the staking-deposit-cli names of the networks the launchpad knows, by genesis fork version.
+var launchpadNetworks = map[string]string{
+	"00000000": "mainnet",
+	"00001020": "goerli",
+	"90000069": "sepolia",
+	"01017000": "holesky",
+}
+
+// networkName is the staking-deposit-cli name of the network, derived from its fork version so the launchpad name is
+// never given to another network. Other networks keep their own name, unless it's a launchpad one.
+func networkName(network core.Network) (string, error) {
+	forkVersion := hex.EncodeToString(network.ForkVersion())
+	if name, ok := launchpadNetworks[forkVersion]; ok {
+		return name, nil
+	}
+	for _, name := range launchpadNetworks {
+		if string(network) == name {
+			return "", fmt.Errorf("network %s has fork version %s, not the one of the launchpad network", network, forkVersion)
+		}
+	}
+	return string(network), nil
+}
@@ -0,0 +1,181 @@
+package eth1_deposit
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	ssz "github.com/prysmaticlabs/go-ssz"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// depositAccount only implements what ExportDepositData uses
+type depositAccount struct {
+	core.ValidatorAccount
+	key *core.HDKey
+}
+
+func (a *depositAccount) Name() string { return "account-0" }
+func (a *depositAccount) GetDepositData(executionAddress []byte, amountInGwei uint64) (map[string]interface{}, error) {
+	depositData, _, err := DepositData(a.key, ExecutionAddressWithdrawalCredentials(executionAddress), core.TestNetwork, amountInGwei)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"amount":                depositData.GetAmount(),
+		"publicKey":             hex.EncodeToString(depositData.GetPublicKey()),
+		"signature":             hex.EncodeToString(depositData.GetSignature()),
+		"withdrawalCredentials": hex.EncodeToString(depositData.GetWithdrawalCredentials()),
+	}, nil
+}
+
+func testDepositKey(t *testing.T) *core.HDKey {
+	require.NoError(t, e2types.InitBLS())
+	key, err := core.NewHDKeyFromPrivateKey(_ignoreErr(hex.DecodeString("23fd464c122d7fa8c9c8e46d710ae478ab920c8c0587e86556aa968191d5210e")), "")
+	require.NoError(t, err)
+	return key
+}
+
+func TestNewDepositDataJSON(t *testing.T) {
+	key := testDepositKey(t)
+	depositData, _, err := DepositData(
+		key,
+		BLSWithdrawalCredentials(_ignoreErr(hex.DecodeString("b323537b2867d9f2bae068f93e75a9e2e1c8d594e3696c34dc8010dc403eaeeaf43756a440fc82e1c6f45c6e8348343f"))),
+		core.TestNetwork,
+		MaxEffectiveBalanceInGwei,
+	)
+	require.NoError(t, err)
+
+	entry, err := NewDepositDataJSON(depositData, core.TestNetwork)
+	require.NoError(t, err)
+	byts, err := json.Marshal(entry)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"pubkey": "ab321d63b7b991107a5667bf4fe853a266c2baea87d33a41c7e39a5641bfd3b5434b76f1229d452acb45ba86284e3279",
+		"withdrawal_credentials": "00ea056bfaa692b4e12bb1c3f59049dabcfb0b63f427025c718f5e3b81fdb945",
+		"amount": 32000000000,
+		"signature": "aac3de8d5d1700e2519da9346625273ded81a4250bd1b98c50e6587acac9545a1d1598472823aea29220cbc45ae9062f09791dc22252efdd3a1531964e4d62a59511e0f332fb3cc5ea7fe0831de696f040fe806f9f22bd29db0466047584cb23",
+		"deposit_message_root": "8743d96480b704ba867b45f0e29efaa5343cc1086bf32718f26a4246822bb1d2",
+		"deposit_data_root": "5b508bbed40a083809e4d0ee74135c7289020e33e2dbad2e69f41772d09f5a63",
+		"fork_version": "00000001",
+		"network_name": "test",
+		"deposit_cli_version": "2.7.0"
+	}`, string(byts))
+}
+
+func TestNetworkName(t *testing.T) {
+	launchpad, err := core.RegisterNetwork(&core.NetworkConfig{Name: "holesky-copy", GenesisForkVersion: []byte{1, 1, 0x70, 0}})
+	require.NoError(t, err)
+	other, err := core.RegisterNetwork(&core.NetworkConfig{Name: "holesky", GenesisForkVersion: []byte{0, 0, 0, 0x20}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		network     core.Network
+		expected    string
+		expectedErr string
+	}{
+		{network: core.TestNetwork, expected: "test"},
+		{network: core.MainNetwork, expected: "main"}, // not the launchpad mainnet, its fork version is 00000004
+		{network: launchpad, expected: "holesky"},
+		{network: other, expectedErr: "network holesky has fork version 00000020, not the one of the launchpad network"},
+	}
+	for _, test := range tests {
+		t.Run(string(test.network), func(t *testing.T) {
+			name, err := networkName(test.network)
+			if len(test.expectedErr) > 0 {
+				require.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, test.expected, name)
+		})
+	}
+}
+
+func TestExportAndVerifyDepositData(t *testing.T) {
+	account := &depositAccount{key: testDepositKey(t)}
+	address := _ignoreErr(hex.DecodeString("8ba1f109551bd432803012645ac136ddd64dba72"))
+
+	entries, err := ExportDepositData([]core.ValidatorAccount{account}, core.TestNetwork, address, MaxEffectiveBalanceInGwei)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.EqualValues(t, "0100000000000000000000008ba1f109551bd432803012645ac136ddd64dba72", entries[0].WithdrawalCredentials)
+	require.NoError(t, VerifyDepositData(entries, core.TestNetwork))
+
+	tests := []struct {
+		name        string
+		tamper      func(entry *DepositDataJSON)
+		network     core.Network
+		expectedErr string
+	}{
+		{
+			name:        "other network",
+			tamper:      func(entry *DepositDataJSON) {},
+			network:     core.MainNetwork,
+			expectedErr: "made for network test (fork version 00000001), not main",
+		},
+		{
+			name:        "amount",
+			tamper:      func(entry *DepositDataJSON) { entry.Amount = MinDepositAmountInGwei },
+			expectedErr: "deposit_message_root mismatch",
+		},
+		{
+			name: "deposit data root",
+			tamper: func(entry *DepositDataJSON) {
+				entry.DepositDataRoot = "5b508bbed40a083809e4d0ee74135c7289020e33e2dbad2e69f41772d09f5a63"
+			},
+			expectedErr: "deposit_data_root mismatch",
+		},
+		{
+			name:        "pubkey",
+			tamper:      func(entry *DepositDataJSON) { entry.PubKey = "ab32" },
+			expectedErr: "pubkey must be 48 hex encoded bytes",
+		},
+		{
+			name:        "amount lower than the minimum",
+			tamper:      func(entry *DepositDataJSON) { entry.Amount = 1 },
+			expectedErr: "deposit amount 1 gwei is lower than the minimum of 1000000000 gwei",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entry := *entries[0]
+			test.tamper(&entry)
+			network := core.TestNetwork
+			if len(test.network) > 0 {
+				network = test.network
+			}
+			err := VerifyDepositData([]*DepositDataJSON{&entry}, network)
+			require.EqualError(t, err, "invalid deposit data 0 ("+entry.PubKey+"): "+test.expectedErr)
+		})
+	}
+
+	t.Run("signature", func(t *testing.T) {
+		other, err := ExportDepositData([]core.ValidatorAccount{account}, core.TestNetwork, address, MinDepositAmountInGwei)
+		require.NoError(t, err)
+
+		// roots of the entry with the signature of another deposit
+		entry := *entries[0]
+		entry.Signature = other[0].Signature
+		dataRoot, err := entryDataRoot(&entry)
+		require.NoError(t, err)
+		entry.DepositDataRoot = dataRoot
+		err = VerifyDepositData([]*DepositDataJSON{&entry}, core.TestNetwork)
+		require.EqualError(t, err, "invalid deposit data 0 ("+entry.PubKey+"): signature verification failed")
+	})
+}
+
+// entryDataRoot recomputes the deposit_data_root of the entry
+func entryDataRoot(entry *DepositDataJSON) (string, error) {
+	root, err := ssz.HashTreeRoot(&ethpb.Deposit_Data{
+		PublicKey:             _ignoreErr(hex.DecodeString(entry.PubKey)),
+		WithdrawalCredentials: _ignoreErr(hex.DecodeString(entry.WithdrawalCredentials)),
+		Amount:                entry.Amount,
+		Signature:             _ignoreErr(hex.DecodeString(entry.Signature)),
+	})
+	return hex.EncodeToString(root[:]), err
+}
@@ -1,6 +1,9 @@
 package eth1_deposit
 
 import (
+	"bytes"
+	"fmt"
+
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	ssz "github.com/prysmaticlabs/go-ssz"
@@ -11,40 +14,32 @@ import (
 )
 
 const (
-	MaxEffectiveBalanceInGwei uint64 = 32000000000
-	BLSWithdrawalPrefixByte   byte   = byte(0)
+	MaxEffectiveBalanceInGwei            uint64 = 32000000000
+	MinDepositAmountInGwei               uint64 = 1000000000
+	BLSWithdrawalPrefixByte              byte   = byte(0)
+	ExecutionAddressWithdrawalPrefixByte byte   = byte(1)
 )
 
 // DepositData is basically copied from https://github.com/prysmaticlabs/prysm/blob/master/shared/keystore/deposit_input.go
-func DepositData(validationKey *core.HDKey, withdrawalPubKey []byte, network core.Network, amountInGwei uint64) (*ethpb.Deposit_Data, [32]byte, error) {
-	depositData := struct {
-		PublicKey             []byte `ssz-size:"48"`
-		WithdrawalCredentials []byte `ssz-size:"32"`
-		Amount                uint64
-	}{
-		PublicKey:             validationKey.PublicKey().Marshal(),
-		WithdrawalCredentials: withdrawalCredentialsHash(withdrawalPubKey),
-		Amount:                amountInGwei,
+// withdrawalCredentials are either BLS (see BLSWithdrawalCredentials) or execution address (see
+// ExecutionAddressWithdrawalCredentials) credentials.
+// amountInGwei is MaxEffectiveBalanceInGwei for a full deposit, less for partial ones or top-ups, it can't be lower
+// than MinDepositAmountInGwei (which the deposit contract enforces).
+func DepositData(validationKey *core.HDKey, withdrawalCredentials []byte, network core.Network, amountInGwei uint64) (*ethpb.Deposit_Data, [32]byte, error) {
+	if err := validateWithdrawalCredentials(withdrawalCredentials); err != nil {
+		return nil, [32]byte{}, err
 	}
-	objRoot, err := ssz.HashTreeRoot(depositData)
-	if err != nil {
-		return nil, [32]byte{}, errors.Wrap(err, "failed to determine the root hash of deposit data")
+	if err := ValidateDepositAmount(amountInGwei); err != nil {
+		return nil, [32]byte{}, err
 	}
 
-	// Create domain
-	domain := types.Domain(types.DomainDeposit, network.ForkVersion(), types.ZeroGenesisValidatorsRoot)
-
-	// Prepare for sig
-	signingContainer := struct {
-		Root   []byte `json:"object_root,omitempty" ssz-size:"32"`
-		Domain []byte `json:"domain,omitempty" ssz-size:"32"`
-	}{
-		Root:   objRoot[:],
-		Domain: domain,
+	objRoot, err := depositMessageRoot(validationKey.PublicKey().Marshal(), withdrawalCredentials, amountInGwei)
+	if err != nil {
+		return nil, [32]byte{}, err
 	}
-	root, err := ssz.HashTreeRoot(signingContainer)
+	root, err := depositSigningRoot(objRoot, network.ForkVersion())
 	if err != nil {
-		return nil, [32]byte{}, errors.Wrap(err, "failed to determine the root hash of signing container")
+		return nil, [32]byte{}, err
 	}
 
 	// Sign
@@ -55,7 +50,7 @@ func DepositData(validationKey *core.HDKey, withdrawalPubKey []byte, network cor
 
 	signedDepositData := &ethpb.Deposit_Data{
 		PublicKey:             validationKey.PublicKey().Marshal(),
-		WithdrawalCredentials: withdrawalCredentialsHash(withdrawalPubKey),
+		WithdrawalCredentials: withdrawalCredentials,
 		Amount:                amountInGwei,
 		Signature:             sig.Marshal(),
 	}
@@ -68,14 +63,83 @@ func DepositData(validationKey *core.HDKey, withdrawalPubKey []byte, network cor
 	return signedDepositData, depositDataRoot, nil
 }
 
-// withdrawalCredentialsHash forms a 32 byte hash of the withdrawal public
+// depositMessageRoot is the root of the DepositMessage (the deposit data without its signature).
+func depositMessageRoot(publicKey []byte, withdrawalCredentials []byte, amountInGwei uint64) ([32]byte, error) {
+	depositMessage := struct {
+		PublicKey             []byte `ssz-size:"48"`
+		WithdrawalCredentials []byte `ssz-size:"32"`
+		Amount                uint64
+	}{
+		PublicKey:             publicKey,
+		WithdrawalCredentials: withdrawalCredentials,
+		Amount:                amountInGwei,
+	}
+	root, err := ssz.HashTreeRoot(depositMessage)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "failed to determine the root hash of deposit data")
+	}
+	return root, nil
+}
+
+// depositSigningRoot is the root deposits sign, deposits are signed with the genesis fork version and a zero
+// genesis validators root so they are valid before genesis.
+func depositSigningRoot(messageRoot [32]byte, forkVersion []byte) ([32]byte, error) {
+	// Create domain
+	domain := types.Domain(types.DomainDeposit, forkVersion, types.ZeroGenesisValidatorsRoot)
+
+	// Prepare for sig
+	signingContainer := struct {
+		Root   []byte `json:"object_root,omitempty" ssz-size:"32"`
+		Domain []byte `json:"domain,omitempty" ssz-size:"32"`
+	}{
+		Root:   messageRoot[:],
+		Domain: domain,
+	}
+	root, err := ssz.HashTreeRoot(signingContainer)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "failed to determine the root hash of signing container")
+	}
+	return root, nil
+}
+
+// BLSWithdrawalCredentials forms a 32 byte hash of the withdrawal public
 // address.
 //
 // The specification is as follows:
 //   withdrawal_credentials[:1] == BLS_WITHDRAWAL_PREFIX_BYTE
 //   withdrawal_credentials[1:] == hash(withdrawal_pubkey)[1:]
 // where withdrawal_credentials is of type bytes32.
-func withdrawalCredentialsHash(withdrawalPubKey []byte) []byte {
+func BLSWithdrawalCredentials(withdrawalPubKey []byte) []byte {
 	h := util.SHA256(withdrawalPubKey)
 	return append([]byte{BLSWithdrawalPrefixByte}, h[1:]...)[:32]
 }
+
+// ExecutionAddressWithdrawalCredentials forms the 32 byte credentials withdrawing to an execution address.
+//
+// The specification is as follows:
+//   withdrawal_credentials[:1] == ETH1_ADDRESS_WITHDRAWAL_PREFIX
+//   withdrawal_credentials[1:12] == b'\x00' * 11
+//   withdrawal_credentials[12:] == eth1_withdrawal_address
+func ExecutionAddressWithdrawalCredentials(executionAddress []byte) []byte {
+	ret := make([]byte, 32)
+	ret[0] = ExecutionAddressWithdrawalPrefixByte
+	copy(ret[12:], executionAddress)
+	return ret
+}
+
+func validateWithdrawalCredentials(withdrawalCredentials []byte) error {
+	if len(withdrawalCredentials) != 32 {
+		return fmt.Errorf("withdrawal credentials must be 32 bytes")
+	}
+	switch withdrawalCredentials[0] {
+	case BLSWithdrawalPrefixByte:
+		return nil
+	case ExecutionAddressWithdrawalPrefixByte:
+		if !bytes.Equal(withdrawalCredentials[1:12], make([]byte, 11)) {
+			return fmt.Errorf("execution address withdrawal credentials must be zero padded")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported withdrawal credentials prefix %#x", withdrawalCredentials[0])
+	}
+}
@@ -33,18 +33,27 @@ func TestDepositData(t *testing.T) {
 	tests := []struct {
 		testname                      string
 		validatorPrivKey              []byte
-		withdrawalPubKey              []byte
+		withdrawalCredentials         []byte
 		expectedWithdrawalCredentials []byte
 		expectedSig                   []byte
 		expectedRoot                  []byte
 	}{
 		{
+			testname:                      "bls withdrawal credentials",
 			validatorPrivKey:              _ignoreErr(hex.DecodeString("23fd464c122d7fa8c9c8e46d710ae478ab920c8c0587e86556aa968191d5210e")),
-			withdrawalPubKey:              _ignoreErr(hex.DecodeString("b323537b2867d9f2bae068f93e75a9e2e1c8d594e3696c34dc8010dc403eaeeaf43756a440fc82e1c6f45c6e8348343f")),
+			withdrawalCredentials:         BLSWithdrawalCredentials(_ignoreErr(hex.DecodeString("b323537b2867d9f2bae068f93e75a9e2e1c8d594e3696c34dc8010dc403eaeeaf43756a440fc82e1c6f45c6e8348343f"))),
 			expectedWithdrawalCredentials: _ignoreErr(hex.DecodeString("00ea056bfaa692b4e12bb1c3f59049dabcfb0b63f427025c718f5e3b81fdb945")),
 			expectedSig:                   _ignoreErr(hex.DecodeString("aac3de8d5d1700e2519da9346625273ded81a4250bd1b98c50e6587acac9545a1d1598472823aea29220cbc45ae9062f09791dc22252efdd3a1531964e4d62a59511e0f332fb3cc5ea7fe0831de696f040fe806f9f22bd29db0466047584cb23")),
 			expectedRoot:                  _ignoreErr(hex.DecodeString("5b508bbed40a083809e4d0ee74135c7289020e33e2dbad2e69f41772d09f5a63")),
 		},
+		{
+			testname:                      "execution address withdrawal credentials",
+			validatorPrivKey:              _ignoreErr(hex.DecodeString("23fd464c122d7fa8c9c8e46d710ae478ab920c8c0587e86556aa968191d5210e")),
+			withdrawalCredentials:         ExecutionAddressWithdrawalCredentials(_ignoreErr(hex.DecodeString("8ba1f109551bd432803012645ac136ddd64dba72"))),
+			expectedWithdrawalCredentials: _ignoreErr(hex.DecodeString("0100000000000000000000008ba1f109551bd432803012645ac136ddd64dba72")),
+			expectedSig:                   _ignoreErr(hex.DecodeString("9527748b2bfd034f0425dea5deb9606d7e8afdfdf198cde0dcc9bb179afa6e6c6896fb21ab5d012dac797b0c5ed52d8c0dde1fc0c472c51a2aa9b0d3f3f012f7ae683ccd6674f84d5bada2c41a17f281543c713467e3c8b6e0cfbb724fc88c4a")),
+			expectedRoot:                  _ignoreErr(hex.DecodeString("3848b836d19d00f0d9202889c7156dac33e99335e868aec0252f329d2a6e7485")),
+		},
 	}
 
 	e2types.InitBLS()
@@ -57,7 +66,7 @@ func TestDepositData(t *testing.T) {
 			// create data
 			depositData, root, err := DepositData(
 				val,
-				test.withdrawalPubKey,
+				test.withdrawalCredentials,
 				core.TestNetwork,
 				MaxEffectiveBalanceInGwei,
 			)
@@ -77,3 +86,20 @@ func TestDepositData(t *testing.T) {
 		})
 	}
 }
+
+func TestDepositDataInvalidWithdrawalCredentials(t *testing.T) {
+	e2types.InitBLS()
+	val, err := core.NewHDKeyFromPrivateKey(_ignoreErr(hex.DecodeString("23fd464c122d7fa8c9c8e46d710ae478ab920c8c0587e86556aa968191d5210e")), "")
+	require.NoError(t, err)
+
+	_, _, err = DepositData(val, make([]byte, 31), core.TestNetwork, MaxEffectiveBalanceInGwei)
+	require.EqualError(t, err, "withdrawal credentials must be 32 bytes")
+
+	_, _, err = DepositData(val, append([]byte{2}, make([]byte, 31)...), core.TestNetwork, MaxEffectiveBalanceInGwei)
+	require.EqualError(t, err, "unsupported withdrawal credentials prefix 0x2")
+
+	credentials := ExecutionAddressWithdrawalCredentials(make([]byte, 20))
+	credentials[5] = 1
+	_, _, err = DepositData(val, credentials, core.TestNetwork, MaxEffectiveBalanceInGwei)
+	require.EqualError(t, err, "execution address withdrawal credentials must be zero padded")
+}
@@ -0,0 +1,49 @@
+package eth1_deposit
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ParseExecutionAddress decodes a 0x prefixed execution (eth1) address.
+// Mixed case addresses must have a valid EIP-55 checksum, all lower or upper case ones are accepted as is.
+// https://eips.ethereum.org/EIPS/eip-55
+func ParseExecutionAddress(address string) ([]byte, error) {
+	if !strings.HasPrefix(address, "0x") || len(address) != 42 {
+		return nil, fmt.Errorf("invalid execution address %s, expected 0x followed by 40 hex characters", address)
+	}
+	ret, err := hex.DecodeString(address[2:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid execution address %s, expected 0x followed by 40 hex characters", address)
+	}
+
+	hexAddress := address[2:]
+	if hexAddress != strings.ToLower(hexAddress) && hexAddress != strings.ToUpper(hexAddress) && checksumAddress(ret) != address {
+		return nil, fmt.Errorf("invalid execution address checksum %s", address)
+	}
+	return ret, nil
+}
+
+// checksumAddress returns the EIP-55 mixed case encoding of the address.
+func checksumAddress(address []byte) string {
+	lower := hex.EncodeToString(address)
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(lower))
+	digest := hash.Sum(nil)
+
+	ret := []byte(lower)
+	for i := range ret {
+		// a letter is upper cased if the matching nibble of the hash is >= 8
+		nibble := digest[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if ret[i] >= 'a' && nibble&0xf >= 8 {
+			ret[i] -= 'a' - 'A'
+		}
+	}
+	return "0x" + string(ret)
+}
@@ -0,0 +1,61 @@
+package eth1_deposit
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExecutionAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		address     string
+		expectedErr string
+	}{
+		{
+			name:    "checksummed",
+			address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		},
+		{
+			name:    "lower case",
+			address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		},
+		{
+			name:    "upper case",
+			address: "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED",
+		},
+		{
+			name:        "wrong checksum",
+			address:     "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",
+			expectedErr: "invalid execution address checksum 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",
+		},
+		{
+			name:        "no prefix",
+			address:     "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			expectedErr: "invalid execution address 5aaeb6053f3e94c9b9a09f33669435e7ef1beaed, expected 0x followed by 40 hex characters",
+		},
+		{
+			name:        "too short",
+			address:     "0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea",
+			expectedErr: "invalid execution address 0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea, expected 0x followed by 40 hex characters",
+		},
+		{
+			name:        "not hex",
+			address:     "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beazz",
+			expectedErr: "invalid execution address 0x5aaeb6053f3e94c9b9a09f33669435e7ef1beazz, expected 0x followed by 40 hex characters",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			address, err := ParseExecutionAddress(test.address)
+			if len(test.expectedErr) > 0 {
+				require.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", hex.EncodeToString(address))
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/bloxapp/eth2-key-manager/core"
 	"github.com/bloxapp/eth2-key-manager/wallet_hd"
+	"github.com/bloxapp/eth2-key-manager/wallet_nd"
 )
 
 var initBLSOnce sync.Once
@@ -89,7 +90,15 @@ func NewKeyVault(options *KeyVaultOptions) (*KeyVault, error) {
 	}
 
 	// update wallet context
-	wallet := wallet_hd.NewHDWallet(context)
+	var wallet core.Wallet
+	switch options.walletType {
+	case "", core.HDWallet:
+		wallet = wallet_hd.NewHDWallet(context)
+	case core.ND:
+		wallet = wallet_nd.NewNDWallet(context)
+	default:
+		return nil, fmt.Errorf("unknown wallet type %s", options.walletType)
+	}
 
 	ret := &KeyVault{
 		Context:  context,
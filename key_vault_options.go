@@ -2,13 +2,16 @@ package eth2keymanager
 
 import (
 	wtypes "github.com/wealdtech/go-eth2-wallet-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
 )
 
 type KeyVaultOptions struct {
-	encryptor wtypes.Encryptor
-	password  []byte
-	storage   interface{} // a generic interface as there are a few core storage interfaces (storage, slashing storage and so on)
-	seed      []byte
+	encryptor  wtypes.Encryptor
+	password   []byte
+	storage    interface{} // a generic interface as there are a few core storage interfaces (storage, slashing storage and so on)
+	seed       []byte
+	walletType core.WalletType
 }
 
 func (options *KeyVaultOptions) SetEncryptor(encryptor wtypes.Encryptor) *KeyVaultOptions {
@@ -30,3 +33,9 @@ func (options *KeyVaultOptions) SetSeed(seed []byte) *KeyVaultOptions {
 	options.seed = seed
 	return options
 }
+
+// SetWalletType sets the type of wallet NewKeyVault creates, defaults to core.HDWallet
+func (options *KeyVaultOptions) SetWalletType(walletType core.WalletType) *KeyVaultOptions {
+	options.walletType = walletType
+	return options
+}
@@ -0,0 +1,215 @@
+package grpc_signer
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	"google.golang.org/grpc"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/validator_signer"
+)
+
+// Server implements the eth2-signer-api Lister, Signer and AccountManager services on top of a ValidatorSigner so
+// Dirk-style clients can use the key vault directly.
+// Failures are reported through the response state (as the api defines) and not as grpc errors.
+type Server struct {
+	pb.UnimplementedListerServer
+	pb.UnimplementedSignerServer
+	pb.UnimplementedAccountManagerServer
+
+	wallet     core.Wallet
+	signer     validator_signer.ValidatorSigner
+	passphrase []byte
+
+	namesLock sync.Mutex
+	names     map[string][]byte // account name -> public key, rebuilt from the wallet on unknown or stale names
+
+	lockedLock sync.RWMutex
+	locked     map[string]bool // hex public key -> locked
+}
+
+// NewServer is the constructor of Server, wallet is the wallet the signer signs with.
+// Accounts start unlocked, passphrase is required to unlock an account locked with Lock (nil makes locks permanent).
+func NewServer(wallet core.Wallet, signer validator_signer.ValidatorSigner, passphrase []byte) *Server {
+	return &Server{
+		wallet:     wallet,
+		signer:     signer,
+		passphrase: passphrase,
+		names:      make(map[string][]byte),
+		locked:     make(map[string]bool),
+	}
+}
+
+// Register registers the Lister, Signer and AccountManager services on the given grpc server.
+func (server *Server) Register(grpcServer *grpc.Server) {
+	pb.RegisterListerServer(grpcServer, server)
+	pb.RegisterSignerServer(grpcServer, server)
+	pb.RegisterAccountManagerServer(grpcServer, server)
+}
+
+// ListAccounts implements pb.ListerServer.
+// Paths are account names, optionally prefixed by a wallet name ("wallet/account"), no paths lists all accounts.
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	res, err := server.signer.ListAccounts()
+	if err != nil {
+		return &pb.ListAccountsResponse{State: pb.ResponseState_FAILED}, nil
+	}
+	if len(req.GetPaths()) == 0 {
+		return res, nil
+	}
+
+	accounts := make([]*pb.Account, 0)
+	for _, account := range res.GetAccounts() {
+		for _, path := range req.GetPaths() {
+			if accountName(path) == account.GetName() {
+				accounts = append(accounts, account)
+				break
+			}
+		}
+	}
+	return &pb.ListAccountsResponse{
+		State:    pb.ResponseState_SUCCEEDED,
+		Accounts: accounts,
+	}, nil
+}
+
+// Sign implements pb.SignerServer, it's always DENIED as arbitrary roots can't be checked by the slashing protection.
+func (server *Server) Sign(ctx context.Context, req *pb.SignRequest) (*pb.SignResponse, error) {
+	return &pb.SignResponse{State: pb.ResponseState_DENIED}, nil
+}
+
+// SignBeaconAttestation implements pb.SignerServer, slashable attestations are DENIED.
+func (server *Server) SignBeaconAttestation(ctx context.Context, req *pb.SignBeaconAttestationRequest) (*pb.SignResponse, error) {
+	pubKey, state := server.signingKey(req.GetPublicKey(), req.GetAccount())
+	if state != pb.ResponseState_SUCCEEDED {
+		return &pb.SignResponse{State: state}, nil
+	}
+	return signResponse(server.signer.SignBeaconAttestation(&pb.SignBeaconAttestationRequest{
+		Id:     &pb.SignBeaconAttestationRequest_PublicKey{PublicKey: pubKey},
+		Domain: req.GetDomain(),
+		Data:   req.GetData(),
+	}))
+}
+
+// SignBeaconProposal implements pb.SignerServer, slashable proposals are DENIED.
+func (server *Server) SignBeaconProposal(ctx context.Context, req *pb.SignBeaconProposalRequest) (*pb.SignResponse, error) {
+	pubKey, state := server.signingKey(req.GetPublicKey(), req.GetAccount())
+	if state != pb.ResponseState_SUCCEEDED {
+		return &pb.SignResponse{State: state}, nil
+	}
+	return signResponse(server.signer.SignBeaconProposal(&pb.SignBeaconProposalRequest{
+		Id:     &pb.SignBeaconProposalRequest_PublicKey{PublicKey: pubKey},
+		Domain: req.GetDomain(),
+		Data:   req.GetData(),
+	}))
+}
+
+// Lock implements pb.AccountManagerServer, a locked account refuses to sign until unlocked.
+func (server *Server) Lock(ctx context.Context, req *pb.LockAccountRequest) (*pb.LockAccountResponse, error) {
+	pubKey, err := server.accountPublicKey(req.GetAccount())
+	if err != nil {
+		return &pb.LockAccountResponse{State: pb.ResponseState_FAILED}, nil
+	}
+	if pubKey == nil {
+		return &pb.LockAccountResponse{State: pb.ResponseState_DENIED}, nil
+	}
+
+	server.lockedLock.Lock()
+	defer server.lockedLock.Unlock()
+	server.locked[hex.EncodeToString(pubKey)] = true
+	return &pb.LockAccountResponse{State: pb.ResponseState_SUCCEEDED}, nil
+}
+
+// Unlock implements pb.AccountManagerServer.
+func (server *Server) Unlock(ctx context.Context, req *pb.UnlockAccountRequest) (*pb.UnlockAccountResponse, error) {
+	pubKey, err := server.accountPublicKey(req.GetAccount())
+	if err != nil {
+		return &pb.UnlockAccountResponse{State: pb.ResponseState_FAILED}, nil
+	}
+	if pubKey == nil || len(server.passphrase) == 0 || subtle.ConstantTimeCompare(server.passphrase, req.GetPassphrase()) != 1 {
+		return &pb.UnlockAccountResponse{State: pb.ResponseState_DENIED}, nil
+	}
+
+	server.lockedLock.Lock()
+	defer server.lockedLock.Unlock()
+	delete(server.locked, hex.EncodeToString(pubKey))
+	return &pb.UnlockAccountResponse{State: pb.ResponseState_SUCCEEDED}, nil
+}
+
+// signingKey resolves the public key a request should be signed with, requests name an account either by public key
+// or by name. Unknown and locked accounts are DENIED.
+func (server *Server) signingKey(pubKey []byte, account string) ([]byte, pb.ResponseState) {
+	if pubKey != nil {
+		account = hex.EncodeToString(pubKey)
+	}
+	pubKey, err := server.accountPublicKey(account)
+	if err != nil {
+		return nil, pb.ResponseState_FAILED
+	}
+	if pubKey == nil {
+		return nil, pb.ResponseState_DENIED
+	}
+
+	server.lockedLock.RLock()
+	defer server.lockedLock.RUnlock()
+	if server.locked[hex.EncodeToString(pubKey)] {
+		return nil, pb.ResponseState_DENIED
+	}
+	return pubKey, pb.ResponseState_SUCCEEDED
+}
+
+// accountPublicKey returns the public key of the account with the given name (or hex public key), nil if not found.
+func (server *Server) accountPublicKey(account string) ([]byte, error) {
+	name := accountName(account)
+	if pubKey, err := hex.DecodeString(strings.TrimPrefix(name, "0x")); err == nil && len(pubKey) > 0 {
+		acc, err := server.wallet.AccountByPublicKey(hex.EncodeToString(pubKey))
+		if err == nil {
+			return acc.ValidatorPublicKey().Marshal(), nil
+		}
+		if !errors.Is(err, core.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	server.namesLock.Lock()
+	defer server.namesLock.Unlock()
+	if pubKey, ok := server.names[name]; ok {
+		// the account could have been deleted (or replaced by another one with the same name) since it was cached
+		acc, err := server.wallet.AccountByPublicKey(hex.EncodeToString(pubKey))
+		if err == nil && acc.Name() == name {
+			return pubKey, nil
+		}
+		if err != nil && !errors.Is(err, core.ErrNotFound) {
+			return nil, err
+		}
+	}
+	server.names = make(map[string][]byte)
+	for _, acc := range server.wallet.Accounts() {
+		server.names[acc.Name()] = acc.ValidatorPublicKey().Marshal()
+	}
+	return server.names[name], nil
+}
+
+// accountName strips the wallet name from "wallet/account" paths.
+func accountName(path string) string {
+	if idx := strings.LastIndex(path, "/"); idx != -1 {
+		return path[idx+1:]
+	}
+	return path
+}
+
+func signResponse(res *pb.SignResponse, err error) (*pb.SignResponse, error) {
+	if err != nil {
+		if errors.Is(err, core.ErrSlashable) {
+			return &pb.SignResponse{State: pb.ResponseState_DENIED}, nil
+		}
+		return &pb.SignResponse{State: pb.ResponseState_FAILED}, nil
+	}
+	return res, nil
+}
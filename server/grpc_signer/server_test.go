@@ -0,0 +1,215 @@
+package grpc_signer
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	"google.golang.org/grpc"
+
+	eth2keymanager "github.com/bloxapp/eth2-key-manager"
+	"github.com/bloxapp/eth2-key-manager/core"
+	prot "github.com/bloxapp/eth2-key-manager/slashing_protection"
+	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+	"github.com/bloxapp/eth2-key-manager/validator_signer"
+)
+
+var passphrase = []byte("passphrase")
+
+func setupServer(t *testing.T) (*Server, core.ValidatorAccount) {
+	require.NoError(t, e2types.InitBLS())
+	seed := _byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+
+	store := in_memory.NewInMemStore(core.TestNetwork)
+	options := &eth2keymanager.KeyVaultOptions{}
+	options.SetStorage(store)
+	options.SetSeed(seed)
+	vault, err := eth2keymanager.NewKeyVault(options)
+	require.NoError(t, err)
+	wallet, err := vault.Wallet()
+	require.NoError(t, err)
+	account, err := wallet.CreateValidatorAccount(seed, nil)
+	require.NoError(t, err)
+
+	signer := validator_signer.NewSimpleSigner(wallet, prot.NewNormalProtection(store))
+	return NewServer(wallet, signer, passphrase), account
+}
+
+func _byteArray(input string) []byte {
+	res, _ := hex.DecodeString(input)
+	return res
+}
+
+func attestationRequest(account string, source uint64, target uint64, root []byte) *pb.SignBeaconAttestationRequest {
+	return &pb.SignBeaconAttestationRequest{
+		Id:     &pb.SignBeaconAttestationRequest_Account{Account: account},
+		Domain: make([]byte, 32),
+		Data: &pb.AttestationData{
+			Slot:            target * core.SlotsPerEpoch,
+			CommitteeIndex:  1,
+			BeaconBlockRoot: root,
+			Source:          &pb.Checkpoint{Epoch: source, Root: root},
+			Target:          &pb.Checkpoint{Epoch: target, Root: root},
+		},
+	}
+}
+
+func proposalRequest(pubKey []byte, slot uint64, root []byte) *pb.SignBeaconProposalRequest {
+	return &pb.SignBeaconProposalRequest{
+		Id:     &pb.SignBeaconProposalRequest_PublicKey{PublicKey: pubKey},
+		Domain: make([]byte, 32),
+		Data: &pb.BeaconBlockHeader{
+			Slot:          slot,
+			ProposerIndex: 1,
+			ParentRoot:    root,
+			StateRoot:     root,
+			BodyRoot:      root,
+		},
+	}
+}
+
+func TestRegister(t *testing.T) {
+	server, _ := setupServer(t)
+	server.Register(grpc.NewServer())
+}
+
+func TestListAccounts(t *testing.T) {
+	server, account := setupServer(t)
+
+	res, err := server.ListAccounts(context.Background(), &pb.ListAccountsRequest{})
+	require.NoError(t, err)
+	require.EqualValues(t, pb.ResponseState_SUCCEEDED, res.GetState())
+	require.Len(t, res.GetAccounts(), 1)
+	require.EqualValues(t, account.Name(), res.GetAccounts()[0].GetName())
+	require.EqualValues(t, account.ValidatorPublicKey().Marshal(), res.GetAccounts()[0].GetPublicKey())
+
+	t.Run("by wallet path", func(t *testing.T) {
+		res, err := server.ListAccounts(context.Background(), &pb.ListAccountsRequest{Paths: []string{"wallet/" + account.Name()}})
+		require.NoError(t, err)
+		require.Len(t, res.GetAccounts(), 1)
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		res, err := server.ListAccounts(context.Background(), &pb.ListAccountsRequest{Paths: []string{"wallet/unknown"}})
+		require.NoError(t, err)
+		require.EqualValues(t, pb.ResponseState_SUCCEEDED, res.GetState())
+		require.Len(t, res.GetAccounts(), 0)
+	})
+}
+
+func TestSign(t *testing.T) {
+	server, account := setupServer(t)
+
+	// arbitrary roots aren't signed, they could be slashable messages
+	res, err := server.Sign(context.Background(), &pb.SignRequest{
+		Id:     &pb.SignRequest_Account{Account: "wallet/" + account.Name()},
+		Data:   make([]byte, 32),
+		Domain: make([]byte, 32),
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, pb.ResponseState_DENIED, res.GetState())
+	require.Empty(t, res.GetSignature())
+}
+
+func TestSignBeaconAttestation(t *testing.T) {
+	server, account := setupServer(t)
+	root := _byteArray("A000000000000000000000000000000000000000000000000000000000000000")
+
+	res, err := server.SignBeaconAttestation(context.Background(), attestationRequest(account.Name(), 1, 2, root))
+	require.NoError(t, err)
+	require.EqualValues(t, pb.ResponseState_SUCCEEDED, res.GetState())
+	require.NotEmpty(t, res.GetSignature())
+
+	t.Run("double vote is denied", func(t *testing.T) {
+		other := _byteArray("B000000000000000000000000000000000000000000000000000000000000000")
+		res, err := server.SignBeaconAttestation(context.Background(), attestationRequest(account.Name(), 1, 2, other))
+		require.NoError(t, err)
+		require.EqualValues(t, pb.ResponseState_DENIED, res.GetState())
+		require.Empty(t, res.GetSignature())
+	})
+}
+
+func TestSignBeaconProposal(t *testing.T) {
+	server, account := setupServer(t)
+	pubKey := account.ValidatorPublicKey().Marshal()
+	root := _byteArray("A000000000000000000000000000000000000000000000000000000000000000")
+
+	res, err := server.SignBeaconProposal(context.Background(), proposalRequest(pubKey, 10, root))
+	require.NoError(t, err)
+	require.EqualValues(t, pb.ResponseState_SUCCEEDED, res.GetState())
+	require.NotEmpty(t, res.GetSignature())
+
+	t.Run("double proposal is denied", func(t *testing.T) {
+		other := _byteArray("B000000000000000000000000000000000000000000000000000000000000000")
+		res, err := server.SignBeaconProposal(context.Background(), proposalRequest(pubKey, 10, other))
+		require.NoError(t, err)
+		require.EqualValues(t, pb.ResponseState_DENIED, res.GetState())
+	})
+
+	t.Run("unknown public key is denied", func(t *testing.T) {
+		res, err := server.SignBeaconProposal(context.Background(), proposalRequest(make([]byte, 48), 11, root))
+		require.NoError(t, err)
+		require.EqualValues(t, pb.ResponseState_DENIED, res.GetState())
+	})
+}
+
+func TestLockUnlock(t *testing.T) {
+	server, account := setupServer(t)
+	pubKey := account.ValidatorPublicKey().Marshal()
+	root := _byteArray("A000000000000000000000000000000000000000000000000000000000000000")
+
+	lockRes, err := server.Lock(context.Background(), &pb.LockAccountRequest{Account: "wallet/" + account.Name()})
+	require.NoError(t, err)
+	require.EqualValues(t, pb.ResponseState_SUCCEEDED, lockRes.GetState())
+
+	res, err := server.SignBeaconProposal(context.Background(), proposalRequest(pubKey, 10, root))
+	require.NoError(t, err)
+	require.EqualValues(t, pb.ResponseState_DENIED, res.GetState())
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		res, err := server.Unlock(context.Background(), &pb.UnlockAccountRequest{Account: account.Name(), Passphrase: []byte("wrong")})
+		require.NoError(t, err)
+		require.EqualValues(t, pb.ResponseState_DENIED, res.GetState())
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		res, err := server.Lock(context.Background(), &pb.LockAccountRequest{Account: "wallet/unknown"})
+		require.NoError(t, err)
+		require.EqualValues(t, pb.ResponseState_DENIED, res.GetState())
+	})
+
+	unlockRes, err := server.Unlock(context.Background(), &pb.UnlockAccountRequest{Account: account.Name(), Passphrase: passphrase})
+	require.NoError(t, err)
+	require.EqualValues(t, pb.ResponseState_SUCCEEDED, unlockRes.GetState())
+
+	res, err = server.SignBeaconProposal(context.Background(), proposalRequest(pubKey, 10, root))
+	require.NoError(t, err)
+	require.EqualValues(t, pb.ResponseState_SUCCEEDED, res.GetState())
+}
+
+func TestAccountNamesFollowTheWallet(t *testing.T) {
+	server, account := setupServer(t)
+	seed := _byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+
+	res, err := server.Lock(context.Background(), &pb.LockAccountRequest{Account: "wallet/" + account.Name()})
+	require.NoError(t, err)
+	require.EqualValues(t, pb.ResponseState_SUCCEEDED, res.GetState())
+
+	t.Run("deleted account", func(t *testing.T) {
+		require.NoError(t, server.wallet.DeleteAccountByPublicKey(hex.EncodeToString(account.ValidatorPublicKey().Marshal())))
+		res, err := server.Lock(context.Background(), &pb.LockAccountRequest{Account: "wallet/" + account.Name()})
+		require.NoError(t, err)
+		require.EqualValues(t, pb.ResponseState_DENIED, res.GetState())
+	})
+
+	t.Run("added account", func(t *testing.T) {
+		added, err := server.wallet.CreateValidatorAccount(seed, nil)
+		require.NoError(t, err)
+		res, err := server.Lock(context.Background(), &pb.LockAccountRequest{Account: "wallet/" + added.Name()})
+		require.NoError(t, err)
+		require.EqualValues(t, pb.ResponseState_SUCCEEDED, res.GetState())
+	})
+}
@@ -0,0 +1,74 @@
+package grpc_signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSignedCert writes a self signed certificate and its key as PEM files into dir
+func writeSelfSignedCert(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
+
+func TestLoadTLSCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grpc_signer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeSelfSignedCert(t, dir)
+
+	t.Run("server tls", func(t *testing.T) {
+		creds, err := LoadTLSCredentials(certFile, keyFile, "")
+		require.NoError(t, err)
+		require.NotNil(t, creds)
+	})
+
+	t.Run("mutual tls", func(t *testing.T) {
+		creds, err := LoadTLSCredentials(certFile, keyFile, certFile)
+		require.NoError(t, err)
+		require.NotNil(t, creds)
+	})
+
+	t.Run("missing certificate", func(t *testing.T) {
+		_, err := LoadTLSCredentials(filepath.Join(dir, "missing.crt"), keyFile, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to load server certificate")
+	})
+
+	t.Run("invalid CA", func(t *testing.T) {
+		_, err := LoadTLSCredentials(certFile, keyFile, keyFile)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no certificates found in")
+	})
+}
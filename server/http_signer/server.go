@@ -0,0 +1,346 @@
+package http_signer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/validator_signer"
+)
+
+// Web3Signer eth2 API paths
+const (
+	PublicKeysPath = "/api/v1/eth2/publicKeys"
+	SignPath       = "/api/v1/eth2/sign/"
+	UpcheckPath    = "/upcheck"
+)
+
+// Server exposes a ValidatorSigner over HTTP, compatible with the Web3Signer eth2 API so validator clients can use it
+// as a remote signer. Attestations and blocks go through the signer's slashing protection.
+type Server struct {
+	signer   validator_signer.ValidatorSigner
+	onDenied func(pubKey []byte, err *core.SlashableError)
+}
+
+// NewServer is the constructor of Server.
+func NewServer(signer validator_signer.ValidatorSigner) *Server {
+	return &Server{
+		signer: signer,
+	}
+}
+
+// OnDenied sets a func called with every request the slashing protection refuses, e.g. to log its verdicts.
+func (server *Server) OnDenied(fn func(pubKey []byte, err *core.SlashableError)) {
+	server.onDenied = fn
+}
+
+// Handler returns the http handler serving the API.
+func (server *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(PublicKeysPath, server.publicKeys)
+	mux.HandleFunc(SignPath, server.sign)
+	mux.HandleFunc(UpcheckPath, server.upcheck)
+	return mux
+}
+
+func (server *Server) upcheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte("OK"))
+}
+
+func (server *Server) publicKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	res, err := server.signer.ListAccounts()
+	if err != nil {
+		http.Error(w, errors.Wrap(err, "failed to list accounts").Error(), http.StatusInternalServerError)
+		return
+	}
+	ret := make([]hexBytes, 0)
+	for _, account := range res.GetAccounts() {
+		ret = append(ret, account.PublicKey)
+	}
+	writeJSON(w, ret)
+}
+
+func (server *Server) sign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pubKey, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, SignPath), "0x"))
+	if err != nil || len(pubKey) == 0 {
+		http.Error(w, "invalid public key", http.StatusBadRequest)
+		return
+	}
+
+	req := &signRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		http.Error(w, errors.Wrap(err, "invalid request body").Error(), http.StatusBadRequest)
+		return
+	}
+
+	var sig []byte
+	switch req.Type {
+	case MessageTypeAttestation:
+		sig, err = server.signAttestation(pubKey, req)
+	case MessageTypeBlockV2:
+		sig, err = server.signBlock(pubKey, req)
+	case MessageTypeAggregationSlot:
+		sig, err = server.signAggregationSlot(pubKey, req)
+	case MessageTypeAggregateAndProof:
+		sig, err = server.signAggregateAndProof(pubKey, req)
+	case MessageTypeRandaoReveal:
+		sig, err = server.signRandaoReveal(pubKey, req)
+	case MessageTypeVoluntaryExit:
+		sig, err = server.signVoluntaryExit(pubKey, req)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported type %s", req.Type), http.StatusBadRequest)
+		return
+	}
+	var slashable *core.SlashableError
+	if errors.As(err, &slashable) {
+		server.denied(w, pubKey, slashable)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	writeJSON(w, &signResponse{Signature: sig})
+}
+
+// denied writes the verdicts of a refused request with the 412 status Web3Signer uses
+func (server *Server) denied(w http.ResponseWriter, pubKey []byte, err *core.SlashableError) {
+	if server.onDenied != nil {
+		server.onDenied(pubKey, err)
+	}
+	verdicts := err.Verdicts
+	if verdicts == nil {
+		verdicts = make([]*core.SlashingVerdict, 0)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusPreconditionFailed)
+	json.NewEncoder(w).Encode(&deniedResponse{Error: err.Error(), Verdicts: verdicts})
+}
+
+func (server *Server) signAttestation(pubKey []byte, req *signRequest) ([]byte, error) {
+	forkInfo, err := req.ForkInfo.toCore()
+	if err != nil {
+		return nil, &badRequestError{err}
+	}
+	data, err := req.Attestation.toPB()
+	if err != nil {
+		return nil, &badRequestError{err}
+	}
+
+	signReq := &pb.SignBeaconAttestationRequest{
+		Id:     &pb.SignBeaconAttestationRequest_PublicKey{PublicKey: pubKey},
+		Domain: forkInfo.Domain(e2types.DomainBeaconAttester, data.Target.Epoch),
+		Data:   data,
+	}
+	root, err := validator_signer.PrepareAttestationReqForSigning(signReq)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySigningRoot(req.SigningRoot, root); err != nil {
+		return nil, err
+	}
+
+	res, err := server.signer.SignBeaconAttestation(signReq)
+	if err != nil {
+		return nil, err
+	}
+	return res.GetSignature(), nil
+}
+
+func (server *Server) signBlock(pubKey []byte, req *signRequest) ([]byte, error) {
+	forkInfo, err := req.ForkInfo.toCore()
+	if err != nil {
+		return nil, &badRequestError{err}
+	}
+	data, err := req.BeaconBlock.toPB()
+	if err != nil {
+		return nil, &badRequestError{err}
+	}
+
+	signReq := &pb.SignBeaconProposalRequest{
+		Id:     &pb.SignBeaconProposalRequest_PublicKey{PublicKey: pubKey},
+		Domain: forkInfo.Domain(e2types.DomainBeaconProposer, core.EpochAtSlot(data.Slot)),
+		Data:   data,
+	}
+	root, err := validator_signer.PrepareProposalReqForSigning(signReq)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySigningRoot(req.SigningRoot, root); err != nil {
+		return nil, err
+	}
+
+	res, err := server.signer.SignBeaconProposal(signReq)
+	if err != nil {
+		return nil, err
+	}
+	return res.GetSignature(), nil
+}
+
+func (server *Server) signAggregationSlot(pubKey []byte, req *signRequest) ([]byte, error) {
+	forkInfo, err := req.ForkInfo.toCore()
+	if err != nil {
+		return nil, &badRequestError{err}
+	}
+	if req.AggregationSlot == nil {
+		return nil, &badRequestError{fmt.Errorf("aggregation_slot is missing")}
+	}
+
+	slot := uint64(req.AggregationSlot.Slot)
+	signReq := &validator_signer.SignSlotSelectionRequest{
+		PublicKey: pubKey,
+		Domain:    forkInfo.Domain(e2types.DomainSelectionProof, core.EpochAtSlot(slot)),
+		Slot:      slot,
+	}
+	root, err := validator_signer.PrepareSlotSelectionReqForSigning(signReq)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySigningRoot(req.SigningRoot, root); err != nil {
+		return nil, err
+	}
+
+	res, err := server.signer.SignSlotSelection(signReq)
+	if err != nil {
+		return nil, err
+	}
+	return res.GetSignature(), nil
+}
+
+func (server *Server) signAggregateAndProof(pubKey []byte, req *signRequest) ([]byte, error) {
+	forkInfo, err := req.ForkInfo.toCore()
+	if err != nil {
+		return nil, &badRequestError{err}
+	}
+	data, err := req.AggregateAndProof.toEthPB()
+	if err != nil {
+		return nil, &badRequestError{err}
+	}
+
+	signReq := &validator_signer.SignAggregateAndProofRequest{
+		PublicKey: pubKey,
+		Domain:    forkInfo.Domain(e2types.DomainAggregateAndProof, core.EpochAtSlot(data.Aggregate.Data.Slot)),
+		Data:      data,
+	}
+	root, err := validator_signer.PrepareAggregateAndProofReqForSigning(signReq)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySigningRoot(req.SigningRoot, root); err != nil {
+		return nil, err
+	}
+
+	res, err := server.signer.SignAggregateAndProof(signReq)
+	if err != nil {
+		return nil, err
+	}
+	return res.GetSignature(), nil
+}
+
+func (server *Server) signRandaoReveal(pubKey []byte, req *signRequest) ([]byte, error) {
+	forkInfo, err := req.ForkInfo.toCore()
+	if err != nil {
+		return nil, &badRequestError{err}
+	}
+	if req.RandaoReveal == nil {
+		return nil, &badRequestError{fmt.Errorf("randao_reveal is missing")}
+	}
+
+	epoch := uint64(req.RandaoReveal.Epoch)
+	root, err := validator_signer.PrepareRandaoRevealForSigning(epoch, forkInfo.Domain(e2types.DomainRANDAO, epoch))
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySigningRoot(req.SigningRoot, root); err != nil {
+		return nil, err
+	}
+
+	res, err := server.signer.SignRandaoReveal(pubKey, epoch, forkInfo)
+	if err != nil {
+		return nil, err
+	}
+	return res.GetSignature(), nil
+}
+
+func (server *Server) signVoluntaryExit(pubKey []byte, req *signRequest) ([]byte, error) {
+	forkInfo, err := req.ForkInfo.toCore()
+	if err != nil {
+		return nil, &badRequestError{err}
+	}
+	exit, err := req.VoluntaryExit.toCore()
+	if err != nil {
+		return nil, &badRequestError{err}
+	}
+
+	root, err := validator_signer.PrepareVoluntaryExitForSigning(exit, forkInfo.Domain(e2types.DomainVoluntaryExit, exit.Epoch))
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySigningRoot(req.SigningRoot, root); err != nil {
+		return nil, err
+	}
+
+	res, err := server.signer.SignVoluntaryExit(pubKey, exit, forkInfo)
+	if err != nil {
+		return nil, err
+	}
+	return res.GetSignature(), nil
+}
+
+// verifySigningRoot checks the client's signing root, if sent, matches the one the signer computed
+func verifySigningRoot(expected []byte, root []byte) error {
+	if len(expected) > 0 && !bytes.Equal(expected, root) {
+		return &badRequestError{fmt.Errorf("signing root mismatch, expected: %x, got: %x", root, expected)}
+	}
+	return nil
+}
+
+type badRequestError struct {
+	error
+}
+
+// errorStatus maps signing errors to the Web3Signer status codes
+func errorStatus(err error) int {
+	var badRequest *badRequestError
+	switch {
+	case errors.As(err, &badRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, core.ErrSlashable):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, core.ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, errors.Wrap(err, "failed to marshal response").Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,396 @@
+package http_signer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	eth2keymanager "github.com/bloxapp/eth2-key-manager"
+	"github.com/bloxapp/eth2-key-manager/core"
+	prot "github.com/bloxapp/eth2-key-manager/slashing_protection"
+	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+	"github.com/bloxapp/eth2-key-manager/validator_signer"
+)
+
+const forkInfoJSON = `{
+	"fork": {"previous_version": "0x00000001", "current_version": "0x00000001", "epoch": "0"},
+	"genesis_validators_root": "0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673"
+}`
+
+func setupServer(t *testing.T) (*httptest.Server, e2types.PublicKey) {
+	signer, pubKey := setupSigner(t)
+	return httptest.NewServer(NewServer(signer).Handler()), pubKey
+}
+
+func setupSigner(t *testing.T) (validator_signer.ValidatorSigner, e2types.PublicKey) {
+	require.NoError(t, e2types.InitBLS())
+	seed := _byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+
+	store := in_memory.NewInMemStore(core.TestNetwork)
+	options := &eth2keymanager.KeyVaultOptions{}
+	options.SetStorage(store)
+	options.SetSeed(seed)
+	vault, err := eth2keymanager.NewKeyVault(options)
+	require.NoError(t, err)
+	wallet, err := vault.Wallet()
+	require.NoError(t, err)
+	account, err := wallet.CreateValidatorAccount(seed, nil)
+	require.NoError(t, err)
+
+	return validator_signer.NewSimpleSigner(wallet, prot.NewNormalProtection(store)), account.ValidatorPublicKey()
+}
+
+func _byteArray(input string) []byte {
+	res, _ := hex.DecodeString(input)
+	return res
+}
+
+func attestationBody(source uint64, target uint64, root string) string {
+	return fmt.Sprintf(`{
+		"type": "ATTESTATION",
+		"fork_info": %s,
+		"attestation": {
+			"slot": "%d",
+			"index": "1",
+			"beacon_block_root": "0x%s",
+			"source": {"epoch": "%d", "root": "0x%s"},
+			"target": {"epoch": "%d", "root": "0x%s"}
+		}
+	}`, forkInfoJSON, target*core.SlotsPerEpoch, root, source, root, target, root)
+}
+
+func blockBody(slot uint64, root string) string {
+	return fmt.Sprintf(`{
+		"type": "BLOCK_V2",
+		"fork_info": %s,
+		"beacon_block": {
+			"version": "PHASE0",
+			"block_header": {
+				"slot": "%d",
+				"proposer_index": "1",
+				"parent_root": "0x%s",
+				"state_root": "0x%s",
+				"body_root": "0x%s"
+			}
+		}
+	}`, forkInfoJSON, slot, root, root, root)
+}
+
+func aggregationSlotBody(slot uint64) string {
+	return fmt.Sprintf(`{
+		"type": "AGGREGATION_SLOT",
+		"fork_info": %s,
+		"aggregation_slot": {"slot": "%d"}
+	}`, forkInfoJSON, slot)
+}
+
+func aggregateAndProofBody(slot uint64, root string) string {
+	return fmt.Sprintf(`{
+		"type": "AGGREGATE_AND_PROOF",
+		"fork_info": %s,
+		"aggregate_and_proof": {
+			"aggregator_index": "1",
+			"aggregate": {
+				"aggregation_bits": "0x0f",
+				"data": {
+					"slot": "%d",
+					"index": "1",
+					"beacon_block_root": "0x%s",
+					"source": {"epoch": "0", "root": "0x%s"},
+					"target": {"epoch": "%d", "root": "0x%s"}
+				},
+				"signature": "0x%s"
+			},
+			"selection_proof": "0x%s"
+		}
+	}`, forkInfoJSON, slot, root, root, core.EpochAtSlot(slot), root, strings.Repeat("a", 192), strings.Repeat("b", 192))
+}
+
+func signURL(server *httptest.Server, pubKey e2types.PublicKey) string {
+	return server.URL + SignPath + "0x" + hex.EncodeToString(pubKey.Marshal())
+}
+
+func post(t *testing.T, url string, body string) (int, string) {
+	res, err := http.Post(url, "application/json", bytes.NewBufferString(body))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	byts, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, string(byts)
+}
+
+func requireValidSignature(t *testing.T, body string, pubKey e2types.PublicKey) {
+	res := make(map[string]string)
+	require.NoError(t, json.Unmarshal([]byte(body), &res))
+	require.Regexp(t, "^0x[0-9a-f]+$", res["signature"])
+	_, err := e2types.BLSSignatureFromBytes(_byteArray(res["signature"][2:]))
+	require.NoError(t, err)
+}
+
+func TestUpcheck(t *testing.T) {
+	server, _ := setupServer(t)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + UpcheckPath)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.EqualValues(t, http.StatusOK, res.StatusCode)
+}
+
+func TestPublicKeys(t *testing.T) {
+	server, pubKey := setupServer(t)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + PublicKeysPath)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.EqualValues(t, http.StatusOK, res.StatusCode)
+
+	var keys []string
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&keys))
+	require.EqualValues(t, []string{"0x" + hex.EncodeToString(pubKey.Marshal())}, keys)
+
+	t.Run("method not allowed", func(t *testing.T) {
+		status, _ := post(t, server.URL+PublicKeysPath, "")
+		require.EqualValues(t, http.StatusMethodNotAllowed, status)
+	})
+}
+
+func TestSignAttestation(t *testing.T) {
+	server, pubKey := setupServer(t)
+	defer server.Close()
+	root := "A000000000000000000000000000000000000000000000000000000000000000"
+
+	status, body := post(t, signURL(server, pubKey), attestationBody(1, 2, root))
+	require.EqualValues(t, http.StatusOK, status, body)
+	requireValidSignature(t, body, pubKey)
+
+	t.Run("double vote", func(t *testing.T) {
+		other := "B000000000000000000000000000000000000000000000000000000000000000"
+		status, body := post(t, signURL(server, pubKey), attestationBody(1, 2, other))
+		require.EqualValues(t, http.StatusPreconditionFailed, status, body)
+
+		res := &deniedResponse{}
+		require.NoError(t, json.Unmarshal([]byte(body), res))
+		require.EqualValues(t, "slashable attestation (DoubleVote), not signing", res.Error)
+		require.Len(t, res.Verdicts, 1)
+		require.EqualValues(t, core.DoubleVote, res.Verdicts[0].Detection)
+		require.EqualValues(t, 2, res.Verdicts[0].Attestation.Target.Epoch)
+		require.EqualValues(t, 2, res.Verdicts[0].ConflictingAttestation.Target.Epoch)
+		require.NotEqual(t, res.Verdicts[0].Attestation.BeaconBlockRoot, res.Verdicts[0].ConflictingAttestation.BeaconBlockRoot)
+	})
+
+	t.Run("surrounding vote", func(t *testing.T) {
+		status, body := post(t, signURL(server, pubKey), attestationBody(0, 3, root))
+		require.EqualValues(t, http.StatusPreconditionFailed, status, body)
+	})
+
+	t.Run("signing root mismatch", func(t *testing.T) {
+		req := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal([]byte(attestationBody(2, 3, root)), &req))
+		req["signingRoot"] = "0x" + root
+		byts, err := json.Marshal(req)
+		require.NoError(t, err)
+		status, body := post(t, signURL(server, pubKey), string(byts))
+		require.EqualValues(t, http.StatusBadRequest, status, body)
+		require.Contains(t, body, "signing root mismatch")
+	})
+
+	t.Run("missing fork info", func(t *testing.T) {
+		status, body := post(t, signURL(server, pubKey), `{"type": "ATTESTATION"}`)
+		require.EqualValues(t, http.StatusBadRequest, status, body)
+	})
+}
+
+func TestOnDenied(t *testing.T) {
+	signer, pubKey := setupSigner(t)
+	var denied []*core.SlashableError
+	handler := NewServer(signer)
+	handler.OnDenied(func(key []byte, err *core.SlashableError) {
+		require.EqualValues(t, pubKey.Marshal(), key)
+		denied = append(denied, err)
+	})
+	server := httptest.NewServer(handler.Handler())
+	defer server.Close()
+
+	root := "A000000000000000000000000000000000000000000000000000000000000000"
+	status, body := post(t, signURL(server, pubKey), attestationBody(1, 2, root))
+	require.EqualValues(t, http.StatusOK, status, body)
+	require.Len(t, denied, 0)
+
+	status, body = post(t, signURL(server, pubKey), attestationBody(0, 3, root))
+	require.EqualValues(t, http.StatusPreconditionFailed, status, body)
+	require.Len(t, denied, 1)
+	require.Len(t, denied[0].Verdicts, 1)
+	require.EqualValues(t, core.SurroundingVote, denied[0].Verdicts[0].Detection)
+	require.EqualValues(t, 1, denied[0].Verdicts[0].ConflictingAttestation.Source.Epoch)
+}
+
+func TestSignBlock(t *testing.T) {
+	server, pubKey := setupServer(t)
+	defer server.Close()
+	root := "A000000000000000000000000000000000000000000000000000000000000000"
+
+	status, body := post(t, signURL(server, pubKey), blockBody(10, root))
+	require.EqualValues(t, http.StatusOK, status, body)
+	requireValidSignature(t, body, pubKey)
+
+	t.Run("double proposal", func(t *testing.T) {
+		other := "B000000000000000000000000000000000000000000000000000000000000000"
+		status, body := post(t, signURL(server, pubKey), blockBody(10, other))
+		require.EqualValues(t, http.StatusPreconditionFailed, status, body)
+	})
+
+	t.Run("missing block header", func(t *testing.T) {
+		status, body := post(t, signURL(server, pubKey), `{"type": "BLOCK_V2", "fork_info": `+forkInfoJSON+`, "beacon_block": {"version": "PHASE0"}}`)
+		require.EqualValues(t, http.StatusBadRequest, status, body)
+	})
+}
+
+func TestSignAggregation(t *testing.T) {
+	server, pubKey := setupServer(t)
+	defer server.Close()
+	root := "A000000000000000000000000000000000000000000000000000000000000000"
+
+	status, body := post(t, signURL(server, pubKey), aggregationSlotBody(64))
+	require.EqualValues(t, http.StatusOK, status, body)
+	requireValidSignature(t, body, pubKey)
+
+	status, body = post(t, signURL(server, pubKey), aggregateAndProofBody(64, root))
+	require.EqualValues(t, http.StatusOK, status, body)
+	requireValidSignature(t, body, pubKey)
+
+	t.Run("missing aggregation slot", func(t *testing.T) {
+		status, body := post(t, signURL(server, pubKey), `{"type": "AGGREGATION_SLOT", "fork_info": `+forkInfoJSON+`}`)
+		require.EqualValues(t, http.StatusBadRequest, status, body)
+		require.Contains(t, body, "aggregation_slot is missing")
+	})
+
+	t.Run("missing aggregate", func(t *testing.T) {
+		status, body := post(t, signURL(server, pubKey), `{"type": "AGGREGATE_AND_PROOF", "fork_info": `+forkInfoJSON+`, "aggregate_and_proof": {"aggregator_index": "1"}}`)
+		require.EqualValues(t, http.StatusBadRequest, status, body)
+		require.Contains(t, body, "aggregate_and_proof is missing")
+	})
+}
+
+func TestSignRandaoReveal(t *testing.T) {
+	server, pubKey := setupServer(t)
+	defer server.Close()
+	root := "A000000000000000000000000000000000000000000000000000000000000000"
+	randaoBody := func(epoch uint64) string {
+		return fmt.Sprintf(`{"type": "RANDAO_REVEAL", "fork_info": %s, "randao_reveal": {"epoch": "%d"}}`, forkInfoJSON, epoch)
+	}
+
+	status, body := post(t, signURL(server, pubKey), randaoBody(3))
+	require.EqualValues(t, http.StatusOK, status, body)
+	requireValidSignature(t, body, pubKey)
+
+	t.Run("lower than the highest proposal", func(t *testing.T) {
+		status, body := post(t, signURL(server, pubKey), blockBody(5*core.SlotsPerEpoch, root))
+		require.EqualValues(t, http.StatusOK, status, body)
+
+		status, body = post(t, signURL(server, pubKey), randaoBody(4))
+		require.EqualValues(t, http.StatusPreconditionFailed, status, body)
+	})
+
+	t.Run("missing randao reveal", func(t *testing.T) {
+		status, body := post(t, signURL(server, pubKey), `{"type": "RANDAO_REVEAL", "fork_info": `+forkInfoJSON+`}`)
+		require.EqualValues(t, http.StatusBadRequest, status, body)
+	})
+}
+
+func TestSignVoluntaryExit(t *testing.T) {
+	server, pubKey := setupServer(t)
+	defer server.Close()
+
+	status, body := post(t, signURL(server, pubKey), `{"type": "VOLUNTARY_EXIT", "fork_info": `+forkInfoJSON+`, "voluntary_exit": {"epoch": "10", "validator_index": "25"}}`)
+	require.EqualValues(t, http.StatusOK, status, body)
+	requireValidSignature(t, body, pubKey)
+
+	t.Run("missing voluntary exit", func(t *testing.T) {
+		status, body := post(t, signURL(server, pubKey), `{"type": "VOLUNTARY_EXIT", "fork_info": `+forkInfoJSON+`}`)
+		require.EqualValues(t, http.StatusBadRequest, status, body)
+		require.Contains(t, body, "voluntary_exit is missing")
+	})
+}
+
+func TestSignErrors(t *testing.T) {
+	server, pubKey := setupServer(t)
+	defer server.Close()
+
+	t.Run("unknown public key", func(t *testing.T) {
+		unknown, err := e2types.GenerateBLSPrivateKey()
+		require.NoError(t, err)
+		status, body := post(t, signURL(server, unknown.PublicKey()), attestationBody(1, 2, "A000000000000000000000000000000000000000000000000000000000000000"))
+		require.EqualValues(t, http.StatusNotFound, status, body)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		status, body := post(t, signURL(server, pubKey), `{"type": "SYNC_COMMITTEE_MESSAGE"}`)
+		require.EqualValues(t, http.StatusBadRequest, status, body)
+		require.Contains(t, body, "unsupported type SYNC_COMMITTEE_MESSAGE")
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		status, body := post(t, signURL(server, pubKey), `{"type": `)
+		require.EqualValues(t, http.StatusBadRequest, status, body)
+	})
+
+	t.Run("invalid field lengths", func(t *testing.T) {
+		root := "A000000000000000000000000000000000000000000000000000000000000000"
+		tests := []struct {
+			name        string
+			body        string
+			expectedErr string
+		}{
+			{
+				name:        "short fork version",
+				body:        strings.Replace(attestationBody(5, 6, root), `"current_version": "0x00000001"`, `"current_version": "0x01"`, 1),
+				expectedErr: "fork_info.fork.current_version must be 4 bytes, got 1",
+			},
+			{
+				name:        "short genesis validators root",
+				body:        strings.Replace(attestationBody(5, 6, root), "0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673", "0x0470", 1),
+				expectedErr: "fork_info.genesis_validators_root must be 32 bytes, got 2",
+			},
+			{
+				name:        "short attestation root",
+				body:        attestationBody(5, 6, "A0"),
+				expectedErr: "beacon_block_root must be 32 bytes, got 1",
+			},
+			{
+				name:        "long block root",
+				body:        blockBody(100, root+"00"),
+				expectedErr: "parent_root must be 32 bytes, got 33",
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				status, body := post(t, signURL(server, pubKey), test.body)
+				require.EqualValues(t, http.StatusBadRequest, status, body)
+				require.Contains(t, body, test.expectedErr)
+			})
+		}
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		status, body := post(t, server.URL+SignPath+"0xzz", attestationBody(1, 2, "A000000000000000000000000000000000000000000000000000000000000000"))
+		require.EqualValues(t, http.StatusBadRequest, status, body)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		res, err := http.Get(signURL(server, pubKey))
+		require.NoError(t, err)
+		res.Body.Close()
+		require.EqualValues(t, http.StatusMethodNotAllowed, res.StatusCode)
+	})
+}
@@ -0,0 +1,276 @@
+package http_signer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// Signing request types, as named by the Web3Signer eth2 API.
+// https://consensys.github.io/web3signer/web3signer-eth2.html
+const (
+	MessageTypeAttestation       = "ATTESTATION"
+	MessageTypeBlockV2           = "BLOCK_V2"
+	MessageTypeAggregationSlot   = "AGGREGATION_SLOT"
+	MessageTypeAggregateAndProof = "AGGREGATE_AND_PROOF"
+	MessageTypeRandaoReveal      = "RANDAO_REVEAL"
+	MessageTypeVoluntaryExit     = "VOLUNTARY_EXIT"
+)
+
+// uint64String is an uint64 json encoded as a decimal string, plain numbers are accepted too
+type uint64String uint64
+
+func (u *uint64String) UnmarshalJSON(data []byte) error {
+	str := strings.Trim(string(data), `"`)
+	val, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid uint64 %s", data)
+	}
+	*u = uint64String(val)
+	return nil
+}
+
+// hexBytes is a 0x prefixed hex json string
+type hexBytes []byte
+
+func (h *hexBytes) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(str, "0x") {
+		return fmt.Errorf("hex value %s is not 0x prefixed", str)
+	}
+	byts, err := hex.DecodeString(str[2:])
+	if err != nil {
+		return fmt.Errorf("invalid hex value %s", str)
+	}
+	*h = byts
+	return nil
+}
+
+func (h hexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal("0x" + hex.EncodeToString(h))
+}
+
+// checkLength validates a fixed size field, ssz would fail on it or hash it padded
+func (h hexBytes) checkLength(name string, length int) error {
+	if len(h) != length {
+		return fmt.Errorf("%s must be %d bytes, got %d", name, length, len(h))
+	}
+	return nil
+}
+
+type fork struct {
+	PreviousVersion hexBytes     `json:"previous_version"`
+	CurrentVersion  hexBytes     `json:"current_version"`
+	Epoch           uint64String `json:"epoch"`
+}
+
+type forkInfo struct {
+	Fork                  *fork    `json:"fork"`
+	GenesisValidatorsRoot hexBytes `json:"genesis_validators_root"`
+}
+
+func (info *forkInfo) toCore() (*core.ForkInfo, error) {
+	if info == nil || info.Fork == nil {
+		return nil, fmt.Errorf("fork_info is missing")
+	}
+	if err := info.Fork.PreviousVersion.checkLength("fork_info.fork.previous_version", 4); err != nil {
+		return nil, err
+	}
+	if err := info.Fork.CurrentVersion.checkLength("fork_info.fork.current_version", 4); err != nil {
+		return nil, err
+	}
+	if err := info.GenesisValidatorsRoot.checkLength("fork_info.genesis_validators_root", 32); err != nil {
+		return nil, err
+	}
+	return &core.ForkInfo{
+		Fork: &core.Fork{
+			PreviousVersion: info.Fork.PreviousVersion,
+			CurrentVersion:  info.Fork.CurrentVersion,
+			Epoch:           uint64(info.Fork.Epoch),
+		},
+		GenesisValidatorsRoot: info.GenesisValidatorsRoot,
+	}, nil
+}
+
+type checkpoint struct {
+	Epoch uint64String `json:"epoch"`
+	Root  hexBytes     `json:"root"`
+}
+
+func (c *checkpoint) toPB(name string) (*pb.Checkpoint, error) {
+	if err := c.Root.checkLength(name+".root", 32); err != nil {
+		return nil, err
+	}
+	return &pb.Checkpoint{
+		Epoch: uint64(c.Epoch),
+		Root:  c.Root,
+	}, nil
+}
+
+type attestationData struct {
+	Slot            uint64String `json:"slot"`
+	Index           uint64String `json:"index"`
+	BeaconBlockRoot hexBytes     `json:"beacon_block_root"`
+	Source          *checkpoint  `json:"source"`
+	Target          *checkpoint  `json:"target"`
+}
+
+func (data *attestationData) toPB() (*pb.AttestationData, error) {
+	if data == nil || data.Source == nil || data.Target == nil {
+		return nil, fmt.Errorf("attestation is missing")
+	}
+	if err := data.BeaconBlockRoot.checkLength("beacon_block_root", 32); err != nil {
+		return nil, err
+	}
+	source, err := data.Source.toPB("source")
+	if err != nil {
+		return nil, err
+	}
+	target, err := data.Target.toPB("target")
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AttestationData{
+		Slot:            uint64(data.Slot),
+		CommitteeIndex:  uint64(data.Index),
+		BeaconBlockRoot: data.BeaconBlockRoot,
+		Source:          source,
+		Target:          target,
+	}, nil
+}
+
+type blockHeader struct {
+	Slot          uint64String `json:"slot"`
+	ProposerIndex uint64String `json:"proposer_index"`
+	ParentRoot    hexBytes     `json:"parent_root"`
+	StateRoot     hexBytes     `json:"state_root"`
+	BodyRoot      hexBytes     `json:"body_root"`
+}
+
+// beaconBlock is the BLOCK_V2 block, only block headers are supported as the signer doesn't know the body schemas
+type beaconBlock struct {
+	Version     string       `json:"version"`
+	BlockHeader *blockHeader `json:"block_header"`
+}
+
+func (block *beaconBlock) toPB() (*pb.BeaconBlockHeader, error) {
+	if block == nil || block.BlockHeader == nil {
+		return nil, fmt.Errorf("beacon_block.block_header is missing")
+	}
+	header := block.BlockHeader
+	if err := header.ParentRoot.checkLength("parent_root", 32); err != nil {
+		return nil, err
+	}
+	if err := header.StateRoot.checkLength("state_root", 32); err != nil {
+		return nil, err
+	}
+	if err := header.BodyRoot.checkLength("body_root", 32); err != nil {
+		return nil, err
+	}
+	return &pb.BeaconBlockHeader{
+		Slot:          uint64(block.BlockHeader.Slot),
+		ProposerIndex: uint64(block.BlockHeader.ProposerIndex),
+		ParentRoot:    block.BlockHeader.ParentRoot,
+		StateRoot:     block.BlockHeader.StateRoot,
+		BodyRoot:      block.BlockHeader.BodyRoot,
+	}, nil
+}
+
+type aggregationSlot struct {
+	Slot uint64String `json:"slot"`
+}
+
+type randaoReveal struct {
+	Epoch uint64String `json:"epoch"`
+}
+
+type voluntaryExit struct {
+	Epoch          uint64String `json:"epoch"`
+	ValidatorIndex uint64String `json:"validator_index"`
+}
+
+func (exit *voluntaryExit) toCore() (*core.VoluntaryExit, error) {
+	if exit == nil {
+		return nil, fmt.Errorf("voluntary_exit is missing")
+	}
+	return &core.VoluntaryExit{
+		Epoch:          uint64(exit.Epoch),
+		ValidatorIndex: uint64(exit.ValidatorIndex),
+	}, nil
+}
+
+type attestation struct {
+	AggregationBits hexBytes         `json:"aggregation_bits"`
+	Data            *attestationData `json:"data"`
+	Signature       hexBytes         `json:"signature"`
+}
+
+type aggregateAndProof struct {
+	AggregatorIndex uint64String `json:"aggregator_index"`
+	Aggregate       *attestation `json:"aggregate"`
+	SelectionProof  hexBytes     `json:"selection_proof"`
+}
+
+func (agg *aggregateAndProof) toEthPB() (*ethpb.AggregateAttestationAndProof, error) {
+	if agg == nil || agg.Aggregate == nil {
+		return nil, fmt.Errorf("aggregate_and_proof is missing")
+	}
+	data, err := agg.Aggregate.Data.toPB()
+	if err != nil {
+		return nil, err
+	}
+	if err := agg.Aggregate.Signature.checkLength("aggregate.signature", 96); err != nil {
+		return nil, err
+	}
+	if err := agg.SelectionProof.checkLength("selection_proof", 96); err != nil {
+		return nil, err
+	}
+	return &ethpb.AggregateAttestationAndProof{
+		AggregatorIndex: uint64(agg.AggregatorIndex),
+		Aggregate: &ethpb.Attestation{
+			AggregationBits: agg.Aggregate.AggregationBits,
+			Data: &ethpb.AttestationData{
+				Slot:            data.Slot,
+				CommitteeIndex:  data.CommitteeIndex,
+				BeaconBlockRoot: data.BeaconBlockRoot,
+				Source:          &ethpb.Checkpoint{Epoch: data.Source.Epoch, Root: data.Source.Root},
+				Target:          &ethpb.Checkpoint{Epoch: data.Target.Epoch, Root: data.Target.Root},
+			},
+			Signature: agg.Aggregate.Signature,
+		},
+		SelectionProof: agg.SelectionProof,
+	}, nil
+}
+
+// signRequest is the body of a sign request, the message field depends on the type
+type signRequest struct {
+	Type              string             `json:"type"`
+	ForkInfo          *forkInfo          `json:"fork_info"`
+	SigningRoot       hexBytes           `json:"signingRoot"`
+	Attestation       *attestationData   `json:"attestation"`
+	BeaconBlock       *beaconBlock       `json:"beacon_block"`
+	AggregationSlot   *aggregationSlot   `json:"aggregation_slot"`
+	AggregateAndProof *aggregateAndProof `json:"aggregate_and_proof"`
+	RandaoReveal      *randaoReveal      `json:"randao_reveal"`
+	VoluntaryExit     *voluntaryExit     `json:"voluntary_exit"`
+}
+
+type signResponse struct {
+	Signature hexBytes `json:"signature"`
+}
+
+// deniedResponse is the body of a 412 response, records are encoded as the slashing store saves them
+type deniedResponse struct {
+	Error    string                  `json:"error"`
+	Verdicts []*core.SlashingVerdict `json:"verdicts"`
+}
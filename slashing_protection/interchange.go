@@ -0,0 +1,340 @@
+package slashing_protection
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// InterchangeFormatVersion is the EIP-3076 version written on export
+const InterchangeFormatVersion = "5"
+
+// InterchangeFormat is the flavour of an interchange document, complete holds the full history, minimal only the
+// highest signed records.
+type InterchangeFormat string
+
+const (
+	InterchangeFormatComplete InterchangeFormat = "complete"
+	InterchangeFormatMinimal  InterchangeFormat = "minimal"
+)
+
+// Interchange is an EIP-3076 slashing protection interchange document.
+// https://eips.ethereum.org/EIPS/eip-3076
+type Interchange struct {
+	Metadata *InterchangeMetadata `json:"metadata"`
+	Data     []*InterchangeData   `json:"data"`
+}
+
+type InterchangeMetadata struct {
+	// InterchangeFormat is not part of v5, it's kept for documents produced by clients using v4
+	InterchangeFormat        InterchangeFormat `json:"interchange_format,omitempty"`
+	InterchangeFormatVersion string            `json:"interchange_format_version"`
+	GenesisValidatorsRoot    string            `json:"genesis_validators_root"`
+}
+
+type InterchangeData struct {
+	PublicKey          string                    `json:"pubkey"`
+	SignedBlocks       []*InterchangeBlock       `json:"signed_blocks"`
+	SignedAttestations []*InterchangeAttestation `json:"signed_attestations"`
+}
+
+type InterchangeBlock struct {
+	Slot        string `json:"slot"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+type InterchangeAttestation struct {
+	SourceEpoch string `json:"source_epoch"`
+	TargetEpoch string `json:"target_epoch"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// ExportInterchange returns the slashing history of the given keys as an EIP-3076 interchange document.
+// Signing roots are not exported as the store doesn't keep the domain the data was signed with.
+func (protector *NormalProtection) ExportInterchange(genesisValidatorsRoot []byte, keys []e2types.PublicKey, format InterchangeFormat) (*Interchange, error) {
+	if format != InterchangeFormatComplete && format != InterchangeFormatMinimal {
+		return nil, fmt.Errorf("unknown interchange format: %s", format)
+	}
+
+	ret := &Interchange{
+		Metadata: &InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    encodeInterchangeHex(genesisValidatorsRoot),
+		},
+		Data: make([]*InterchangeData, 0),
+	}
+
+	for _, key := range keys {
+		data, err := protector.exportInterchangeData(key, format)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to export history of %x", key.Marshal())
+		}
+		ret.Data = append(ret.Data, data)
+	}
+	return ret, nil
+}
+
+// ImportInterchange merges an EIP-3076 interchange document into the store.
+// The merge is conservative, local records are kept and only the missing imported ones are added, the key's watermark
+// is raised to the highest imported records (see core.Watermark) so nothing conflicting with them will be signed.
+// The whole document is validated before anything is written.
+func (protector *NormalProtection) ImportInterchange(genesisValidatorsRoot []byte, interchange *Interchange) error {
+	if interchange == nil || interchange.Metadata == nil {
+		return fmt.Errorf("interchange metadata is missing")
+	}
+	if v := interchange.Metadata.InterchangeFormatVersion; v != InterchangeFormatVersion && v != "4" {
+		return fmt.Errorf("unsupported interchange format version: %s", v)
+	}
+	gvr, err := decodeInterchangeHex(interchange.Metadata.GenesisValidatorsRoot)
+	if err != nil {
+		return errors.Wrap(err, "invalid genesis validators root")
+	}
+	if !bytes.Equal(gvr, genesisValidatorsRoot) {
+		return fmt.Errorf("genesis validators root mismatch, expected: %x, got: %x", genesisValidatorsRoot, gvr)
+	}
+
+	type history struct {
+		key          e2types.PublicKey
+		attestations []*core.BeaconAttestation
+		proposals    []*core.BeaconBlockHeader
+	}
+
+	// parse and validate everything first
+	histories := make([]*history, 0)
+	for _, data := range interchange.Data {
+		pubKey, err := decodeInterchangeHex(data.PublicKey)
+		if err != nil {
+			return errors.Wrap(err, "invalid public key")
+		}
+		key, err := e2types.BLSPublicKeyFromBytes(pubKey)
+		if err != nil {
+			return errors.Wrapf(err, "invalid public key %s", data.PublicKey)
+		}
+
+		h := &history{key: key}
+		for _, block := range data.SignedBlocks {
+			slot, err := decodeInterchangeUint64(block.Slot)
+			if err != nil {
+				return errors.Wrapf(err, "invalid block slot for %s", data.PublicKey)
+			}
+			h.proposals = append(h.proposals, &core.BeaconBlockHeader{Slot: slot})
+		}
+		for _, att := range data.SignedAttestations {
+			source, err := decodeInterchangeUint64(att.SourceEpoch)
+			if err != nil {
+				return errors.Wrapf(err, "invalid attestation source epoch for %s", data.PublicKey)
+			}
+			target, err := decodeInterchangeUint64(att.TargetEpoch)
+			if err != nil {
+				return errors.Wrapf(err, "invalid attestation target epoch for %s", data.PublicKey)
+			}
+			if source > target {
+				return fmt.Errorf("attestation source epoch %d is greater than target epoch %d for %s", source, target, data.PublicKey)
+			}
+			h.attestations = append(h.attestations, &core.BeaconAttestation{
+				Source: &core.Checkpoint{Epoch: source},
+				Target: &core.Checkpoint{Epoch: target},
+			})
+		}
+		histories = append(histories, h)
+	}
+
+	// merge, each key in a single store transaction
+	for _, h := range histories {
+		err := protector.store.Atomically(h.key, func(store core.SlashingStore) error {
+			return NewNormalProtection(store).mergeHistory(h.key, h.attestations, h.proposals)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeHistory adds the imported records the store doesn't have, local records at the same target epoch / slot are
+// kept. The spans and the watermark cover every imported record.
+func (protector *NormalProtection) mergeHistory(key e2types.PublicKey, attestations []*core.BeaconAttestation, proposals []*core.BeaconBlockHeader) error {
+	for _, proposal := range proposals {
+		existing, err := protector.store.RetrieveProposal(key, proposal.Slot)
+		if err != nil && !errors.Is(err, core.ErrNotFound) {
+			return errors.Wrap(err, "failed to retrieve proposal")
+		}
+		if existing != nil {
+			continue
+		}
+		if err := protector.store.SaveProposal(key, proposal); err != nil {
+			return errors.Wrap(err, "failed to save imported proposal")
+		}
+	}
+
+	spans, err := newSpanUpdater(protector.store, key)
+	if err != nil {
+		return errors.Wrap(err, "failed to load attestation spans")
+	}
+	for _, att := range attestations {
+		if err := spans.add(att); err != nil {
+			return errors.Wrap(err, "failed to update attestation spans")
+		}
+
+		existing, err := protector.store.RetrieveAttestation(key, att.Target.Epoch)
+		if err != nil && !errors.Is(err, core.ErrNotFound) {
+			return errors.Wrap(err, "failed to retrieve attestation")
+		}
+		if existing != nil {
+			continue
+		}
+		if err := protector.store.SaveAttestation(key, att); err != nil {
+			return errors.Wrap(err, "failed to save imported attestation")
+		}
+
+		latest, err := protector.store.RetrieveLatestAttestation(key)
+		if err != nil {
+			return errors.Wrap(err, "failed to retrieve latest attestation")
+		}
+		if latest == nil || latest.Target.Epoch < att.Target.Epoch {
+			if err := protector.store.SaveLatestAttestation(key, att); err != nil {
+				return errors.Wrap(err, "failed to save latest attestation")
+			}
+		}
+	}
+	if err := spans.save(); err != nil {
+		return errors.Wrap(err, "failed to save attestation spans")
+	}
+	return protector.raiseWatermark(key, attestations, proposals)
+}
+
+// exportInterchangeData exports the key's records, the watermark is exported as a synthetic record when it's above the
+// lowest exported one (e.g. after pruning or a minimal import), an importer only protects from the lowest record down.
+func (protector *NormalProtection) exportInterchangeData(key e2types.PublicKey, format InterchangeFormat) (*InterchangeData, error) {
+	ret := &InterchangeData{
+		PublicKey:          encodeInterchangeHex(key.Marshal()),
+		SignedBlocks:       make([]*InterchangeBlock, 0),
+		SignedAttestations: make([]*InterchangeAttestation, 0),
+	}
+
+	watermark, err := protector.store.RetrieveWatermark(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve watermark")
+	}
+
+	proposals, err := protector.store.ListProposals(key, 0, math.MaxUint64)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list proposals")
+	}
+	if watermark != nil && watermark.MinProposalSlot != nil && watermarkAboveLowest(*watermark.MinProposalSlot, proposalSlots(proposals)) {
+		proposals = append(proposals, &core.BeaconBlockHeader{Slot: *watermark.MinProposalSlot})
+	}
+	if format == InterchangeFormatMinimal && len(proposals) > 0 {
+		highest := proposals[0]
+		for _, proposal := range proposals {
+			if proposal.Slot > highest.Slot {
+				highest = proposal
+			}
+		}
+		proposals = []*core.BeaconBlockHeader{highest}
+	}
+	for _, proposal := range proposals {
+		ret.SignedBlocks = append(ret.SignedBlocks, &InterchangeBlock{
+			Slot: strconv.FormatUint(proposal.Slot, 10),
+		})
+	}
+
+	latest, err := protector.store.RetrieveLatestAttestation(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve latest attestation")
+	}
+	var attestations []*core.BeaconAttestation
+	if latest != nil {
+		attestations, err = protector.store.ListAttestations(key, 0, latest.Target.Epoch)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list attestations")
+		}
+	}
+	if watermark != nil && watermark.MinTargetEpoch != nil && watermarkAboveLowest(*watermark.MinTargetEpoch, attestationTargets(attestations)) {
+		attestations = append(attestations, &core.BeaconAttestation{
+			Source: &core.Checkpoint{Epoch: watermark.MinSourceEpoch},
+			Target: &core.Checkpoint{Epoch: *watermark.MinTargetEpoch},
+		})
+	}
+	if format == InterchangeFormatMinimal && len(attestations) > 0 {
+		// a single record made of the highest source and target epochs, as EIP-3076 suggests
+		highest := &core.BeaconAttestation{
+			Source: &core.Checkpoint{},
+			Target: &core.Checkpoint{},
+		}
+		for _, att := range attestations {
+			if att.Source.Epoch > highest.Source.Epoch {
+				highest.Source.Epoch = att.Source.Epoch
+			}
+			if att.Target.Epoch > highest.Target.Epoch {
+				highest.Target.Epoch = att.Target.Epoch
+			}
+		}
+		attestations = []*core.BeaconAttestation{highest}
+	}
+	for _, att := range attestations {
+		ret.SignedAttestations = append(ret.SignedAttestations, &InterchangeAttestation{
+			SourceEpoch: strconv.FormatUint(att.Source.Epoch, 10),
+			TargetEpoch: strconv.FormatUint(att.Target.Epoch, 10),
+		})
+	}
+	return ret, nil
+}
+
+// watermarkAboveLowest returns true if the watermark must be exported, it's above the lowest value (or there are none)
+// and isn't one of the values already.
+func watermarkAboveLowest(watermark uint64, values []uint64) bool {
+	if len(values) == 0 {
+		return true
+	}
+	lowest := values[0]
+	for _, val := range values {
+		if val == watermark {
+			return false
+		}
+		if val < lowest {
+			lowest = val
+		}
+	}
+	return watermark > lowest
+}
+
+func proposalSlots(proposals []*core.BeaconBlockHeader) []uint64 {
+	ret := make([]uint64, len(proposals))
+	for i, proposal := range proposals {
+		ret[i] = proposal.Slot
+	}
+	return ret
+}
+
+func attestationTargets(attestations []*core.BeaconAttestation) []uint64 {
+	ret := make([]uint64, len(attestations))
+	for i, att := range attestations {
+		ret[i] = att.Target.Epoch
+	}
+	return ret
+}
+
+func encodeInterchangeHex(data []byte) string {
+	return "0x" + hex.EncodeToString(data)
+}
+
+func decodeInterchangeHex(data string) ([]byte, error) {
+	if !strings.HasPrefix(data, "0x") {
+		return nil, fmt.Errorf("hex value %q is not 0x prefixed", data)
+	}
+	return hex.DecodeString(data[2:])
+}
+
+// EIP-3076 encodes uint64 values as decimal strings
+func decodeInterchangeUint64(data string) (uint64, error) {
+	return strconv.ParseUint(data, 10, 64)
+}
@@ -0,0 +1,384 @@
+package slashing_protection
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+var testGenesisValidatorsRoot = _byteArray("04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673")
+
+func attestationReq(source uint64, target uint64, root string) *pb.SignBeaconAttestationRequest {
+	return &pb.SignBeaconAttestationRequest{
+		Id:     nil,
+		Domain: []byte("domain"),
+		Data: &pb.AttestationData{
+			Slot:            target * 32,
+			CommitteeIndex:  1,
+			BeaconBlockRoot: []byte(root),
+			Source: &pb.Checkpoint{
+				Epoch: source,
+				Root:  []byte(root),
+			},
+			Target: &pb.Checkpoint{
+				Epoch: target,
+				Root:  []byte(root),
+			},
+		},
+	}
+}
+
+func proposalReq(slot uint64, root string) *pb.SignBeaconProposalRequest {
+	return &pb.SignBeaconProposalRequest{
+		Id:     nil,
+		Domain: []byte("domain"),
+		Data: &pb.BeaconBlockHeader{
+			Slot:          slot,
+			ProposerIndex: 2,
+			ParentRoot:    []byte(root),
+			StateRoot:     []byte(root),
+			BodyRoot:      []byte(root),
+		},
+	}
+}
+
+func setupInterchange(t *testing.T) (*NormalProtection, e2types.PublicKey) {
+	require.NoError(t, e2types.InitBLS())
+
+	seed := _byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+	vault, err := vault()
+	require.NoError(t, err)
+	w, err := vault.Wallet()
+	require.NoError(t, err)
+	account, err := w.CreateValidatorAccount(seed, nil)
+	require.NoError(t, err)
+
+	protector := NewNormalProtection(vault.Context.Storage.(core.SlashingStore))
+	require.NoError(t, protector.SaveAttestation(account.ValidatorPublicKey(), attestationReq(1, 2, "A")))
+	require.NoError(t, protector.SaveAttestation(account.ValidatorPublicKey(), attestationReq(2, 3, "B")))
+	require.NoError(t, protector.SaveAttestation(account.ValidatorPublicKey(), attestationReq(3, 8, "C")))
+	require.NoError(t, protector.SaveProposal(account.ValidatorPublicKey(), proposalReq(100, "A")))
+	require.NoError(t, protector.SaveProposal(account.ValidatorPublicKey(), proposalReq(101, "B")))
+	return protector, account.ValidatorPublicKey()
+}
+
+func uint64Ptr(val uint64) *uint64 {
+	return &val
+}
+
+// exports and re-imports through json, the way it would go between clients
+func roundTrip(t *testing.T, interchange *Interchange) *Interchange {
+	byts, err := json.Marshal(interchange)
+	require.NoError(t, err)
+	ret := &Interchange{}
+	require.NoError(t, json.Unmarshal(byts, ret))
+	return ret
+}
+
+func TestExportInterchange(t *testing.T) {
+	protector, key := setupInterchange(t)
+
+	t.Run("complete", func(t *testing.T) {
+		interchange, err := protector.ExportInterchange(testGenesisValidatorsRoot, []e2types.PublicKey{key}, InterchangeFormatComplete)
+		require.NoError(t, err)
+		require.EqualValues(t, "5", interchange.Metadata.InterchangeFormatVersion)
+		require.EqualValues(t, "0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673", interchange.Metadata.GenesisValidatorsRoot)
+		require.Len(t, interchange.Data, 1)
+		require.Len(t, interchange.Data[0].SignedAttestations, 3)
+		require.Len(t, interchange.Data[0].SignedBlocks, 2)
+		require.EqualValues(t, "100", interchange.Data[0].SignedBlocks[0].Slot)
+		require.EqualValues(t, "101", interchange.Data[0].SignedBlocks[1].Slot)
+		require.EqualValues(t, "3", interchange.Data[0].SignedAttestations[2].SourceEpoch)
+		require.EqualValues(t, "8", interchange.Data[0].SignedAttestations[2].TargetEpoch)
+	})
+
+	t.Run("minimal", func(t *testing.T) {
+		interchange, err := protector.ExportInterchange(testGenesisValidatorsRoot, []e2types.PublicKey{key}, InterchangeFormatMinimal)
+		require.NoError(t, err)
+		require.Len(t, interchange.Data, 1)
+		require.Len(t, interchange.Data[0].SignedAttestations, 1)
+		require.Len(t, interchange.Data[0].SignedBlocks, 1)
+		require.EqualValues(t, "101", interchange.Data[0].SignedBlocks[0].Slot)
+		require.EqualValues(t, "3", interchange.Data[0].SignedAttestations[0].SourceEpoch)
+		require.EqualValues(t, "8", interchange.Data[0].SignedAttestations[0].TargetEpoch)
+	})
+
+	t.Run("watermark above the history", func(t *testing.T) {
+		protector, key := setupInterchange(t)
+		require.NoError(t, protector.store.SaveWatermark(key, &core.Watermark{MinSourceEpoch: 5, MinTargetEpoch: uint64Ptr(6), MinProposalSlot: uint64Ptr(150)}))
+
+		interchange, err := protector.ExportInterchange(testGenesisValidatorsRoot, []e2types.PublicKey{key}, InterchangeFormatComplete)
+		require.NoError(t, err)
+		require.Len(t, interchange.Data[0].SignedAttestations, 4)
+		require.EqualValues(t, "5", interchange.Data[0].SignedAttestations[3].SourceEpoch)
+		require.EqualValues(t, "6", interchange.Data[0].SignedAttestations[3].TargetEpoch)
+		require.Len(t, interchange.Data[0].SignedBlocks, 3)
+		require.EqualValues(t, "150", interchange.Data[0].SignedBlocks[2].Slot)
+
+		interchange, err = protector.ExportInterchange(testGenesisValidatorsRoot, []e2types.PublicKey{key}, InterchangeFormatMinimal)
+		require.NoError(t, err)
+		require.EqualValues(t, "5", interchange.Data[0].SignedAttestations[0].SourceEpoch)
+		require.EqualValues(t, "8", interchange.Data[0].SignedAttestations[0].TargetEpoch)
+		require.EqualValues(t, "150", interchange.Data[0].SignedBlocks[0].Slot)
+
+		// re-imported elsewhere, nothing at or below the watermark can be signed
+		other, _ := setupInterchange(t)
+		require.NoError(t, other.ImportInterchange(testGenesisValidatorsRoot, roundTrip(t, interchange)))
+		watermark, err := other.store.RetrieveWatermark(key)
+		require.NoError(t, err)
+		require.EqualValues(t, 150, *watermark.MinProposalSlot)
+	})
+
+	t.Run("watermark below the history", func(t *testing.T) {
+		protector, key := setupInterchange(t)
+		require.NoError(t, protector.store.SaveWatermark(key, &core.Watermark{MinSourceEpoch: 0, MinTargetEpoch: uint64Ptr(1), MinProposalSlot: uint64Ptr(100)}))
+
+		interchange, err := protector.ExportInterchange(testGenesisValidatorsRoot, []e2types.PublicKey{key}, InterchangeFormatComplete)
+		require.NoError(t, err)
+		require.Len(t, interchange.Data[0].SignedAttestations, 3)
+		require.Len(t, interchange.Data[0].SignedBlocks, 2)
+	})
+
+	t.Run("watermark without history", func(t *testing.T) {
+		protector, key := setupInterchange(t)
+		require.NoError(t, protector.store.PruneAttestations(key, math.MaxUint64, math.MaxUint64))
+		require.NoError(t, protector.store.PruneProposals(key, math.MaxUint64))
+		require.NoError(t, protector.store.SaveWatermark(key, &core.Watermark{MinSourceEpoch: 3, MinTargetEpoch: uint64Ptr(8), MinProposalSlot: uint64Ptr(101)}))
+
+		interchange, err := protector.ExportInterchange(testGenesisValidatorsRoot, []e2types.PublicKey{key}, InterchangeFormatComplete)
+		require.NoError(t, err)
+		require.Len(t, interchange.Data[0].SignedAttestations, 1)
+		require.EqualValues(t, "3", interchange.Data[0].SignedAttestations[0].SourceEpoch)
+		require.EqualValues(t, "8", interchange.Data[0].SignedAttestations[0].TargetEpoch)
+		require.Len(t, interchange.Data[0].SignedBlocks, 1)
+		require.EqualValues(t, "101", interchange.Data[0].SignedBlocks[0].Slot)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := protector.ExportInterchange(testGenesisValidatorsRoot, []e2types.PublicKey{key}, "other")
+		require.EqualError(t, err, "unknown interchange format: other")
+	})
+}
+
+func TestImportInterchange(t *testing.T) {
+	source, key := setupInterchange(t)
+	interchange, err := source.ExportInterchange(testGenesisValidatorsRoot, []e2types.PublicKey{key}, InterchangeFormatComplete)
+	require.NoError(t, err)
+
+	t.Run("imported history is protected", func(t *testing.T) {
+		protector := NewNormalProtection(store())
+		require.NoError(t, protector.ImportInterchange(testGenesisValidatorsRoot, roundTrip(t, interchange)))
+
+		// double vote, below the imported watermark
+		res, err := protector.IsSlashableAttestation(key, attestationReq(2, 3, "D"))
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.EqualValues(t, core.BelowWatermarkVote, res[0].Status)
+
+		// surrounding vote, source below the imported watermark
+		res, err = protector.IsSlashableAttestation(key, attestationReq(0, 9, "D"))
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.EqualValues(t, core.BelowWatermarkVote, res[0].Status)
+
+		// new attestation
+		res, err = protector.IsSlashableAttestation(key, attestationReq(8, 9, "D"))
+		require.NoError(t, err)
+		require.Len(t, res, 0)
+
+		// double proposal, at the imported watermark
+		status := protector.IsSlashableProposal(key, proposalReq(101, "D"))
+		require.EqualValues(t, core.BelowWatermarkProposal, status.Status)
+
+		// new proposal
+		status = protector.IsSlashableProposal(key, proposalReq(102, "D"))
+		require.EqualValues(t, core.ValidProposal, status.Status)
+	})
+
+	t.Run("merges into existing history", func(t *testing.T) {
+		protector := NewNormalProtection(store())
+		require.NoError(t, protector.SaveAttestation(key, attestationReq(3, 8, "C")))
+		require.NoError(t, protector.SaveAttestation(key, attestationReq(10, 11, "E")))
+		require.NoError(t, protector.SaveProposal(key, proposalReq(101, "E")))
+		require.NoError(t, protector.ImportInterchange(testGenesisValidatorsRoot, roundTrip(t, interchange)))
+
+		// local records at the imported target epoch / slot are kept, the watermark refuses signing them again
+		att, err := protector.store.RetrieveAttestation(key, 8)
+		require.NoError(t, err)
+		require.EqualValues(t, []byte("C"), att.BeaconBlockRoot)
+		proposal, err := protector.store.RetrieveProposal(key, 101)
+		require.NoError(t, err)
+		require.EqualValues(t, []byte("E"), proposal.BodyRoot)
+		res, err := protector.IsSlashableAttestation(key, attestationReq(3, 8, "C"))
+		require.NoError(t, err)
+		require.NotEmpty(t, res)
+
+		// missing records are added
+		proposal, err = protector.store.RetrieveProposal(key, 100)
+		require.NoError(t, err)
+		require.NotNil(t, proposal)
+
+		// local history is kept
+		latest, err := protector.RetrieveLatestAttestation(key)
+		require.NoError(t, err)
+		require.EqualValues(t, 11, latest.Target.Epoch)
+		res, err = protector.IsSlashableAttestation(key, attestationReq(10, 11, "F"))
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.EqualValues(t, core.DoubleVote, res[0].Status)
+	})
+
+	t.Run("updates latest attestation", func(t *testing.T) {
+		protector := NewNormalProtection(store())
+		require.NoError(t, protector.ImportInterchange(testGenesisValidatorsRoot, roundTrip(t, interchange)))
+		latest, err := protector.RetrieveLatestAttestation(key)
+		require.NoError(t, err)
+		require.EqualValues(t, 8, latest.Target.Epoch)
+	})
+
+	t.Run("genesis validators root mismatch", func(t *testing.T) {
+		protector := NewNormalProtection(store())
+		err := protector.ImportInterchange(_byteArray("0000000000000000000000000000000000000000000000000000000000000001"), roundTrip(t, interchange))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "genesis validators root mismatch")
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		protector := NewNormalProtection(store())
+		other := roundTrip(t, interchange)
+		other.Metadata.InterchangeFormatVersion = "3"
+		require.EqualError(t, protector.ImportInterchange(testGenesisValidatorsRoot, other), "unsupported interchange format version: 3")
+	})
+
+	t.Run("invalid record is not partially imported", func(t *testing.T) {
+		protector := NewNormalProtection(store())
+		other := roundTrip(t, interchange)
+		other.Data[0].SignedAttestations = append(other.Data[0].SignedAttestations, &InterchangeAttestation{
+			SourceEpoch: "10",
+			TargetEpoch: "9",
+		})
+		require.Error(t, protector.ImportInterchange(testGenesisValidatorsRoot, other))
+
+		latest, err := protector.RetrieveLatestAttestation(key)
+		require.NoError(t, err)
+		require.Nil(t, latest)
+	})
+
+	t.Run("minimal", func(t *testing.T) {
+		minimal, err := source.ExportInterchange(testGenesisValidatorsRoot, []e2types.PublicKey{key}, InterchangeFormatMinimal)
+		require.NoError(t, err)
+
+		protector := NewNormalProtection(store())
+		require.NoError(t, protector.ImportInterchange(testGenesisValidatorsRoot, roundTrip(t, minimal)))
+
+		status := protector.IsSlashableProposal(key, proposalReq(101, "D"))
+		require.EqualValues(t, core.BelowWatermarkProposal, status.Status)
+		res, err := protector.IsSlashableAttestation(key, attestationReq(3, 8, "D"))
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+
+		// the history below the highest records is unknown
+		status = protector.IsSlashableProposal(key, proposalReq(50, "D"))
+		require.EqualValues(t, core.BelowWatermarkProposal, status.Status)
+		res, err = protector.IsSlashableAttestation(key, attestationReq(1, 2, "D"))
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.EqualValues(t, core.BelowWatermarkVote, res[0].Status)
+	})
+
+	t.Run("watermark at genesis", func(t *testing.T) {
+		protector := NewNormalProtection(store())
+		genesis := roundTrip(t, interchange)
+		genesis.Data[0].SignedBlocks = []*InterchangeBlock{{Slot: "0"}}
+		genesis.Data[0].SignedAttestations = []*InterchangeAttestation{{SourceEpoch: "0", TargetEpoch: "0"}}
+		require.NoError(t, protector.ImportInterchange(testGenesisValidatorsRoot, genesis))
+
+		status := protector.IsSlashableProposal(key, proposalReq(0, "D"))
+		require.EqualValues(t, core.BelowWatermarkProposal, status.Status)
+		res, err := protector.IsSlashableAttestation(key, attestationReq(0, 0, "D"))
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.EqualValues(t, core.BelowWatermarkVote, res[0].Status)
+	})
+
+	t.Run("watermark only goes up", func(t *testing.T) {
+		protector := NewNormalProtection(store())
+		require.NoError(t, protector.store.SaveWatermark(key, &core.Watermark{MinSourceEpoch: 20, MinTargetEpoch: uint64Ptr(21), MinProposalSlot: uint64Ptr(700)}))
+		require.NoError(t, protector.ImportInterchange(testGenesisValidatorsRoot, roundTrip(t, interchange)))
+
+		watermark, err := protector.store.RetrieveWatermark(key)
+		require.NoError(t, err)
+		require.EqualValues(t, &core.Watermark{MinSourceEpoch: 20, MinTargetEpoch: uint64Ptr(21), MinProposalSlot: uint64Ptr(700)}, watermark)
+	})
+}
+
+func TestImportInterchangeJSON(t *testing.T) {
+	require.NoError(t, e2types.InitBLS())
+
+	// taken from EIP-3076
+	data := []byte(`{
+  "metadata": {
+    "interchange_format_version": "5",
+    "genesis_validators_root": "0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673"
+  },
+  "data": [
+    {
+      "pubkey": "0xb845089a1457f811bfc000588fbb4e713669be8ce060ea6be3c6ece09afc3794106c91ca73acda5e5457122d58723bed",
+      "signed_blocks": [
+        {
+          "slot": "81952",
+          "signing_root": "0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b"
+        },
+        {
+          "slot": "81951"
+        }
+      ],
+      "signed_attestations": [
+        {
+          "source_epoch": "2290",
+          "target_epoch": "3007",
+          "signing_root": "0x587d6a4f59a58fe24f406e0502413e77fe1babddee641fda30034ed37ecc884d"
+        },
+        {
+          "source_epoch": "2290",
+          "target_epoch": "3008"
+        }
+      ]
+    }
+  ]
+}`)
+	interchange := &Interchange{}
+	require.NoError(t, json.Unmarshal(data, interchange))
+
+	protector := NewNormalProtection(store())
+	require.NoError(t, protector.ImportInterchange(testGenesisValidatorsRoot, interchange))
+
+	key, err := e2types.BLSPublicKeyFromBytes(_byteArray("b845089a1457f811bfc000588fbb4e713669be8ce060ea6be3c6ece09afc3794106c91ca73acda5e5457122d58723bed"))
+	require.NoError(t, err)
+	watermark, err := protector.store.RetrieveWatermark(key)
+	require.NoError(t, err)
+	require.EqualValues(t, &core.Watermark{MinSourceEpoch: 2290, MinTargetEpoch: uint64Ptr(3008), MinProposalSlot: uint64Ptr(81952)}, watermark)
+
+	status := protector.IsSlashableProposal(key, proposalReq(81952, "A"))
+	require.EqualValues(t, core.BelowWatermarkProposal, status.Status)
+	status = protector.IsSlashableProposal(key, proposalReq(81953, "A"))
+	require.EqualValues(t, core.ValidProposal, status.Status)
+
+	res, err := protector.IsSlashableAttestation(key, attestationReq(2290, 3008, "A"))
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.EqualValues(t, core.BelowWatermarkVote, res[0].Status)
+	res, err = protector.IsSlashableAttestation(key, attestationReq(2289, 3009, "A"))
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.EqualValues(t, core.BelowWatermarkVote, res[0].Status)
+	res, err = protector.IsSlashableAttestation(key, attestationReq(2290, 3009, "A"))
+	require.NoError(t, err)
+	require.Len(t, res, 0)
+}
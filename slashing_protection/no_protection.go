@@ -36,3 +36,18 @@ func (p *NoProtection) SaveLatestAttestation(key e2types.PublicKey, req *pb.Sign
 func (p *NoProtection) RetrieveLatestAttestation(key e2types.PublicKey) (*core.BeaconAttestation, error) {
 	return nil, nil
 }
+
+func (p *NoProtection) RetrieveHighestProposal(key e2types.PublicKey) (*core.BeaconBlockHeader, error) {
+	return nil, nil
+}
+
+func (p *NoProtection) CheckAndRecordAttestation(key e2types.PublicKey, req *pb.SignBeaconAttestationRequest) ([]*core.AttestationSlashStatus, error) {
+	return make([]*core.AttestationSlashStatus, 0), nil
+}
+
+func (p *NoProtection) CheckAndRecordProposal(key e2types.PublicKey, req *pb.SignBeaconProposalRequest) *core.ProposalSlashStatus {
+	return &core.ProposalSlashStatus{
+		Proposal: nil,
+		Status:   core.ValidProposal,
+	}
+}
@@ -1,6 +1,9 @@
 package slashing_protection
 
 import (
+	"math"
+
+	"github.com/pkg/errors"
 	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
 	e2types "github.com/wealdtech/go-eth2-types/v2"
 
@@ -15,39 +18,98 @@ func NewNormalProtection(store core.SlashingStore) *NormalProtection {
 	return &NormalProtection{store: store}
 }
 
-// From prysm:
-// We look back 128 epochs when updating min/max spans
-// for incoming attestations.
-// TODO - verify this is true
-const epochLookback = 128
-
-// will detect double, surround and surrounded slashable events
+// will detect double, surround and surrounded slashable events.
+// Double votes are looked up by the target epoch and surround votes by the min/max spans, the history is only listed to
+// report the surrounding/ surrounded attestations once the spans detected them.
 func (protector *NormalProtection) IsSlashableAttestation(key e2types.PublicKey, req *pb.SignBeaconAttestationRequest) ([]*core.AttestationSlashStatus, error) {
 	data := core.ToCoreAttestationData(req)
+	ret := make([]*core.AttestationSlashStatus, 0)
 
-	lookupStartEpoch := lookupEpochSub(data.Source.Epoch, epochLookback)
-	lookupEndEpoch := req.Data.Target.Epoch
-
-	// lookupEndEpoch should be the latest written attestation, if not than req.Data.Target.Epoch
-	latestAtt, err := protector.RetrieveLatestAttestation(key)
+	watermark, err := protector.store.RetrieveWatermark(key)
 	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve watermark")
+	}
+	if watermark != nil && (data.Source.Epoch < watermark.MinSourceEpoch || (watermark.MinTargetEpoch != nil && data.Target.Epoch <= *watermark.MinTargetEpoch)) {
+		return append(ret, &core.AttestationSlashStatus{Status: core.BelowWatermarkVote}), nil
+	}
+
+	existing, err := protector.store.RetrieveAttestation(key, data.Target.Epoch)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
 		return nil, err
 	}
-	if latestAtt != nil {
-		lookupEndEpoch = latestAtt.Target.Epoch
+	if existing != nil {
+		ret = append(ret, data.SlashesAttestations([]*core.BeaconAttestation{existing})...)
 	}
 
-	history, err := protector.store.ListAttestations(key, lookupStartEpoch, lookupEndEpoch)
+	spans, err := newSpanUpdater(protector.store, key)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "failed to load attestation spans")
+	}
+	surrounding, surrounded, err := spans.surrounds(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check attestation spans")
 	}
+	if surrounding {
+		// surrounded attestations have a target between our source and target
+		statuses, err := protector.surroundVotes(key, data, data.Source.Epoch+1, data.Target.Epoch-1, core.SurroundingVote)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, statuses...)
+	}
+	if surrounded {
+		// surrounding attestations have a target above ours
+		latest, err := protector.store.RetrieveLatestAttestation(key)
+		if err != nil {
+			return nil, err
+		}
+		end := data.Target.Epoch
+		if latest != nil {
+			end = latest.Target.Epoch
+		}
+		statuses, err := protector.surroundVotes(key, data, data.Target.Epoch+1, end, core.SurroundedVote)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, statuses...)
+	}
+	return ret, nil
+}
 
-	return data.SlashesAttestations(history), nil
+// surroundVotes returns the attestations with a target between start and end that the spans found in conflict with
+// data. The spans also cover votes missing from the history (e.g. imported at a target epoch with a local vote) so
+// the conflict is reported without the attestation if it's not there.
+func (protector *NormalProtection) surroundVotes(key e2types.PublicKey, data *core.BeaconAttestation, start uint64, end uint64, status core.VoteDetectionType) ([]*core.AttestationSlashStatus, error) {
+	if start <= end {
+		history, err := protector.store.ListAttestations(key, start, end)
+		if err != nil {
+			return nil, err
+		}
+		if ret := data.SlashesAttestations(history); len(ret) > 0 {
+			return ret, nil
+		}
+	}
+	return []*core.AttestationSlashStatus{{Status: status}}, nil
 }
 
 func (protector *NormalProtection) IsSlashableProposal(key e2types.PublicKey, req *pb.SignBeaconProposalRequest) *core.ProposalSlashStatus {
+	watermark, err := protector.store.RetrieveWatermark(key)
+	if err != nil {
+		return &core.ProposalSlashStatus{
+			Proposal: nil,
+			Status:   core.Error,
+			Error:    errors.Wrap(err, "failed to retrieve watermark"),
+		}
+	}
+	if watermark != nil && watermark.MinProposalSlot != nil && req.Data.Slot <= *watermark.MinProposalSlot {
+		return &core.ProposalSlashStatus{
+			Proposal: core.ToCoreBlockData(req),
+			Status:   core.BelowWatermarkProposal,
+		}
+	}
+
 	matchedProposal, err := protector.store.RetrieveProposal(key, req.Data.Slot)
-	if err != nil && err.Error() != "proposal not found" {
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
 		return &core.ProposalSlashStatus{
 			Proposal: nil,
 			Status:   core.Error,
@@ -74,15 +136,28 @@ func (protector *NormalProtection) IsSlashableProposal(key e2types.PublicKey, re
 
 	// slashable
 	return &core.ProposalSlashStatus{
-		Proposal: data,
-		Status:   core.DoubleProposal,
+		Proposal:    data,
+		Conflicting: matchedProposal,
+		Status:      core.DoubleProposal,
 	}
 }
 
 func (protector *NormalProtection) SaveAttestation(key e2types.PublicKey, req *pb.SignBeaconAttestationRequest) error {
 	data := core.ToCoreAttestationData(req)
-	err := protector.store.SaveAttestation(key, data)
+
+	// spans go first, if saving the attestation fails they refuse more than needed rather than less
+	spans, err := newSpanUpdater(protector.store, key)
 	if err != nil {
+		return errors.Wrap(err, "failed to load attestation spans")
+	}
+	if err := spans.add(data); err != nil {
+		return errors.Wrap(err, "failed to update attestation spans")
+	}
+	if err := spans.save(); err != nil {
+		return errors.Wrap(err, "failed to save attestation spans")
+	}
+
+	if err := protector.store.SaveAttestation(key, data); err != nil {
 		return err
 	}
 	return protector.SaveLatestAttestation(key, req)
@@ -96,7 +171,7 @@ func (protector *NormalProtection) SaveProposal(key e2types.PublicKey, req *pb.S
 func (protector *NormalProtection) SaveLatestAttestation(key e2types.PublicKey, req *pb.SignBeaconAttestationRequest) error {
 	val, err := protector.store.RetrieveLatestAttestation(key)
 	if err != nil {
-		return nil
+		return errors.Wrap(err, "failed to retrieve latest attestation")
 	}
 
 	data := core.ToCoreAttestationData(req)
@@ -110,14 +185,90 @@ func (protector *NormalProtection) SaveLatestAttestation(key e2types.PublicKey,
 	return nil
 }
 
+// CheckAndRecordAttestation implements core.SlashingProtector, the check and the records are done in a single
+// store transaction so concurrent signers can't both sign conflicting attestations.
+func (protector *NormalProtection) CheckAndRecordAttestation(key e2types.PublicKey, req *pb.SignBeaconAttestationRequest) ([]*core.AttestationSlashStatus, error) {
+	var ret []*core.AttestationSlashStatus
+	err := protector.store.Atomically(key, func(store core.SlashingStore) error {
+		tx := NewNormalProtection(store)
+		var err error
+		if ret, err = tx.IsSlashableAttestation(key, req); err != nil || len(ret) > 0 {
+			return err
+		}
+		return tx.SaveAttestation(key, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// CheckAndRecordProposal implements core.SlashingProtector, see CheckAndRecordAttestation.
+func (protector *NormalProtection) CheckAndRecordProposal(key e2types.PublicKey, req *pb.SignBeaconProposalRequest) *core.ProposalSlashStatus {
+	var ret *core.ProposalSlashStatus
+	err := protector.store.Atomically(key, func(store core.SlashingStore) error {
+		tx := NewNormalProtection(store)
+		if ret = tx.IsSlashableProposal(key, req); ret.Status != core.ValidProposal {
+			return nil
+		}
+		return tx.SaveProposal(key, req)
+	})
+	if err != nil {
+		return &core.ProposalSlashStatus{
+			Proposal: nil,
+			Status:   core.Error,
+			Error:    err,
+		}
+	}
+	return ret
+}
+
 func (protector *NormalProtection) RetrieveLatestAttestation(key e2types.PublicKey) (*core.BeaconAttestation, error) {
 	return protector.store.RetrieveLatestAttestation(key)
 }
 
-// specialized func that will prevent overflow for lookup epochs for uint64
-func lookupEpochSub(l uint64, r uint64) uint64 {
-	if l >= r {
-		return l - r
+func (protector *NormalProtection) RetrieveHighestProposal(key e2types.PublicKey) (*core.BeaconBlockHeader, error) {
+	proposals, err := protector.store.ListProposals(key, 0, math.MaxUint64)
+	if err != nil {
+		return nil, err
+	}
+	if len(proposals) == 0 {
+		return nil, nil
+	}
+	return proposals[len(proposals)-1], nil // sorted by slot
+}
+
+// raiseWatermark raises the key's watermark to the highest of the given records, used when the history below them isn't
+// fully known (imported or pruned) so nothing at or below them can be signed safely.
+func (protector *NormalProtection) raiseWatermark(key e2types.PublicKey, attestations []*core.BeaconAttestation, proposals []*core.BeaconBlockHeader) error {
+	if len(attestations) == 0 && len(proposals) == 0 {
+		return nil
+	}
+	watermark, err := protector.store.RetrieveWatermark(key)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve watermark")
 	}
-	return 0
+	if watermark == nil {
+		watermark = &core.Watermark{}
+	}
+	ret := *watermark
+	for _, att := range attestations {
+		if att.Source.Epoch > ret.MinSourceEpoch {
+			ret.MinSourceEpoch = att.Source.Epoch
+		}
+		if ret.MinTargetEpoch == nil || att.Target.Epoch > *ret.MinTargetEpoch {
+			target := att.Target.Epoch
+			ret.MinTargetEpoch = &target
+		}
+	}
+	for _, proposal := range proposals {
+		if ret.MinProposalSlot == nil || proposal.Slot > *ret.MinProposalSlot {
+			slot := proposal.Slot
+			ret.MinProposalSlot = &slot
+		}
+	}
+	if err := protector.store.SaveWatermark(key, &ret); err != nil {
+		return errors.Wrap(err, "failed to save watermark")
+	}
+	return nil
 }
@@ -0,0 +1,109 @@
+package slashing_protection
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// failingStore fails to read the latest attestation
+type failingStore struct {
+	core.SlashingStore
+}
+
+func (store *failingStore) RetrieveLatestAttestation(key e2types.PublicKey) (*core.BeaconAttestation, error) {
+	return nil, fmt.Errorf("test")
+}
+
+func TestCheckAndRecordAttestation(t *testing.T) {
+	protector, store, key := setupSpans(t)
+
+	t.Run("concurrent double votes", func(t *testing.T) {
+		var wg sync.WaitGroup
+		signed := make(chan string, 20)
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(root string) {
+				defer wg.Done()
+				res, err := protector.CheckAndRecordAttestation(key, attestationReq(1, 2, root))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if len(res) == 0 {
+					signed <- root
+				}
+			}(fmt.Sprintf("%d", i))
+		}
+		wg.Wait()
+		close(signed)
+		require.Len(t, signed, 1)
+
+		atts, err := store.ListAttestations(key, 0, 2)
+		require.NoError(t, err)
+		require.Len(t, atts, 1)
+		require.EqualValues(t, <-signed, string(atts[0].BeaconBlockRoot))
+	})
+
+	t.Run("slashable isn't recorded", func(t *testing.T) {
+		res, err := protector.CheckAndRecordAttestation(key, attestationReq(0, 3, "A"))
+		require.NoError(t, err)
+		require.EqualValues(t, []string{"SurroundingVote-2"}, slashStatuses(res))
+
+		atts, err := store.ListAttestations(key, 3, 3)
+		require.NoError(t, err)
+		require.Len(t, atts, 0)
+	})
+
+	t.Run("recorded", func(t *testing.T) {
+		res, err := protector.CheckAndRecordAttestation(key, attestationReq(2, 3, "A"))
+		require.NoError(t, err)
+		require.Len(t, res, 0)
+
+		latest, err := store.RetrieveLatestAttestation(key)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, latest.Target.Epoch)
+		res, err = protector.IsSlashableAttestation(key, attestationReq(1, 4, "A"))
+		require.NoError(t, err)
+		require.EqualValues(t, []string{"SurroundingVote-3"}, slashStatuses(res))
+	})
+}
+
+func TestCheckAndRecordProposal(t *testing.T) {
+	protector, store, key := setupSpans(t)
+
+	var wg sync.WaitGroup
+	signed := make(chan string, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(root string) {
+			defer wg.Done()
+			status := protector.CheckAndRecordProposal(key, proposalReq(5, root))
+			if status.Error != nil {
+				t.Error(status.Error)
+				return
+			}
+			if status.Status == core.ValidProposal {
+				signed <- root
+			}
+		}(fmt.Sprintf("%d", i))
+	}
+	wg.Wait()
+	close(signed)
+	require.Len(t, signed, 1)
+
+	proposals, err := store.ListProposals(key, 0, 5)
+	require.NoError(t, err)
+	require.Len(t, proposals, 1)
+}
+
+func TestSaveLatestAttestationError(t *testing.T) {
+	_, store, key := setupSpans(t)
+	protector := NewNormalProtection(&failingStore{SlashingStore: store})
+	require.EqualError(t, protector.SaveLatestAttestation(key, attestationReq(1, 2, "A")), "failed to retrieve latest attestation: test")
+}
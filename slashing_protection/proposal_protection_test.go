@@ -172,3 +172,19 @@ func TestDoubleProposal(t *testing.T) {
 		require.Equal(t, res.Status, core.DoubleProposal)
 	})
 }
+
+func TestRetrieveHighestProposal(t *testing.T) {
+	protector, accounts, err := setupProposal()
+	require.NoError(t, err)
+
+	highest, err := protector.RetrieveHighestProposal(accounts[0].ValidatorPublicKey())
+	require.NoError(t, err)
+	require.NotNil(t, highest)
+	require.EqualValues(t, 102, highest.Slot)
+
+	t.Run("no proposals", func(t *testing.T) {
+		highest, err := protector.RetrieveHighestProposal(accounts[1].ValidatorPublicKey())
+		require.NoError(t, err)
+		require.Nil(t, highest)
+	})
+}
@@ -0,0 +1,170 @@
+package slashing_protection
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// Prune compacts the key's slashing history older than horizon epochs into its watermark, attestations are kept from
+// horizon epochs below the latest attestation's target and proposals from horizon epochs below the highest proposal.
+// Everything pruned stays refused, the watermark is raised to the highest pruned records before they are deleted.
+// It runs atomically so it can't interleave with CheckAndRecordAttestation/ CheckAndRecordProposal.
+func (protector *NormalProtection) Prune(key e2types.PublicKey, horizon uint64) error {
+	return protector.store.Atomically(key, func(store core.SlashingStore) error {
+		tx := NewNormalProtection(store)
+		if err := tx.pruneAttestations(key, horizon); err != nil {
+			return err
+		}
+		return tx.pruneProposals(key, horizon)
+	})
+}
+
+func (protector *NormalProtection) pruneAttestations(key e2types.PublicKey, horizon uint64) error {
+	latest, err := protector.store.RetrieveLatestAttestation(key)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve latest attestation")
+	}
+	if latest == nil || latest.Target.Epoch <= horizon {
+		return nil
+	}
+	targetEpoch := latest.Target.Epoch - horizon
+
+	// spans are built from the history so they must exist before it's pruned
+	spans, err := newSpanUpdater(protector.store, key)
+	if err != nil {
+		return errors.Wrap(err, "failed to load attestation spans")
+	}
+	pruned, err := protector.store.ListAttestations(key, 0, targetEpoch-1)
+	if err != nil {
+		return errors.Wrap(err, "failed to list attestations")
+	}
+	if len(pruned) == 0 {
+		return nil
+	}
+	if err := protector.raiseWatermark(key, pruned, nil); err != nil {
+		return err
+	}
+
+	// spans are only read at the source of an attestation, below the watermark's source they're never read again
+	watermark, err := protector.store.RetrieveWatermark(key)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve watermark")
+	}
+	spanEpoch := watermark.MinSourceEpoch
+	if spans.bounds != nil && spans.bounds.LowestSource < spanEpoch {
+		bounds := *spans.bounds
+		bounds.LowestSource = spanEpoch
+		if err := protector.store.SaveAttestationSpans(key, &bounds, nil); err != nil {
+			return errors.Wrap(err, "failed to save attestation spans")
+		}
+	}
+
+	if err := protector.store.PruneAttestations(key, targetEpoch, spanEpoch); err != nil {
+		return errors.Wrap(err, "failed to prune attestations")
+	}
+	return nil
+}
+
+func (protector *NormalProtection) pruneProposals(key e2types.PublicKey, horizon uint64) error {
+	highest, err := protector.RetrieveHighestProposal(key)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve highest proposal")
+	}
+	if highest == nil || highest.Slot <= horizon*core.SlotsPerEpoch {
+		return nil
+	}
+	slot := highest.Slot - horizon*core.SlotsPerEpoch
+
+	pruned, err := protector.store.ListProposals(key, 0, slot-1)
+	if err != nil {
+		return errors.Wrap(err, "failed to list proposals")
+	}
+	if len(pruned) == 0 {
+		return nil
+	}
+	if err := protector.raiseWatermark(key, nil, pruned); err != nil {
+		return err
+	}
+	if err := protector.store.PruneProposals(key, slot); err != nil {
+		return errors.Wrap(err, "failed to prune proposals")
+	}
+	return nil
+}
+
+// Pruner prunes the slashing history of every key in the store on an interval, see NormalProtection.Prune.
+type Pruner struct {
+	protector *NormalProtection
+	horizon   uint64
+	interval  time.Duration
+	onError   func(err error)
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewPruner is the constructor of Pruner, onError is called with pruning errors (could be nil).
+// Start must be called for it to run.
+func NewPruner(protector *NormalProtection, horizon uint64, interval time.Duration, onError func(err error)) *Pruner {
+	return &Pruner{
+		protector: protector,
+		horizon:   horizon,
+		interval:  interval,
+		onError:   onError,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start prunes right away and then on every interval until Stop is called.
+func (pruner *Pruner) Start() {
+	go func() {
+		defer close(pruner.done)
+		ticker := time.NewTicker(pruner.interval)
+		defer ticker.Stop()
+		for {
+			if err := pruner.Prune(); err != nil && pruner.onError != nil {
+				pruner.onError(err)
+			}
+			select {
+			case <-pruner.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops a started pruner and waits for a running prune to finish.
+func (pruner *Pruner) Stop() {
+	pruner.stopOnce.Do(func() {
+		close(pruner.stop)
+	})
+	<-pruner.done
+}
+
+// Prune prunes the history of every key once, a failing key doesn't stop the others from being pruned and the
+// returned error lists every failure.
+func (pruner *Pruner) Prune() error {
+	keys, err := pruner.protector.store.ListSlashingKeys()
+	if err != nil {
+		return errors.Wrap(err, "failed to list slashing keys")
+	}
+	failures := make([]string, 0)
+	for _, key := range keys {
+		if err := pruner.protector.Prune(key, pruner.horizon); err != nil {
+			failures = append(failures, errors.Wrapf(err, "failed to prune %x", key.Marshal()).Error())
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package slashing_protection
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+func TestPrune(t *testing.T) {
+	protector, store, key := setupSpans(t)
+	full, fullStore, _ := setupSpans(t) // same history, never pruned
+
+	// random history, slashable records included
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 150; i++ {
+		source := uint64(r.Intn(400))
+		req := attestationReq(source, source+uint64(r.Intn(50)), fmt.Sprintf("%d", r.Int()))
+		if existing, _ := store.RetrieveAttestation(key, req.Data.Target.Epoch); existing != nil {
+			continue
+		}
+		require.NoError(t, protector.SaveAttestation(key, req))
+		require.NoError(t, full.SaveAttestation(key, req))
+	}
+	for slot := uint64(0); slot < 450*core.SlotsPerEpoch; slot += 50 {
+		require.NoError(t, protector.SaveProposal(key, proposalReq(slot, "A")))
+		require.NoError(t, full.SaveProposal(key, proposalReq(slot, "A")))
+	}
+
+	latest, err := store.RetrieveLatestAttestation(key)
+	require.NoError(t, err)
+	highest, err := protector.RetrieveHighestProposal(key)
+	require.NoError(t, err)
+	require.NoError(t, protector.Prune(key, 100))
+
+	t.Run("history is pruned", func(t *testing.T) {
+		targetEpoch := latest.Target.Epoch - 100
+		atts, err := store.ListAttestations(key, 0, targetEpoch-1)
+		require.NoError(t, err)
+		require.Len(t, atts, 0)
+		kept, err := store.ListAttestations(key, targetEpoch, latest.Target.Epoch)
+		require.NoError(t, err)
+		expected, err := fullStore.ListAttestations(key, targetEpoch, latest.Target.Epoch)
+		require.NoError(t, err)
+		require.EqualValues(t, expected, kept)
+
+		slot := highest.Slot - 100*core.SlotsPerEpoch
+		proposals, err := store.ListProposals(key, 0, slot-1)
+		require.NoError(t, err)
+		require.Len(t, proposals, 0)
+		proposals, err = store.ListProposals(key, slot, highest.Slot)
+		require.NoError(t, err)
+		require.Len(t, proposals, int(100*core.SlotsPerEpoch/50)+1)
+
+		watermark, err := store.RetrieveWatermark(key)
+		require.NoError(t, err)
+		require.NotNil(t, watermark)
+		require.True(t, *watermark.MinTargetEpoch < targetEpoch)
+		require.True(t, *watermark.MinProposalSlot < slot)
+	})
+
+	t.Run("slashable attestations stay refused", func(t *testing.T) {
+		watermark, err := store.RetrieveWatermark(key)
+		require.NoError(t, err)
+		for source := uint64(0); source < 460; source++ {
+			for target := source; target < 460; target += 3 {
+				req := attestationReq(source, target, "B")
+				expected, err := full.IsSlashableAttestation(key, req)
+				require.NoError(t, err)
+				res, err := protector.IsSlashableAttestation(key, req)
+				require.NoError(t, err)
+
+				if len(expected) > 0 {
+					require.NotEmpty(t, res, "source %d target %d", source, target)
+				}
+				// above the watermark nothing more is refused
+				if source >= watermark.MinSourceEpoch && target > *watermark.MinTargetEpoch && len(expected) == 0 {
+					require.Empty(t, res, "source %d target %d", source, target)
+				}
+			}
+		}
+	})
+
+	t.Run("slashable proposals stay refused", func(t *testing.T) {
+		for slot := uint64(0); slot < 450*core.SlotsPerEpoch; slot += 25 {
+			req := proposalReq(slot, "B")
+			if full.IsSlashableProposal(key, req).Status != core.ValidProposal {
+				require.NotEqual(t, core.ValidProposal, protector.IsSlashableProposal(key, req).Status, "slot %d", slot)
+			}
+		}
+	})
+
+	t.Run("new records are saved", func(t *testing.T) {
+		req := attestationReq(latest.Target.Epoch, latest.Target.Epoch+1, "C")
+		res, err := protector.IsSlashableAttestation(key, req)
+		require.NoError(t, err)
+		require.Len(t, res, 0)
+		require.NoError(t, protector.SaveAttestation(key, req))
+
+		res, err = protector.IsSlashableAttestation(key, attestationReq(latest.Target.Epoch, latest.Target.Epoch+1, "D"))
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.EqualValues(t, core.DoubleVote, res[0].Status)
+	})
+
+	t.Run("pruning again", func(t *testing.T) {
+		require.NoError(t, protector.Prune(key, 100))
+		require.NoError(t, protector.Prune(key, 0))
+
+		latest, err := store.RetrieveLatestAttestation(key)
+		require.NoError(t, err)
+		atts, err := store.ListAttestations(key, 0, latest.Target.Epoch)
+		require.NoError(t, err)
+		require.Len(t, atts, 1)
+
+		res, err := protector.IsSlashableAttestation(key, attestationReq(latest.Source.Epoch, latest.Target.Epoch, "E"))
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.EqualValues(t, core.DoubleVote, res[0].Status)
+	})
+}
+
+func TestPrunerSchedule(t *testing.T) {
+	protector, store, key := setupSpans(t)
+	for epoch := uint64(1); epoch <= 20; epoch++ {
+		require.NoError(t, protector.SaveAttestation(key, attestationReq(epoch-1, epoch, "A")))
+	}
+
+	pruner := NewPruner(protector, 5, time.Hour, func(err error) {
+		t.Error(err)
+	})
+	pruner.Start()
+	pruner.Stop()
+
+	atts, err := store.ListAttestations(key, 0, 20)
+	require.NoError(t, err)
+	require.Len(t, atts, 6)
+	watermark, err := store.RetrieveWatermark(key)
+	require.NoError(t, err)
+	require.EqualValues(t, &core.Watermark{MinSourceEpoch: 13, MinTargetEpoch: uint64Ptr(14)}, watermark)
+}
+
+// brokenStore fails every transaction, so every key fails to be pruned
+type brokenStore struct {
+	core.SlashingStore
+}
+
+func (store *brokenStore) Atomically(key e2types.PublicKey, fn func(store core.SlashingStore) error) error {
+	return fmt.Errorf("broken store")
+}
+
+func TestPrunerReportsEveryFailure(t *testing.T) {
+	require.NoError(t, e2types.InitBLS())
+	vault, err := vault()
+	require.NoError(t, err)
+	w, err := vault.Wallet()
+	require.NoError(t, err)
+	seed := _byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+	store := vault.Context.Storage.(core.SlashingStore)
+	protector := NewNormalProtection(store)
+	for i := 0; i < 2; i++ {
+		account, err := w.CreateValidatorAccount(seed, nil)
+		require.NoError(t, err)
+		require.NoError(t, protector.SaveAttestation(account.ValidatorPublicKey(), attestationReq(0, 1, "A")))
+	}
+
+	err = NewPruner(NewNormalProtection(&brokenStore{store}), 5, time.Hour, nil).Prune()
+	require.Error(t, err)
+	require.EqualValues(t, 2, strings.Count(err.Error(), "broken store"))
+}
@@ -0,0 +1,165 @@
+package slashing_protection
+
+import (
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// Surround votes are detected with min/max spans (https://github.com/protolambda/eth2-surround#min-max-surround)
+// so the check doesn't depend on the size of the attestation history, for an attestation (s, t):
+//   - it surrounds a previous attestation if minSpan(s) < t - s
+//   - it is surrounded by a previous attestation if maxSpan(s) > t - s
+//
+// Min spans of epochs below the lowest attested source aren't saved, they are derived from core.SpanBounds.
+type spanUpdater struct {
+	store  core.SlashingStore
+	key    e2types.PublicKey
+	bounds *core.SpanBounds           // nil until the key has attestations
+	spans  map[uint64]*core.EpochSpan // spans changed since the last save
+}
+
+// newSpanUpdater returns the span updater of the key.
+// Spans of stores written before spans were kept are built from the attestation history and saved.
+func newSpanUpdater(store core.SlashingStore, key e2types.PublicKey) (*spanUpdater, error) {
+	bounds, err := store.RetrieveAttestationSpanBounds(key)
+	if err != nil {
+		return nil, err
+	}
+	ret := &spanUpdater{
+		store:  store,
+		key:    key,
+		bounds: bounds,
+		spans:  make(map[uint64]*core.EpochSpan),
+	}
+	if bounds != nil {
+		return ret, nil
+	}
+
+	latest, err := store.RetrieveLatestAttestation(key)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return ret, nil
+	}
+	history, err := store.ListAttestations(key, 0, latest.Target.Epoch)
+	if err != nil {
+		return nil, err
+	}
+	for _, att := range history {
+		if err := ret.add(att); err != nil {
+			return nil, err
+		}
+	}
+	if err := ret.save(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// span returns a copy of the epoch's span, changing it has no effect until it's added to spans.
+func (updater *spanUpdater) span(epoch uint64) (*core.EpochSpan, error) {
+	if span, exists := updater.spans[epoch]; exists {
+		return span, nil
+	}
+	if updater.bounds == nil {
+		return &core.EpochSpan{}, nil
+	}
+	if epoch < updater.bounds.LowestSource {
+		return &core.EpochSpan{MinSpan: updater.bounds.LowestTarget - epoch}, nil
+	}
+
+	span, err := updater.store.RetrieveAttestationSpan(updater.key, epoch)
+	if err != nil {
+		return nil, err
+	}
+	if span == nil {
+		return &core.EpochSpan{}, nil
+	}
+	ret := *span
+	return &ret, nil
+}
+
+// add updates the spans with the attestation, spans are only walked until an epoch already covering the attestation.
+func (updater *spanUpdater) add(att *core.BeaconAttestation) error {
+	source, target := att.Source.Epoch, att.Target.Epoch
+	if source > target {
+		return nil // can't surround or be surrounded
+	}
+
+	if updater.bounds == nil {
+		updater.bounds = &core.SpanBounds{LowestSource: source, LowestTarget: target}
+	}
+	bounds := *updater.bounds
+
+	// epochs between the new and the previous lowest source are no longer covered by the bounds, all previous
+	// attestations have a higher source so their min span is still the one derived from the bounds.
+	for epoch := source; epoch < bounds.LowestSource; epoch++ {
+		span, err := updater.span(epoch)
+		if err != nil {
+			return err
+		}
+		updater.spans[epoch] = span
+	}
+	if source < bounds.LowestSource {
+		bounds.LowestSource = source
+	}
+
+	// min spans of the epochs below the source, lower ones are derived from the bounds
+	for epoch := source; epoch > bounds.LowestSource; {
+		epoch--
+		span, err := updater.span(epoch)
+		if err != nil {
+			return err
+		}
+		if span.MinSpan != 0 && span.MinSpan <= target-epoch {
+			break
+		}
+		span.MinSpan = target - epoch
+		updater.spans[epoch] = span
+	}
+	if target < bounds.LowestTarget {
+		bounds.LowestTarget = target
+	}
+	updater.bounds = &bounds
+
+	// max spans of the epochs between the source and the target
+	for epoch := source + 1; epoch < target; epoch++ {
+		span, err := updater.span(epoch)
+		if err != nil {
+			return err
+		}
+		if span.MaxSpan >= target-epoch {
+			break
+		}
+		span.MaxSpan = target - epoch
+		updater.spans[epoch] = span
+	}
+	return nil
+}
+
+// save writes the changed spans and the bounds to the store.
+func (updater *spanUpdater) save() error {
+	if updater.bounds == nil {
+		return nil
+	}
+	if err := updater.store.SaveAttestationSpans(updater.key, updater.bounds, updater.spans); err != nil {
+		return err
+	}
+	updater.spans = make(map[uint64]*core.EpochSpan)
+	return nil
+}
+
+// surrounds returns whether the attestation surrounds a previous attestation and whether it is surrounded by one.
+func (updater *spanUpdater) surrounds(att *core.BeaconAttestation) (bool, bool, error) {
+	if updater.bounds == nil || att.Source.Epoch > att.Target.Epoch {
+		return false, false, nil
+	}
+	span, err := updater.span(att.Source.Epoch)
+	if err != nil {
+		return false, false, err
+	}
+	distance := att.Target.Epoch - att.Source.Epoch
+	return span.MinSpan != 0 && span.MinSpan < distance, span.MaxSpan > distance, nil
+}
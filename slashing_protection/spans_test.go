@@ -0,0 +1,177 @@
+package slashing_protection
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+func setupSpans(t testing.TB) (*NormalProtection, core.SlashingStore, e2types.PublicKey) {
+	require.NoError(t, e2types.InitBLS())
+
+	seed := _byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+	vault, err := vault()
+	require.NoError(t, err)
+	w, err := vault.Wallet()
+	require.NoError(t, err)
+	account, err := w.CreateValidatorAccount(seed, nil)
+	require.NoError(t, err)
+
+	slashingStore := vault.Context.Storage.(core.SlashingStore)
+	return NewNormalProtection(slashingStore), slashingStore, account.ValidatorPublicKey()
+}
+
+// statuses as sorted "status-target" strings so detections can be compared regardless of order
+func slashStatuses(statuses []*core.AttestationSlashStatus) []string {
+	ret := make([]string, 0)
+	for _, status := range statuses {
+		ret = append(ret, fmt.Sprintf("%s-%d", status.Status, status.Attestation.Target.Epoch))
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func TestSpansMatchHistory(t *testing.T) {
+	protector, store, key := setupSpans(t)
+	r := rand.New(rand.NewSource(1))
+	randomAttestation := func() *pb.SignBeaconAttestationRequest {
+		source := uint64(r.Intn(2000))
+		return attestationReq(source, source+uint64(r.Intn(300)), fmt.Sprintf("%d", r.Int()))
+	}
+
+	for i := 0; i < 200; i++ {
+		// every request is checked against the full history
+		for j := 0; j < 10; j++ {
+			req := randomAttestation()
+			res, err := protector.IsSlashableAttestation(key, req)
+			require.NoError(t, err)
+
+			history, err := store.ListAttestations(key, 0, 2300)
+			require.NoError(t, err)
+			expected := core.ToCoreAttestationData(req).SlashesAttestations(history)
+			require.EqualValues(t, slashStatuses(expected), slashStatuses(res), "source %d target %d", req.Data.Source.Epoch, req.Data.Target.Epoch)
+		}
+
+		// saving over an existing target would leave its spans behind, which the history can't explain
+		req := randomAttestation()
+		if existing, _ := store.RetrieveAttestation(key, req.Data.Target.Epoch); existing == nil {
+			require.NoError(t, protector.SaveAttestation(key, req))
+		}
+	}
+}
+
+func TestSpansAcrossLongHistory(t *testing.T) {
+	protector, _, key := setupSpans(t)
+	require.NoError(t, protector.SaveAttestation(key, attestationReq(10, 11, "A")))
+	require.NoError(t, protector.SaveAttestation(key, attestationReq(3000, 3001, "B")))
+	require.NoError(t, protector.SaveAttestation(key, attestationReq(3001, 4000, "C")))
+
+	t.Run("surrounding", func(t *testing.T) {
+		res, err := protector.IsSlashableAttestation(key, attestationReq(5, 3500, "D"))
+		require.NoError(t, err)
+		require.EqualValues(t, []string{"SurroundingVote-11", "SurroundingVote-3001"}, slashStatuses(res))
+	})
+
+	t.Run("surrounded", func(t *testing.T) {
+		res, err := protector.IsSlashableAttestation(key, attestationReq(3500, 3501, "D"))
+		require.NoError(t, err)
+		require.EqualValues(t, []string{"SurroundedVote-4000"}, slashStatuses(res))
+	})
+
+	t.Run("below the lowest source", func(t *testing.T) {
+		res, err := protector.IsSlashableAttestation(key, attestationReq(1, 2, "D"))
+		require.NoError(t, err)
+		require.Len(t, res, 0)
+
+		res, err = protector.IsSlashableAttestation(key, attestationReq(1, 12, "D"))
+		require.NoError(t, err)
+		require.EqualValues(t, []string{"SurroundingVote-11"}, slashStatuses(res))
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		res, err := protector.IsSlashableAttestation(key, attestationReq(4000, 4001, "D"))
+		require.NoError(t, err)
+		require.Len(t, res, 0)
+	})
+}
+
+func TestSpansBuiltFromHistory(t *testing.T) {
+	protector, store, key := setupSpans(t)
+
+	// history saved without spans, as stores did before spans were kept
+	for _, att := range []*core.BeaconAttestation{
+		{Source: &core.Checkpoint{Epoch: 1}, Target: &core.Checkpoint{Epoch: 1000}},
+		{Source: &core.Checkpoint{Epoch: 1000}, Target: &core.Checkpoint{Epoch: 1001}},
+	} {
+		require.NoError(t, store.SaveAttestation(key, att))
+		require.NoError(t, store.SaveLatestAttestation(key, att))
+	}
+	bounds, err := store.RetrieveAttestationSpanBounds(key)
+	require.NoError(t, err)
+	require.Nil(t, bounds)
+
+	res, err := protector.IsSlashableAttestation(key, attestationReq(500, 501, "A"))
+	require.NoError(t, err)
+	require.EqualValues(t, []string{"SurroundedVote-1000"}, slashStatuses(res))
+
+	bounds, err = store.RetrieveAttestationSpanBounds(key)
+	require.NoError(t, err)
+	require.EqualValues(t, &core.SpanBounds{LowestSource: 1, LowestTarget: 1000}, bounds)
+	span, err := store.RetrieveAttestationSpan(key, 500)
+	require.NoError(t, err)
+	require.EqualValues(t, &core.EpochSpan{MinSpan: 501, MaxSpan: 500}, span)
+}
+
+// slashableByLookback is the detection spans replaced, it lists the history from 128 epochs below the source.
+func slashableByLookback(store core.SlashingStore, key e2types.PublicKey, req *pb.SignBeaconAttestationRequest) ([]*core.AttestationSlashStatus, error) {
+	data := core.ToCoreAttestationData(req)
+	start := uint64(0)
+	if data.Source.Epoch > 128 {
+		start = data.Source.Epoch - 128
+	}
+	end := data.Target.Epoch
+	latest, err := store.RetrieveLatestAttestation(key)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil {
+		end = latest.Target.Epoch
+	}
+	history, err := store.ListAttestations(key, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return data.SlashesAttestations(history), nil
+}
+
+func BenchmarkIsSlashableAttestation(b *testing.B) {
+	for _, size := range []uint64{1000, 10000} {
+		protector, store, key := setupSpans(b)
+		for epoch := uint64(1); epoch <= size; epoch++ {
+			require.NoError(b, protector.SaveAttestation(key, attestationReq(epoch-1, epoch, "A")))
+		}
+		req := attestationReq(size, size+1, "B")
+
+		b.Run(fmt.Sprintf("spans/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := protector.IsSlashableAttestation(key, req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("lookback/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := slashableByLookback(store, key, req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
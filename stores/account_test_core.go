@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	eth2keymanager "github.com/bloxapp/eth2-key-manager"
@@ -54,10 +55,15 @@ func TestingFetchingNonExistingAccount(storage core.Storage, t *testing.T) {
 		options.SetSeed(_byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"))
 
 		// fetch non existing account
-		_, err := storage.OpenAccount(uuid.New())
-		if err != nil {
-			t.Error(fmt.Errorf("should not return error for unknwon account, just nil"))
-		}
+		account, err := storage.OpenAccount(uuid.New())
+		require.Nil(t, account)
+		require.EqualError(t, err, "account not found")
+		require.True(t, errors.Is(err, core.ErrNotFound))
+
+		// delete non existing account
+		err = storage.DeleteAccount(uuid.New())
+		require.EqualError(t, err, "account not found")
+		require.True(t, errors.Is(err, core.ErrNotFound))
 	})
 }
 
@@ -0,0 +1,32 @@
+package bolt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bloxapp/eth2-key-manager/stores"
+)
+
+func TestStoringWithEncryption(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingWalletStorageWithEncryption(storage, t)
+}
+
+func TestAccountEncryptionAtRest(t *testing.T) {
+	storage, accounts, err := getPopulatedWalletStorage(t)
+	defer removeStorage(storage)
+	require.NoError(t, err)
+
+	stores.TestingAccountEncryptionAtRest(storage, accounts[0], func() []byte {
+		var ret []byte
+		id := accounts[0].ID()
+		require.NoError(t, storage.db.View(func(tx *bolt.Tx) error {
+			ret = append([]byte{}, tx.Bucket(accountsBucket).Get(id[:])...)
+			return nil
+		}))
+		return ret
+	}, t)
+}
@@ -0,0 +1,297 @@
+package bolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+func (store *BoltStore) SaveAttestation(key e2types.PublicKey, req *core.BeaconAttestation) error {
+	return store.put(attestationsBucket, recordKey(key, req.Target.Epoch), req)
+}
+
+func (store *BoltStore) RetrieveAttestation(key e2types.PublicKey, epoch uint64) (*core.BeaconAttestation, error) {
+	ret := &core.BeaconAttestation{}
+	found, err := store.get(attestationsBucket, recordKey(key, epoch), ret)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, &core.NotFoundError{Kind: "attestation"}
+	}
+	return ret, nil
+}
+
+func (store *BoltStore) ListAttestations(key e2types.PublicKey, epochStart uint64, epochEnd uint64) ([]*core.BeaconAttestation, error) {
+	ret := make([]*core.BeaconAttestation, 0)
+	err := store.scan(attestationsBucket, key, epochStart, epochEnd, func(data []byte) error {
+		att := &core.BeaconAttestation{}
+		if err := json.Unmarshal(data, att); err != nil {
+			return err
+		}
+		ret = append(ret, att)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list attestations")
+	}
+	return ret, nil
+}
+
+func (store *BoltStore) SaveProposal(key e2types.PublicKey, req *core.BeaconBlockHeader) error {
+	return store.put(proposalsBucket, recordKey(key, req.Slot), req)
+}
+
+func (store *BoltStore) RetrieveProposal(key e2types.PublicKey, slot uint64) (*core.BeaconBlockHeader, error) {
+	ret := &core.BeaconBlockHeader{}
+	found, err := store.get(proposalsBucket, recordKey(key, slot), ret)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, &core.NotFoundError{Kind: "proposal"}
+	}
+	return ret, nil
+}
+
+func (store *BoltStore) ListProposals(key e2types.PublicKey, slotStart uint64, slotEnd uint64) ([]*core.BeaconBlockHeader, error) {
+	ret := make([]*core.BeaconBlockHeader, 0)
+	err := store.scan(proposalsBucket, key, slotStart, slotEnd, func(data []byte) error {
+		proposal := &core.BeaconBlockHeader{}
+		if err := json.Unmarshal(data, proposal); err != nil {
+			return err
+		}
+		ret = append(ret, proposal)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list proposals")
+	}
+	return ret, nil
+}
+
+func (store *BoltStore) SaveLatestAttestation(key e2types.PublicKey, req *core.BeaconAttestation) error {
+	return store.put(latestBucket, key.Marshal(), req)
+}
+
+func (store *BoltStore) RetrieveLatestAttestation(key e2types.PublicKey) (*core.BeaconAttestation, error) {
+	ret := &core.BeaconAttestation{}
+	found, err := store.get(latestBucket, key.Marshal(), ret)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return ret, nil
+}
+
+// SaveAttestationSpans saves the spans and bounds in a single transaction so they can't get out of sync.
+func (store *BoltStore) SaveAttestationSpans(key e2types.PublicKey, bounds *core.SpanBounds, spans map[uint64]*core.EpochSpan) error {
+	boundsData, err := json.Marshal(bounds)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal span bounds")
+	}
+	return store.update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(spansBucket)
+		for epoch, span := range spans {
+			data, err := json.Marshal(span)
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal span")
+			}
+			if err := bucket.Put(recordKey(key, epoch), data); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(spanBoundsBucket).Put(key.Marshal(), boundsData)
+	})
+}
+
+func (store *BoltStore) RetrieveAttestationSpanBounds(key e2types.PublicKey) (*core.SpanBounds, error) {
+	ret := &core.SpanBounds{}
+	found, err := store.get(spanBoundsBucket, key.Marshal(), ret)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return ret, nil
+}
+
+func (store *BoltStore) RetrieveAttestationSpan(key e2types.PublicKey, epoch uint64) (*core.EpochSpan, error) {
+	ret := &core.EpochSpan{}
+	found, err := store.get(spansBucket, recordKey(key, epoch), ret)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return ret, nil
+}
+
+func (store *BoltStore) SaveWatermark(key e2types.PublicKey, watermark *core.Watermark) error {
+	return store.put(watermarksBucket, key.Marshal(), watermark)
+}
+
+func (store *BoltStore) RetrieveWatermark(key e2types.PublicKey) (*core.Watermark, error) {
+	ret := &core.Watermark{}
+	found, err := store.get(watermarksBucket, key.Marshal(), ret)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return ret, nil
+}
+
+func (store *BoltStore) ListSlashingKeys() ([]e2types.PublicKey, error) {
+	ids := make(map[string]bool)
+	err := store.view(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{attestationsBucket, proposalsBucket} {
+			err := tx.Bucket(bucket).ForEach(func(k, v []byte) error {
+				ids[string(k[:len(k)-8])] = true
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list slashing keys")
+	}
+
+	ret := make([]e2types.PublicKey, 0)
+	for id := range ids {
+		key, err := e2types.BLSPublicKeyFromBytes([]byte(id))
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, key)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return bytes.Compare(ret[i].Marshal(), ret[j].Marshal()) < 0
+	})
+	return ret, nil
+}
+
+func (store *BoltStore) PruneAttestations(key e2types.PublicKey, targetEpoch uint64, spanEpoch uint64) error {
+	return store.update(func(tx *bolt.Tx) error {
+		if err := deleteRecords(tx.Bucket(attestationsBucket), key, targetEpoch); err != nil {
+			return errors.Wrap(err, "failed to prune attestations")
+		}
+		if err := deleteRecords(tx.Bucket(spansBucket), key, spanEpoch); err != nil {
+			return errors.Wrap(err, "failed to prune attestation spans")
+		}
+		return nil
+	})
+}
+
+func (store *BoltStore) PruneProposals(key e2types.PublicKey, slot uint64) error {
+	return store.update(func(tx *bolt.Tx) error {
+		if err := deleteRecords(tx.Bucket(proposalsBucket), key, slot); err != nil {
+			return errors.Wrap(err, "failed to prune proposals")
+		}
+		return nil
+	})
+}
+
+// deleteRecords deletes the records of the public key below end.
+func deleteRecords(bucket *bolt.Bucket, key e2types.PublicKey, end uint64) error {
+	// keys are collected first, deleting while iterating makes the cursor skip records
+	keys := make([][]byte, 0)
+	last := recordKey(key, end)
+	c := bucket.Cursor()
+	for k, _ := c.Seek(recordKey(key, 0)); k != nil && bytes.Compare(k, last) < 0; k, _ = c.Next() {
+		keys = append(keys, append([]byte{}, k...))
+	}
+	for _, k := range keys {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Atomically runs fn in a single read-write transaction, bolt runs one at a time so it's exclusive for every key.
+func (store *BoltStore) Atomically(key e2types.PublicKey, fn func(store core.SlashingStore) error) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		txStore := *store
+		txStore.tx = tx
+		return fn(&txStore)
+	})
+}
+
+// update runs fn in a read-write transaction, the one of Atomically when called from it.
+func (store *BoltStore) update(fn func(tx *bolt.Tx) error) error {
+	if store.tx != nil {
+		return fn(store.tx)
+	}
+	return store.db.Update(fn)
+}
+
+// view runs fn in a read-only transaction, the one of Atomically when called from it.
+func (store *BoltStore) view(fn func(tx *bolt.Tx) error) error {
+	if store.tx != nil {
+		return fn(store.tx)
+	}
+	return store.db.View(fn)
+}
+
+func (store *BoltStore) put(bucket []byte, key []byte, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal record")
+	}
+	return store.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put(key, data)
+	})
+}
+
+func (store *BoltStore) get(bucket []byte, key []byte, value interface{}) (bool, error) {
+	found := false
+	err := store.view(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get(key)
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, value)
+	})
+	return found, err
+}
+
+// scan calls fn for every record of the public key between start and end (inclusive), in order.
+func (store *BoltStore) scan(bucket []byte, key e2types.PublicKey, start uint64, end uint64, fn func(data []byte) error) error {
+	prefix := key.Marshal()
+	last := recordKey(key, end)
+	return store.view(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		for k, v := c.Seek(recordKey(key, start)); k != nil && bytes.HasPrefix(k, prefix) && bytes.Compare(k, last) <= 0; k, v = c.Next() {
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// recordKey is the public key followed by the big endian epoch/ slot so records of a key are sorted by it
+func recordKey(key e2types.PublicKey, index uint64) []byte {
+	pubKey := key.Marshal()
+	ret := make([]byte, len(pubKey)+8)
+	copy(ret, pubKey)
+	binary.BigEndian.PutUint64(ret[len(pubKey):], index)
+	return ret
+}
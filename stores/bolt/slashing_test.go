@@ -0,0 +1,103 @@
+package bolt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/stores"
+)
+
+func TestSavingProposal(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingSaveProposal(storage, t)
+}
+
+func TestSavingAttestation(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingSaveAttestation(storage, t)
+}
+
+func TestRetrieveMissingRecords(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingRetrieveMissingRecords(storage, t)
+}
+
+func TestSavingLatestAttestation(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingSaveLatestAttestation(storage, t)
+}
+
+func TestRetrieveEmptyLatestAttestation(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingRetrieveEmptyLatestAttestation(storage, t)
+}
+
+func TestListingAttestation(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingListingAttestation(storage, t)
+}
+
+func TestListingProposals(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingListingProposals(storage, t)
+}
+
+func TestSavingAttestationSpans(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingSaveAttestationSpans(storage, t)
+}
+
+func TestSavingWatermark(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingSaveWatermark(storage, t)
+}
+
+func TestPruningHistory(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingPruneHistory(storage, t)
+}
+
+func TestAtomically(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingAtomically(storage, t)
+}
+
+func TestAtomicallyRollback(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	require.NoError(t, e2types.InitBLS())
+	priv, err := e2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+	key := priv.PublicKey()
+
+	err = storage.Atomically(key, func(store core.SlashingStore) error {
+		if err := store.SaveProposal(key, &core.BeaconBlockHeader{Slot: 1}); err != nil {
+			return err
+		}
+		// visible within the transaction
+		proposals, err := store.ListProposals(key, 0, 1)
+		require.NoError(t, err)
+		require.Len(t, proposals, 1)
+		return fmt.Errorf("test")
+	})
+	require.EqualError(t, err, "test")
+
+	proposals, err := storage.ListProposals(key, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, proposals, 0)
+}
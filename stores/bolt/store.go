@@ -0,0 +1,224 @@
+package bolt
+
+import (
+	"encoding/json"
+	"time"
+
+	uuid "github.com/google/uuid"
+	"github.com/pkg/errors"
+	types "github.com/wealdtech/go-eth2-wallet-types/v2"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/stores/codec"
+)
+
+var (
+	metaBucket         = []byte("meta")
+	walletBucket       = []byte("wallet")
+	accountsBucket     = []byte("accounts")
+	attestationsBucket = []byte("attestations")
+	proposalsBucket    = []byte("proposals")
+	latestBucket       = []byte("latest_attestations")
+	spansBucket        = []byte("attestation_spans")
+	spanBoundsBucket   = []byte("attestation_span_bounds")
+	watermarksBucket   = []byte("watermarks")
+
+	networkKey = []byte("network")
+	walletKey  = []byte("wallet")
+)
+
+// how long to wait for the db file lock before giving up, bolt locks the file so only one process can open it.
+const openTimeout = time.Second
+
+// BoltStore implements core.Storage and core.SlashingStore on an embedded bbolt database.
+type BoltStore struct {
+	db                 *bolt.DB
+	network            core.Network
+	encryptor          types.Encryptor
+	encryptionPassword []byte
+	accounts           *codec.AccountCache // decrypted accounts
+	tx                 *bolt.Tx            // set on the store given to Atomically
+}
+
+// NewBoltStore is the constructor of BoltStore.
+// It creates the database file if needed, Close must be called to release it.
+func NewBoltStore(path string, network core.Network) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open db")
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{metaBucket, walletBucket, accountsBucket, attestationsBucket, proposalsBucket, latestBucket, spansBucket, spanBoundsBucket, watermarksBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return errors.Wrapf(err, "failed to create bucket %s", bucket)
+			}
+		}
+
+		// refuse to open a db created for another network
+		meta := tx.Bucket(metaBucket)
+		existing := meta.Get(networkKey)
+		if existing == nil {
+			return meta.Put(networkKey, []byte(network))
+		}
+		if core.Network(existing) != network {
+			return &core.WrongNetworkError{Subject: "store", Expected: core.Network(existing), Actual: network}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{
+		db:       db,
+		network:  network,
+		accounts: codec.NewAccountCache(),
+	}, nil
+}
+
+// Close closes the database, the store can't be used afterwards.
+func (store *BoltStore) Close() error {
+	return store.db.Close()
+}
+
+// Name provides the name of the store.
+func (store *BoltStore) Name() string {
+	return "bolt"
+}
+
+// Network returns the network.
+func (store *BoltStore) Network() core.Network {
+	return store.network
+}
+
+// SaveWallet implements core.Storage interface.
+func (store *BoltStore) SaveWallet(wallet core.Wallet) error {
+	data, err := json.Marshal(wallet)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal wallet")
+	}
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(walletBucket).Put(walletKey, data)
+	})
+}
+
+// will return nil,err if no wallet was found
+func (store *BoltStore) OpenWallet() (core.Wallet, error) {
+	var ret core.Wallet
+	err := store.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(walletBucket).Get(walletKey)
+		if data == nil {
+			return &core.NotFoundError{Kind: "wallet"}
+		}
+		var err error
+		ret, err = codec.DecodeWallet(data)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret.SetContext(store.freshContext())
+	return ret, nil
+}
+
+// will return an empty array for no accounts
+func (store *BoltStore) ListAccounts() ([]core.ValidatorAccount, error) {
+	w, err := store.OpenWallet()
+	if err != nil {
+		return nil, err
+	}
+
+	return w.Accounts(), nil
+}
+
+func (store *BoltStore) SaveAccount(account core.ValidatorAccount) error {
+	data, err := json.Marshal(account)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal account")
+	}
+	plain := data
+	if store.canEncrypt() {
+		if data, err = codec.EncryptAccount(data, store.encryptor, store.encryptionPassword); err != nil {
+			return errors.Wrap(err, "failed to encrypt account")
+		}
+	}
+	id := account.ID()
+	err = store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(accountsBucket).Put(id[:], data)
+	})
+	if err != nil {
+		return err
+	}
+	store.accounts.Set(id, plain)
+	return nil
+}
+
+func (store *BoltStore) DeleteAccount(accountId uuid.UUID) error {
+	store.accounts.Delete(accountId)
+	return store.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(accountsBucket)
+		if bucket.Get(accountId[:]) == nil {
+			return &core.NotFoundError{Kind: "account"}
+		}
+		return bucket.Delete(accountId[:])
+	})
+}
+
+// will return a core.NotFoundError if no account was found
+func (store *BoltStore) OpenAccount(accountId uuid.UUID) (core.ValidatorAccount, error) {
+	if data, ok := store.accounts.Get(accountId); ok {
+		return codec.DecodeAccount(data)
+	}
+
+	var data []byte
+	err := store.db.View(func(tx *bolt.Tx) error {
+		if val := tx.Bucket(accountsBucket).Get(accountId[:]); val != nil {
+			data = append([]byte{}, val...) // only valid during the tx
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, &core.NotFoundError{Kind: "account"}
+	}
+
+	data, encrypted, err := codec.DecryptAccount(data, store.encryptor, store.encryptionPassword)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt account")
+	}
+
+	ret, err := codec.DecodeAccount(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// accounts saved before an encryptor was set are re-saved encrypted
+	if !encrypted && store.canEncrypt() {
+		if err := store.SaveAccount(ret); err != nil {
+			return nil, errors.Wrap(err, "failed to encrypt plaintext account")
+		}
+	}
+	store.accounts.Set(accountId, data)
+	return ret, nil
+}
+
+func (store *BoltStore) SetEncryptor(encryptor types.Encryptor, password []byte) {
+	store.encryptor = encryptor
+	store.encryptionPassword = password
+	store.accounts.Clear()
+}
+
+func (store *BoltStore) canEncrypt() bool {
+	return store.encryptor != nil && store.encryptionPassword != nil
+}
+
+func (store *BoltStore) freshContext() *core.WalletContext {
+	return &core.WalletContext{
+		Storage: store,
+	}
+}
@@ -0,0 +1,102 @@
+package bolt
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+func TestLocking(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+
+	_, err := NewBoltStore(storage.db.Path(), core.MainNetwork)
+	require.Error(t, err)
+}
+
+func TestNetworkMismatch(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	path := storage.db.Path()
+	require.NoError(t, storage.Close())
+
+	_, err := NewBoltStore(path, core.TestNetwork)
+	require.EqualError(t, err, "store was created for network main, not test")
+	var wrongNetwork *core.WrongNetworkError
+	require.True(t, errors.As(err, &wrongNetwork))
+	require.EqualValues(t, core.MainNetwork, wrongNetwork.Expected)
+
+	// reopen so removeStorage has something to close
+	storage, err = NewBoltStore(path, core.MainNetwork)
+	require.NoError(t, err)
+}
+
+func TestReopening(t *testing.T) {
+	storage, accounts, err := getPopulatedWalletStorage(t)
+	require.NoError(t, err)
+	path := storage.db.Path()
+
+	key := accounts[0].ValidatorPublicKey()
+	att := &core.BeaconAttestation{
+		Slot:            30,
+		CommitteeIndex:  1,
+		BeaconBlockRoot: []byte("A"),
+		Source:          &core.Checkpoint{Epoch: 1, Root: []byte("B")},
+		Target:          &core.Checkpoint{Epoch: 2, Root: []byte("C")},
+	}
+	require.NoError(t, storage.SaveAttestation(key, att))
+	require.NoError(t, storage.SaveLatestAttestation(key, att))
+	require.NoError(t, storage.SaveProposal(key, &core.BeaconBlockHeader{Slot: 10, ParentRoot: []byte("A")}))
+	require.NoError(t, storage.Close())
+
+	reopened, err := NewBoltStore(path, core.MainNetwork)
+	require.NoError(t, err)
+	defer removeStorage(reopened)
+
+	wallet, err := reopened.OpenWallet()
+	require.NoError(t, err)
+	require.Len(t, wallet.Accounts(), len(accounts))
+	for _, account := range accounts {
+		fetched, err := wallet.AccountByPublicKey(hex.EncodeToString(account.ValidatorPublicKey().Marshal()))
+		require.NoError(t, err)
+		require.Equal(t, account.ID(), fetched.ID())
+	}
+
+	fetchedAtt, err := reopened.RetrieveAttestation(key, 2)
+	require.NoError(t, err)
+	require.True(t, att.Compare(fetchedAtt))
+	latest, err := reopened.RetrieveLatestAttestation(key)
+	require.NoError(t, err)
+	require.True(t, att.Compare(latest))
+	proposal, err := reopened.RetrieveProposal(key, 10)
+	require.NoError(t, err)
+	require.EqualValues(t, []byte("A"), proposal.ParentRoot)
+}
+
+func TestRangeScanIsolatesKeys(t *testing.T) {
+	storage, accounts, err := getPopulatedWalletStorage(t)
+	defer removeStorage(storage)
+	require.NoError(t, err)
+
+	for i, account := range accounts[:2] {
+		for epoch := uint64(0); epoch < 5; epoch++ {
+			require.NoError(t, storage.SaveAttestation(account.ValidatorPublicKey(), &core.BeaconAttestation{
+				Slot:   uint64(i),
+				Source: &core.Checkpoint{Epoch: epoch},
+				Target: &core.Checkpoint{Epoch: epoch + 1},
+			}))
+		}
+	}
+
+	list, err := storage.ListAttestations(accounts[0].ValidatorPublicKey(), 0, ^uint64(0))
+	require.NoError(t, err)
+	require.Len(t, list, 5)
+	for i, att := range list {
+		require.EqualValues(t, 0, att.Slot)
+		require.EqualValues(t, i+1, att.Target.Epoch)
+	}
+}
@@ -0,0 +1,51 @@
+package bolt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/stores"
+)
+
+func getStorage(t *testing.T) *BoltStore {
+	dir, err := ioutil.TempDir("", "bolt-store")
+	require.NoError(t, err)
+	store, err := NewBoltStore(filepath.Join(dir, "vault.db"), core.MainNetwork)
+	require.NoError(t, err)
+	return store
+}
+
+func removeStorage(store *BoltStore) {
+	path := store.db.Path()
+	store.Close()
+	os.RemoveAll(filepath.Dir(path))
+}
+
+func TestOpeningAccounts(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingOpenAccounts(storage, t)
+}
+
+func TestNonExistingWallet(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingNonExistingWallet(storage, t)
+}
+
+func TestWalletStorage(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingWalletStorage(storage, t)
+}
+
+func TestNDWalletStorage(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingNDWalletStorage(storage, t)
+}
@@ -0,0 +1,50 @@
+package codec
+
+import (
+	"sync"
+
+	uuid "github.com/google/uuid"
+)
+
+// AccountCache keeps marshaled accounts with a plain private key so stores decrypt each account once.
+// Accounts are decoded from it on every open, callers never share an account instance.
+type AccountCache struct {
+	lock     sync.RWMutex
+	accounts map[uuid.UUID][]byte
+}
+
+// NewAccountCache is the constructor of AccountCache.
+func NewAccountCache() *AccountCache {
+	return &AccountCache{
+		accounts: make(map[uuid.UUID][]byte),
+	}
+}
+
+// Get returns the cached account data, false if it's not cached.
+func (cache *AccountCache) Get(id uuid.UUID) ([]byte, bool) {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	data, ok := cache.accounts[id]
+	return data, ok
+}
+
+// Set caches the account data, it must hold a plain private key.
+func (cache *AccountCache) Set(id uuid.UUID, data []byte) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.accounts[id] = data
+}
+
+// Delete removes the account from the cache.
+func (cache *AccountCache) Delete(id uuid.UUID) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	delete(cache.accounts, id)
+}
+
+// Clear removes every account from the cache, e.g. when the encryption password changes.
+func (cache *AccountCache) Clear() {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.accounts = make(map[uuid.UUID][]byte)
+}
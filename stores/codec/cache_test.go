@@ -0,0 +1,30 @@
+package codec
+
+import (
+	"testing"
+
+	uuid "github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountCache(t *testing.T) {
+	cache := NewAccountCache()
+	id := uuid.New()
+
+	_, ok := cache.Get(id)
+	require.False(t, ok)
+
+	cache.Set(id, plainAccount)
+	data, ok := cache.Get(id)
+	require.True(t, ok)
+	require.EqualValues(t, plainAccount, data)
+
+	cache.Delete(id)
+	_, ok = cache.Get(id)
+	require.False(t, ok)
+
+	cache.Set(id, plainAccount)
+	cache.Clear()
+	_, ok = cache.Get(id)
+	require.False(t, ok)
+}
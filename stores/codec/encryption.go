@@ -0,0 +1,111 @@
+package codec
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	types "github.com/wealdtech/go-eth2-wallet-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// Predefined errors
+var (
+	// ErrInvalidPassword is returned when the key material can't be decrypted with the configured password
+	ErrInvalidPassword = &core.DecryptionError{Msg: "invalid password, failed to decrypt key material"}
+	// ErrMissingEncryptor is returned when the key material is encrypted but the store has no encryptor set
+	ErrMissingEncryptor = &core.DecryptionError{Msg: "key material is encrypted but no encryptor was set"}
+)
+
+// Account blobs hold the validation key as {"id": .., "path": .., "privKey": <hex>}, encrypted blobs replace privKey
+// with an EIP-2335 crypto section {"id": .., "path": .., "crypto": {..}}
+const (
+	validationKeyField = "validationKey"
+	privKeyField       = "privKey"
+	cryptoField        = "crypto"
+)
+
+// EncryptAccount replaces the plain private key of a marshaled account with its encrypted form.
+// Already encrypted blobs are returned as is.
+func EncryptAccount(data []byte, encryptor types.Encryptor, password []byte) ([]byte, error) {
+	account, key, err := parseAccount(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, encrypted := key[cryptoField]; encrypted {
+		return data, nil
+	}
+
+	var privKeyHex string
+	if err := json.Unmarshal(key[privKeyField], &privKeyHex); err != nil {
+		return nil, errors.Wrap(err, "failed to parse private key")
+	}
+	privKey, err := hex.DecodeString(privKeyHex)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode private key")
+	}
+
+	crypto, err := encryptor.Encrypt(privKey, string(password))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt private key")
+	}
+	if key[cryptoField], err = json.Marshal(crypto); err != nil {
+		return nil, errors.Wrap(err, "failed to marshal encrypted private key")
+	}
+	delete(key, privKeyField)
+
+	return marshalAccount(account, key)
+}
+
+// DecryptAccount returns the marshaled account with a plain private key, ready to be unmarshaled.
+// encrypted reports if data was encrypted, stores use it to re-save plaintext blobs once an encryptor is set.
+func DecryptAccount(data []byte, encryptor types.Encryptor, password []byte) (ret []byte, encrypted bool, err error) {
+	account, key, err := parseAccount(data)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, encrypted := key[cryptoField]; !encrypted {
+		return data, false, nil
+	}
+	if encryptor == nil || password == nil {
+		return nil, true, ErrMissingEncryptor
+	}
+
+	crypto := make(map[string]interface{})
+	if err := json.Unmarshal(key[cryptoField], &crypto); err != nil {
+		return nil, true, errors.Wrap(err, "failed to parse encrypted private key")
+	}
+	privKey, err := encryptor.Decrypt(crypto, string(password))
+	if err != nil {
+		return nil, true, ErrInvalidPassword
+	}
+
+	if key[privKeyField], err = json.Marshal(hex.EncodeToString(privKey)); err != nil {
+		return nil, true, errors.Wrap(err, "failed to marshal private key")
+	}
+	delete(key, cryptoField)
+
+	ret, err = marshalAccount(account, key)
+	return ret, true, err
+}
+
+func parseAccount(data []byte) (map[string]json.RawMessage, map[string]json.RawMessage, error) {
+	account := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse account")
+	}
+	key := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(account[validationKeyField], &key); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse validation key")
+	}
+	return account, key, nil
+}
+
+func marshalAccount(account map[string]json.RawMessage, key map[string]json.RawMessage) ([]byte, error) {
+	var err error
+	if account[validationKeyField], err = json.Marshal(key); err != nil {
+		return nil, errors.Wrap(err, "failed to marshal validation key")
+	}
+	return json.Marshal(account)
+}
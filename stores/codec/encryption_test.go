@@ -0,0 +1,62 @@
+package codec
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+const privKeyHex = "23fc1d8bb7ab2a7f9fbe7c0d4b1fc2dea2aa8d3bb75d3ed01e1bb8dc3b82a29d"
+
+var plainAccount = []byte(`{"baseAccountPath":"/0","id":"1b2e0e15-a4fb-4b9b-9a5f-1f3c1c1ad1f0","name":"account-0","validationKey":{"id":"56c26af8-0a0b-4e1a-9ef5-23d6e07ec5bd","path":"m/12381/3600/0/0/0","privKey":"` + privKeyHex + `"},"withdrawalPubKey":"aa"}`)
+
+func TestEncryptAccount(t *testing.T) {
+	encrypted, err := EncryptAccount(plainAccount, keystorev4.New(), []byte("password"))
+	require.NoError(t, err)
+	require.False(t, strings.Contains(string(encrypted), privKeyHex))
+	require.False(t, strings.Contains(string(encrypted), privKeyField))
+
+	// other fields are untouched
+	account := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(encrypted, &account))
+	require.EqualValues(t, "account-0", account["name"])
+	require.EqualValues(t, "m/12381/3600/0/0/0", account[validationKeyField].(map[string]interface{})["path"])
+
+	t.Run("already encrypted", func(t *testing.T) {
+		again, err := EncryptAccount(encrypted, keystorev4.New(), []byte("password"))
+		require.NoError(t, err)
+		require.EqualValues(t, encrypted, again)
+	})
+
+	t.Run("decrypt", func(t *testing.T) {
+		decrypted, wasEncrypted, err := DecryptAccount(encrypted, keystorev4.New(), []byte("password"))
+		require.NoError(t, err)
+		require.True(t, wasEncrypted)
+		require.JSONEq(t, string(plainAccount), string(decrypted))
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, _, err := DecryptAccount(encrypted, keystorev4.New(), []byte("other"))
+		require.EqualError(t, err, ErrInvalidPassword.Error())
+	})
+
+	t.Run("missing encryptor", func(t *testing.T) {
+		_, _, err := DecryptAccount(encrypted, nil, nil)
+		require.EqualError(t, err, ErrMissingEncryptor.Error())
+	})
+}
+
+func TestDecryptPlainAccount(t *testing.T) {
+	decrypted, wasEncrypted, err := DecryptAccount(plainAccount, keystorev4.New(), []byte("password"))
+	require.NoError(t, err)
+	require.False(t, wasEncrypted)
+	require.EqualValues(t, plainAccount, decrypted)
+
+	decrypted, wasEncrypted, err = DecryptAccount(plainAccount, nil, nil)
+	require.NoError(t, err)
+	require.False(t, wasEncrypted)
+	require.EqualValues(t, plainAccount, decrypted)
+}
@@ -0,0 +1,60 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/wallet_hd"
+	"github.com/bloxapp/eth2-key-manager/wallet_nd"
+)
+
+type typed struct {
+	Type core.WalletType `json:"type"`
+}
+
+// DecodeWallet unmarshals a wallet by its type field.
+func DecodeWallet(data []byte) (core.Wallet, error) {
+	t := &typed{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal wallet")
+	}
+
+	var ret core.Wallet
+	switch t.Type {
+	case core.HDWallet:
+		ret = &wallet_hd.HDWallet{}
+	case core.ND:
+		ret = &wallet_nd.NDWallet{}
+	default:
+		return nil, fmt.Errorf("unknown wallet type %q", t.Type)
+	}
+	if err := json.Unmarshal(data, ret); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal wallet")
+	}
+	return ret, nil
+}
+
+// DecodeAccount unmarshals an account by its type field, accounts without one are HD accounts.
+func DecodeAccount(data []byte) (core.ValidatorAccount, error) {
+	t := &typed{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal account")
+	}
+
+	var ret core.ValidatorAccount
+	switch t.Type {
+	case "", core.HDWallet:
+		ret = &wallet_hd.HDAccount{}
+	case core.ND:
+		ret = &wallet_nd.NDAccount{}
+	default:
+		return nil, fmt.Errorf("unknown account type %q", t.Type)
+	}
+	if err := json.Unmarshal(data, ret); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal account")
+	}
+	return ret, nil
+}
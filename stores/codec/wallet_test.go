@@ -0,0 +1,31 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/eth2-key-manager/wallet_hd"
+	"github.com/bloxapp/eth2-key-manager/wallet_nd"
+)
+
+func TestDecodeWallet(t *testing.T) {
+	id := uuid.New().String()
+
+	w, err := DecodeWallet([]byte(`{"id":"` + id + `","type":"HD","indexMapper":{}}`))
+	require.NoError(t, err)
+	require.IsType(t, &wallet_hd.HDWallet{}, w)
+
+	w, err = DecodeWallet([]byte(`{"id":"` + id + `","type":"ND","indexMapper":{}}`))
+	require.NoError(t, err)
+	require.IsType(t, &wallet_nd.NDWallet{}, w)
+
+	_, err = DecodeWallet([]byte(`{"id":"` + id + `","type":"other","indexMapper":{}}`))
+	require.EqualError(t, err, `unknown wallet type "other"`)
+}
+
+func TestDecodeAccount(t *testing.T) {
+	_, err := DecodeAccount([]byte(`{"type":"other"}`))
+	require.EqualError(t, err, `unknown account type "other"`)
+}
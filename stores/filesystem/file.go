@@ -0,0 +1,100 @@
+package filesystem
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	dirPerm  os.FileMode = 0700
+	filePerm os.FileMode = 0600
+)
+
+// writeFileAtomically writes the data to a temp file next to the destination and renames it over the destination,
+// a reader will either see the old content or the new one, never a partial write.
+func writeFileAtomically(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return errors.Wrap(err, "failed to create directory")
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temp file")
+	}
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		cleanup()
+		return errors.Wrap(err, "failed to write temp file")
+	}
+	if err := tmp.Sync(); err != nil {
+		cleanup()
+		return errors.Wrap(err, "failed to sync temp file")
+	}
+	if err := tmp.Chmod(filePerm); err != nil {
+		cleanup()
+		return errors.Wrap(err, "failed to set temp file permissions")
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrap(err, "failed to close temp file")
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrap(err, "failed to rename temp file")
+	}
+	return syncDir(dir)
+}
+
+// appendLine appends a single line to the file and syncs it to disk.
+func appendLine(path string, line []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
+		return errors.Wrap(err, "failed to create directory")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, filePerm)
+	if err != nil {
+		return errors.Wrap(err, "failed to open file")
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to append to file")
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to sync file")
+	}
+	return f.Close()
+}
+
+// readFile returns nil, nil if the file doesn't exist
+func readFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// syncDir makes a rename durable, not supported on every platform so errors opening the directory are ignored.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return nil
+	}
+	defer d.Close()
+	d.Sync()
+	return nil
+}
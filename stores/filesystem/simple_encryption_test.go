@@ -0,0 +1,28 @@
+package filesystem
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/eth2-key-manager/stores"
+)
+
+func TestStoringWithEncryption(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingWalletStorageWithEncryption(storage, t)
+}
+
+func TestAccountEncryptionAtRest(t *testing.T) {
+	storage, accounts, err := getPopulatedWalletStorage(t)
+	defer removeStorage(storage)
+	require.NoError(t, err)
+
+	stores.TestingAccountEncryptionAtRest(storage, accounts[0], func() []byte {
+		byts, err := ioutil.ReadFile(storage.accountPath(accounts[0].ID()))
+		require.NoError(t, err)
+		return byts
+	}, t)
+}
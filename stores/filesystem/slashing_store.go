@@ -0,0 +1,475 @@
+package filesystem
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// slashingHistory is the in memory view of a single key's slashing records
+type slashingHistory struct {
+	attestations map[uint64]*core.BeaconAttestation
+	proposals    map[uint64]*core.BeaconBlockHeader
+	latest       *core.BeaconAttestation
+	spans        map[uint64]*core.EpochSpan
+	spanBounds   *core.SpanBounds
+	watermark    *core.Watermark
+}
+
+// spanUpdate is a line of the spans log, spans and bounds are written together so a crash can't separate them
+type spanUpdate struct {
+	Bounds *core.SpanBounds           `json:"bounds"`
+	Spans  map[uint64]*core.EpochSpan `json:"spans"`
+}
+
+func (store *FilesystemStore) SaveAttestation(key e2types.PublicKey, req *core.BeaconAttestation) error {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return err
+	}
+	if err := store.appendRecord(key, attestationsName, req); err != nil {
+		return errors.Wrap(err, "failed to save attestation")
+	}
+	history.attestations[req.Target.Epoch] = req
+	return nil
+}
+
+func (store *FilesystemStore) RetrieveAttestation(key e2types.PublicKey, epoch uint64) (*core.BeaconAttestation, error) {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return nil, err
+	}
+	ret := history.attestations[epoch]
+	if ret == nil {
+		return nil, &core.NotFoundError{Kind: "attestation"}
+	}
+	return ret, nil
+}
+
+func (store *FilesystemStore) ListAttestations(key e2types.PublicKey, epochStart uint64, epochEnd uint64) ([]*core.BeaconAttestation, error) {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*core.BeaconAttestation, 0)
+	for epoch, val := range history.attestations {
+		if epoch >= epochStart && epoch <= epochEnd {
+			ret = append(ret, val)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Target.Epoch < ret[j].Target.Epoch
+	})
+	return ret, nil
+}
+
+func (store *FilesystemStore) SaveProposal(key e2types.PublicKey, req *core.BeaconBlockHeader) error {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return err
+	}
+	if err := store.appendRecord(key, proposalsName, req); err != nil {
+		return errors.Wrap(err, "failed to save proposal")
+	}
+	history.proposals[req.Slot] = req
+	return nil
+}
+
+func (store *FilesystemStore) RetrieveProposal(key e2types.PublicKey, slot uint64) (*core.BeaconBlockHeader, error) {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return nil, err
+	}
+	ret := history.proposals[slot]
+	if ret == nil {
+		return nil, &core.NotFoundError{Kind: "proposal"}
+	}
+	return ret, nil
+}
+
+func (store *FilesystemStore) ListProposals(key e2types.PublicKey, slotStart uint64, slotEnd uint64) ([]*core.BeaconBlockHeader, error) {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*core.BeaconBlockHeader, 0)
+	for slot, val := range history.proposals {
+		if slot >= slotStart && slot <= slotEnd {
+			ret = append(ret, val)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Slot < ret[j].Slot
+	})
+	return ret, nil
+}
+
+func (store *FilesystemStore) SaveLatestAttestation(key e2types.PublicKey, req *core.BeaconAttestation) error {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal latest attestation")
+	}
+	if err := writeFileAtomically(filepath.Join(store.slashingPath(key), latestName), data); err != nil {
+		return errors.Wrap(err, "failed to save latest attestation")
+	}
+	history.latest = req
+	return nil
+}
+
+func (store *FilesystemStore) RetrieveLatestAttestation(key e2types.PublicKey) (*core.BeaconAttestation, error) {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return nil, err
+	}
+	return history.latest, nil
+}
+
+func (store *FilesystemStore) SaveAttestationSpans(key e2types.PublicKey, bounds *core.SpanBounds, spans map[uint64]*core.EpochSpan) error {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return err
+	}
+	if err := store.appendRecord(key, spansName, &spanUpdate{Bounds: bounds, Spans: spans}); err != nil {
+		return errors.Wrap(err, "failed to save attestation spans")
+	}
+	history.applySpans(bounds, spans)
+	return nil
+}
+
+func (store *FilesystemStore) RetrieveAttestationSpanBounds(key e2types.PublicKey) (*core.SpanBounds, error) {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return nil, err
+	}
+	return history.spanBounds, nil
+}
+
+func (store *FilesystemStore) RetrieveAttestationSpan(key e2types.PublicKey, epoch uint64) (*core.EpochSpan, error) {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return nil, err
+	}
+	return history.spans[epoch], nil
+}
+
+func (store *FilesystemStore) SaveWatermark(key e2types.PublicKey, watermark *core.Watermark) error {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(watermark)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal watermark")
+	}
+	if err := writeFileAtomically(filepath.Join(store.slashingPath(key), watermarkName), data); err != nil {
+		return errors.Wrap(err, "failed to save watermark")
+	}
+	history.watermark = watermark
+	return nil
+}
+
+func (store *FilesystemStore) RetrieveWatermark(key e2types.PublicKey) (*core.Watermark, error) {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return nil, err
+	}
+	return history.watermark, nil
+}
+
+func (store *FilesystemStore) ListSlashingKeys() ([]e2types.PublicKey, error) {
+	infos, err := ioutil.ReadDir(filepath.Join(store.path, slashingDirName))
+	if os.IsNotExist(err) {
+		return make([]e2types.PublicKey, 0), nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list slashing keys")
+	}
+
+	ret := make([]e2types.PublicKey, 0)
+	for _, info := range infos {
+		byts, err := hex.DecodeString(info.Name())
+		if !info.IsDir() || err != nil {
+			continue
+		}
+		dir := filepath.Join(store.path, slashingDirName, info.Name())
+		if !fileExists(filepath.Join(dir, attestationsName)) && !fileExists(filepath.Join(dir, proposalsName)) {
+			continue
+		}
+		key, err := e2types.BLSPublicKeyFromBytes(byts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid slashing key %s", info.Name())
+		}
+		ret = append(ret, key)
+	}
+	return ret, nil
+}
+
+// PruneAttestations compacts the attestations and spans logs to what's left after pruning.
+func (store *FilesystemStore) PruneAttestations(key e2types.PublicKey, targetEpoch uint64, spanEpoch uint64) error {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return err
+	}
+
+	attestations := make(map[uint64]*core.BeaconAttestation)
+	records := make([]interface{}, 0)
+	for epoch, att := range history.attestations {
+		if epoch >= targetEpoch {
+			attestations[epoch] = att
+			records = append(records, att)
+		}
+	}
+	spans := make(map[uint64]*core.EpochSpan)
+	for epoch, span := range history.spans {
+		if epoch >= spanEpoch {
+			spans[epoch] = span
+		}
+	}
+	// the remaining spans are written as a single update, a crash leaves either the old log or the compacted one
+	spanRecords := make([]interface{}, 0)
+	if history.spanBounds != nil {
+		spanRecords = append(spanRecords, &spanUpdate{Bounds: history.spanBounds, Spans: spans})
+	}
+
+	if err := store.rewriteRecords(key, attestationsName, records); err != nil {
+		return errors.Wrap(err, "failed to prune attestations")
+	}
+	history.attestations = attestations
+	if err := store.rewriteRecords(key, spansName, spanRecords); err != nil {
+		return errors.Wrap(err, "failed to prune attestation spans")
+	}
+	history.spans = spans
+	return nil
+}
+
+// PruneProposals compacts the proposals log to what's left after pruning.
+func (store *FilesystemStore) PruneProposals(key e2types.PublicKey, slot uint64) error {
+	defer store.lockSlashing()()
+
+	history, err := store.history(key)
+	if err != nil {
+		return err
+	}
+
+	proposals := make(map[uint64]*core.BeaconBlockHeader)
+	records := make([]interface{}, 0)
+	for proposalSlot, proposal := range history.proposals {
+		if proposalSlot >= slot {
+			proposals[proposalSlot] = proposal
+			records = append(records, proposal)
+		}
+	}
+	if err := store.rewriteRecords(key, proposalsName, records); err != nil {
+		return errors.Wrap(err, "failed to prune proposals")
+	}
+	history.proposals = proposals
+	return nil
+}
+
+func (history *slashingHistory) applySpans(bounds *core.SpanBounds, spans map[uint64]*core.EpochSpan) {
+	for epoch, span := range spans {
+		history.spans[epoch] = span
+	}
+	history.spanBounds = bounds
+}
+
+// Atomically holds the slashing lock while fn runs, there are no transactions so what fn writes isn't undone if it
+// fails.
+func (store *FilesystemStore) Atomically(key e2types.PublicKey, fn func(store core.SlashingStore) error) error {
+	defer store.lockSlashing()()
+	return fn(&FilesystemStore{
+		path:               store.path,
+		network:            store.network,
+		lock:               store.lock,
+		encryptor:          store.encryptor,
+		encryptionPassword: store.encryptionPassword,
+		accounts:           store.accounts,
+		slashing:           store.slashing,
+		atomic:             true,
+	})
+}
+
+// lockSlashing locks the slashing history and returns the unlock func, the store given by Atomically already holds it.
+func (store *FilesystemStore) lockSlashing() func() {
+	if store.atomic {
+		return func() {}
+	}
+	store.slashingLock.Lock()
+	return store.slashingLock.Unlock
+}
+
+func (store *FilesystemStore) slashingPath(key e2types.PublicKey) string {
+	return filepath.Join(store.path, slashingDirName, hex.EncodeToString(key.Marshal()))
+}
+
+func (store *FilesystemStore) appendRecord(key e2types.PublicKey, name string, record interface{}) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return appendLine(filepath.Join(store.slashingPath(key), name), line)
+}
+
+// rewriteRecords atomically replaces an append only log with the given records.
+func (store *FilesystemStore) rewriteRecords(key e2types.PublicKey, name string, records []interface{}) error {
+	var data []byte
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	return writeFileAtomically(filepath.Join(store.slashingPath(key), name), data)
+}
+
+// history returns the cached history of the key, loading it from disk on first use.
+// must be called while holding slashingLock
+func (store *FilesystemStore) history(key e2types.PublicKey) (*slashingHistory, error) {
+	id := hex.EncodeToString(key.Marshal())
+	if ret, exists := store.slashing[id]; exists {
+		return ret, nil
+	}
+
+	ret := &slashingHistory{
+		attestations: make(map[uint64]*core.BeaconAttestation),
+		proposals:    make(map[uint64]*core.BeaconBlockHeader),
+		spans:        make(map[uint64]*core.EpochSpan),
+	}
+	dir := store.slashingPath(key)
+
+	// later lines override earlier ones for the same epoch/ slot
+	err := readRecords(filepath.Join(dir, attestationsName), func(line []byte) error {
+		att := &core.BeaconAttestation{}
+		if err := json.Unmarshal(line, att); err != nil {
+			return err
+		}
+		ret.attestations[att.Target.Epoch] = att
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load attestations")
+	}
+
+	err = readRecords(filepath.Join(dir, proposalsName), func(line []byte) error {
+		proposal := &core.BeaconBlockHeader{}
+		if err := json.Unmarshal(line, proposal); err != nil {
+			return err
+		}
+		ret.proposals[proposal.Slot] = proposal
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load proposals")
+	}
+
+	err = readRecords(filepath.Join(dir, spansName), func(line []byte) error {
+		update := &spanUpdate{}
+		if err := json.Unmarshal(line, update); err != nil {
+			return err
+		}
+		ret.applySpans(update.Bounds, update.Spans)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load attestation spans")
+	}
+
+	data, err := readFile(filepath.Join(dir, latestName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load latest attestation")
+	}
+	if data != nil {
+		ret.latest = &core.BeaconAttestation{}
+		if err := json.Unmarshal(data, ret.latest); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal latest attestation")
+		}
+	}
+
+	data, err = readFile(filepath.Join(dir, watermarkName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load watermark")
+	}
+	if data != nil {
+		ret.watermark = &core.Watermark{}
+		if err := json.Unmarshal(data, ret.watermark); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal watermark")
+		}
+	}
+
+	store.slashing[id] = ret
+	return ret, nil
+}
+
+// readRecords calls fn for every line of an append only log.
+func readRecords(path string, fn func(line []byte) error) error {
+	data, err := readFile(path)
+	if err != nil {
+		return err
+	}
+
+	// a crash in the middle of an append can only leave the last line partially written, drop it so the next
+	// append starts on a new line
+	if n := len(data); n > 0 && data[n-1] != '\n' {
+		data = data[:bytes.LastIndexByte(data, '\n')+1]
+		if err := os.Truncate(path, int64(len(data))); err != nil {
+			return err
+		}
+	}
+
+	// span updates rebuilt from a long history can be longer than the default max line
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), len(data)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,73 @@
+package filesystem
+
+import (
+	"testing"
+
+	"github.com/bloxapp/eth2-key-manager/stores"
+)
+
+func TestSavingProposal(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingSaveProposal(storage, t)
+}
+
+func TestSavingAttestation(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingSaveAttestation(storage, t)
+}
+
+func TestRetrieveMissingRecords(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingRetrieveMissingRecords(storage, t)
+}
+
+func TestSavingLatestAttestation(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingSaveLatestAttestation(storage, t)
+}
+
+func TestRetrieveEmptyLatestAttestation(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingRetrieveEmptyLatestAttestation(storage, t)
+}
+
+func TestListingAttestation(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingListingAttestation(storage, t)
+}
+
+func TestListingProposals(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingListingProposals(storage, t)
+}
+
+func TestSavingAttestationSpans(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingSaveAttestationSpans(storage, t)
+}
+
+func TestSavingWatermark(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingSaveWatermark(storage, t)
+}
+
+func TestPruningHistory(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingPruneHistory(storage, t)
+}
+
+func TestAtomically(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingAtomically(storage, t)
+}
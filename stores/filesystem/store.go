@@ -0,0 +1,240 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/flock"
+	uuid "github.com/google/uuid"
+	"github.com/pkg/errors"
+	types "github.com/wealdtech/go-eth2-wallet-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/stores/codec"
+)
+
+// Directory layout:
+//
+//	<path>/.lock                          - held while the store is open
+//	<path>/network                        - the network the store was created for
+//	<path>/wallet.json
+//	<path>/accounts/<account id>.json
+//	<path>/slashing/<pub key>/attestations - append only, one json record per line
+//	<path>/slashing/<pub key>/proposals    - append only, one json record per line
+//	<path>/slashing/<pub key>/spans        - append only, one json span update per line
+//	<path>/slashing/<pub key>/latest.json
+//	<path>/slashing/<pub key>/watermark.json
+const (
+	lockFileName     = ".lock"
+	networkFileName  = "network"
+	walletFileName   = "wallet.json"
+	accountsDirName  = "accounts"
+	slashingDirName  = "slashing"
+	attestationsName = "attestations"
+	proposalsName    = "proposals"
+	spansName        = "spans"
+	latestName       = "latest.json"
+	watermarkName    = "watermark.json"
+)
+
+// FilesystemStore implements core.Storage and core.SlashingStore on a directory tree.
+// The directory is locked while the store is open so two processes can't use the same vault.
+type FilesystemStore struct {
+	path               string
+	network            core.Network
+	lock               *flock.Flock
+	encryptor          types.Encryptor
+	encryptionPassword []byte
+	accounts           *codec.AccountCache // decrypted accounts
+
+	// slashing history is read once per key and kept in memory, safe since no one else can write to the directory.
+	slashingLock sync.Mutex
+	slashing     map[string]*slashingHistory
+	atomic       bool // set on the store given to Atomically, which holds slashingLock
+}
+
+// NewFilesystemStore is the constructor of FilesystemStore.
+// It creates the directory if needed and locks it, Close must be called to release it.
+func NewFilesystemStore(path string, network core.Network) (*FilesystemStore, error) {
+	if err := os.MkdirAll(path, dirPerm); err != nil {
+		return nil, errors.Wrap(err, "failed to create store directory")
+	}
+
+	lock := flock.New(filepath.Join(path, lockFileName))
+	locked, err := lock.TryLock()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to lock store directory")
+	}
+	if !locked {
+		return nil, fmt.Errorf("store directory %s is used by another process", path)
+	}
+
+	store := &FilesystemStore{
+		path:     path,
+		network:  network,
+		lock:     lock,
+		accounts: codec.NewAccountCache(),
+		slashing: make(map[string]*slashingHistory),
+	}
+	if err := store.verifyNetwork(); err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	return store, nil
+}
+
+// Close releases the directory lock, the store can't be used afterwards.
+func (store *FilesystemStore) Close() error {
+	return store.lock.Unlock()
+}
+
+// Name provides the name of the store.
+func (store *FilesystemStore) Name() string {
+	return "filesystem"
+}
+
+// Network returns the network.
+func (store *FilesystemStore) Network() core.Network {
+	return store.network
+}
+
+// SaveWallet implements core.Storage interface.
+func (store *FilesystemStore) SaveWallet(wallet core.Wallet) error {
+	data, err := json.Marshal(wallet)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal wallet")
+	}
+	return writeFileAtomically(filepath.Join(store.path, walletFileName), data)
+}
+
+// will return nil,err if no wallet was found
+func (store *FilesystemStore) OpenWallet() (core.Wallet, error) {
+	data, err := readFile(filepath.Join(store.path, walletFileName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read wallet")
+	}
+	if data == nil {
+		return nil, &core.NotFoundError{Kind: "wallet"}
+	}
+
+	ret, err := codec.DecodeWallet(data)
+	if err != nil {
+		return nil, err
+	}
+	ret.SetContext(store.freshContext())
+	return ret, nil
+}
+
+// will return an empty array for no accounts
+func (store *FilesystemStore) ListAccounts() ([]core.ValidatorAccount, error) {
+	w, err := store.OpenWallet()
+	if err != nil {
+		return nil, err
+	}
+
+	return w.Accounts(), nil
+}
+
+func (store *FilesystemStore) SaveAccount(account core.ValidatorAccount) error {
+	data, err := json.Marshal(account)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal account")
+	}
+	plain := data
+	if store.canEncrypt() {
+		if data, err = codec.EncryptAccount(data, store.encryptor, store.encryptionPassword); err != nil {
+			return errors.Wrap(err, "failed to encrypt account")
+		}
+	}
+	if err := writeFileAtomically(store.accountPath(account.ID()), data); err != nil {
+		return err
+	}
+	store.accounts.Set(account.ID(), plain)
+	return nil
+}
+
+func (store *FilesystemStore) DeleteAccount(accountId uuid.UUID) error {
+	store.accounts.Delete(accountId)
+	err := os.Remove(store.accountPath(accountId))
+	if os.IsNotExist(err) {
+		return &core.NotFoundError{Kind: "account"}
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to delete account")
+	}
+	return syncDir(filepath.Join(store.path, accountsDirName))
+}
+
+// will return a core.NotFoundError if no account was found
+func (store *FilesystemStore) OpenAccount(accountId uuid.UUID) (core.ValidatorAccount, error) {
+	if data, ok := store.accounts.Get(accountId); ok {
+		return codec.DecodeAccount(data)
+	}
+
+	data, err := readFile(store.accountPath(accountId))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read account")
+	}
+	if data == nil {
+		return nil, &core.NotFoundError{Kind: "account"}
+	}
+
+	data, encrypted, err := codec.DecryptAccount(data, store.encryptor, store.encryptionPassword)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt account")
+	}
+
+	ret, err := codec.DecodeAccount(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// accounts saved before an encryptor was set are re-saved encrypted
+	if !encrypted && store.canEncrypt() {
+		if err := store.SaveAccount(ret); err != nil {
+			return nil, errors.Wrap(err, "failed to encrypt plaintext account")
+		}
+	}
+	store.accounts.Set(accountId, data)
+	return ret, nil
+}
+
+func (store *FilesystemStore) SetEncryptor(encryptor types.Encryptor, password []byte) {
+	store.encryptor = encryptor
+	store.encryptionPassword = password
+	store.accounts.Clear()
+}
+
+func (store *FilesystemStore) canEncrypt() bool {
+	return store.encryptor != nil && store.encryptionPassword != nil
+}
+
+func (store *FilesystemStore) freshContext() *core.WalletContext {
+	return &core.WalletContext{
+		Storage: store,
+	}
+}
+
+func (store *FilesystemStore) accountPath(accountId uuid.UUID) string {
+	return filepath.Join(store.path, accountsDirName, accountId.String()+".json")
+}
+
+// verifyNetwork writes the network on first use and refuses to open a directory created for another network.
+func (store *FilesystemStore) verifyNetwork() error {
+	path := filepath.Join(store.path, networkFileName)
+	data, err := readFile(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read network")
+	}
+	if data == nil {
+		return writeFileAtomically(path, []byte(store.network))
+	}
+	if existing := core.Network(strings.TrimSpace(string(data))); existing != store.network {
+		return &core.WrongNetworkError{Subject: "store", Expected: existing, Actual: store.network}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package filesystem
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+func TestLocking(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+
+	_, err := NewFilesystemStore(storage.path, core.MainNetwork)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is used by another process")
+
+	require.NoError(t, storage.Close())
+	reopened, err := NewFilesystemStore(storage.path, core.MainNetwork)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Close())
+}
+
+func TestNetworkMismatch(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	require.NoError(t, storage.Close())
+
+	_, err := NewFilesystemStore(storage.path, core.TestNetwork)
+	require.EqualError(t, err, "store was created for network main, not test")
+	var wrongNetwork *core.WrongNetworkError
+	require.True(t, errors.As(err, &wrongNetwork))
+	require.EqualValues(t, core.MainNetwork, wrongNetwork.Expected)
+}
+
+func TestReopening(t *testing.T) {
+	storage, accounts, err := getPopulatedWalletStorage(t)
+	defer removeStorage(storage)
+	require.NoError(t, err)
+
+	key := accounts[0].ValidatorPublicKey()
+	att := &core.BeaconAttestation{
+		Slot:            30,
+		CommitteeIndex:  1,
+		BeaconBlockRoot: []byte("A"),
+		Source:          &core.Checkpoint{Epoch: 1, Root: []byte("B")},
+		Target:          &core.Checkpoint{Epoch: 2, Root: []byte("C")},
+	}
+	require.NoError(t, storage.SaveAttestation(key, att))
+	require.NoError(t, storage.SaveLatestAttestation(key, att))
+	require.NoError(t, storage.SaveProposal(key, &core.BeaconBlockHeader{Slot: 10, ParentRoot: []byte("A")}))
+	require.NoError(t, storage.SaveAttestationSpans(key, &core.SpanBounds{LowestSource: 1, LowestTarget: 2}, map[uint64]*core.EpochSpan{1: {MaxSpan: 3}}))
+	target, slot := uint64(2), uint64(10)
+	require.NoError(t, storage.SaveWatermark(key, &core.Watermark{MinSourceEpoch: 1, MinTargetEpoch: &target, MinProposalSlot: &slot}))
+	require.NoError(t, storage.Close())
+
+	reopened, err := NewFilesystemStore(storage.path, core.MainNetwork)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	wallet, err := reopened.OpenWallet()
+	require.NoError(t, err)
+	require.Len(t, wallet.Accounts(), len(accounts))
+	for _, account := range accounts {
+		fetched, err := wallet.AccountByPublicKey(hex.EncodeToString(account.ValidatorPublicKey().Marshal()))
+		require.NoError(t, err)
+		require.Equal(t, account.ID(), fetched.ID())
+	}
+
+	fetchedAtt, err := reopened.RetrieveAttestation(key, 2)
+	require.NoError(t, err)
+	require.True(t, att.Compare(fetchedAtt))
+	latest, err := reopened.RetrieveLatestAttestation(key)
+	require.NoError(t, err)
+	require.True(t, att.Compare(latest))
+	proposal, err := reopened.RetrieveProposal(key, 10)
+	require.NoError(t, err)
+	require.EqualValues(t, []byte("A"), proposal.ParentRoot)
+	bounds, err := reopened.RetrieveAttestationSpanBounds(key)
+	require.NoError(t, err)
+	require.EqualValues(t, &core.SpanBounds{LowestSource: 1, LowestTarget: 2}, bounds)
+	span, err := reopened.RetrieveAttestationSpan(key, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, &core.EpochSpan{MaxSpan: 3}, span)
+	watermark, err := reopened.RetrieveWatermark(key)
+	require.NoError(t, err)
+	require.EqualValues(t, &core.Watermark{MinSourceEpoch: 1, MinTargetEpoch: &target, MinProposalSlot: &slot}, watermark)
+}
+
+func TestTornAppend(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	require.NoError(t, e2types.InitBLS())
+
+	sk, err := e2types.BLSPrivateKeyFromBytes(_byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff"))
+	require.NoError(t, err)
+	key := sk.PublicKey()
+
+	require.NoError(t, storage.SaveProposal(key, &core.BeaconBlockHeader{Slot: 10}))
+	require.NoError(t, storage.Close())
+
+	// simulate a crash in the middle of an append
+	f, err := os.OpenFile(filepath.Join(storage.slashingPath(key), proposalsName), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte(`{"slot":11,"propos`))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened, err := NewFilesystemStore(storage.path, core.MainNetwork)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	require.NoError(t, reopened.SaveProposal(key, &core.BeaconBlockHeader{Slot: 12}))
+	list, err := reopened.ListProposals(key, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.EqualValues(t, 10, list[0].Slot)
+	require.EqualValues(t, 12, list[1].Slot)
+}
+
+func TestPruneCompaction(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	require.NoError(t, e2types.InitBLS())
+
+	sk, err := e2types.BLSPrivateKeyFromBytes(_byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff"))
+	require.NoError(t, err)
+	key := sk.PublicKey()
+
+	for slot := uint64(1); slot <= 10; slot++ {
+		require.NoError(t, storage.SaveProposal(key, &core.BeaconBlockHeader{Slot: slot}))
+		// the same slot again only appends
+		require.NoError(t, storage.SaveProposal(key, &core.BeaconBlockHeader{Slot: slot, ParentRoot: []byte("A")}))
+	}
+	require.NoError(t, storage.PruneProposals(key, 8))
+	require.NoError(t, storage.Close())
+
+	data, err := readFile(filepath.Join(storage.slashingPath(key), proposalsName))
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 3)
+
+	reopened, err := NewFilesystemStore(storage.path, core.MainNetwork)
+	require.NoError(t, err)
+	defer reopened.Close()
+	list, err := reopened.ListProposals(key, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	require.EqualValues(t, 8, list[0].Slot)
+	require.EqualValues(t, []byte("A"), list[0].ParentRoot)
+}
@@ -0,0 +1,49 @@
+package filesystem
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/stores"
+)
+
+func getStorage(t *testing.T) *FilesystemStore {
+	dir, err := ioutil.TempDir("", "filesystem-store")
+	require.NoError(t, err)
+	store, err := NewFilesystemStore(dir, core.MainNetwork)
+	require.NoError(t, err)
+	return store
+}
+
+func removeStorage(store *FilesystemStore) {
+	store.Close()
+	os.RemoveAll(store.path)
+}
+
+func TestOpeningAccounts(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingOpenAccounts(storage, t)
+}
+
+func TestNonExistingWallet(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingNonExistingWallet(storage, t)
+}
+
+func TestWalletStorage(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingWalletStorage(storage, t)
+}
+
+func TestNDWalletStorage(t *testing.T) {
+	storage := getStorage(t)
+	defer removeStorage(storage)
+	stores.TestingNDWalletStorage(storage, t)
+}
@@ -6,9 +6,14 @@ import (
 	"fmt"
 
 	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/stores/codec"
 )
 
 func (store *InMemStore) MarshalJSON() ([]byte, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	defer store.lockSlashing()()
+
 	var err error
 	data := make(map[string]interface{})
 
@@ -20,7 +25,7 @@ func (store *InMemStore) MarshalJSON() ([]byte, error) {
 	}
 	data["wallet"] = hex.EncodeToString(data["wallet"].([]byte))
 
-	data["accounts"], err = json.Marshal(store.accounts)
+	data["accounts"], err = store.marshalAccounts()
 	if err != nil {
 		return nil, err
 	}
@@ -38,10 +43,20 @@ func (store *InMemStore) MarshalJSON() ([]byte, error) {
 	}
 	data["proposalMemory"] = hex.EncodeToString(data["proposalMemory"].([]byte))
 
+	data["watermarks"], err = json.Marshal(store.watermarks)
+	if err != nil {
+		return nil, err
+	}
+	data["watermarks"] = hex.EncodeToString(data["watermarks"].([]byte))
+
 	return json.Marshal(data)
 }
 
 func (store *InMemStore) UnmarshalJSON(data []byte) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	defer store.lockSlashing()()
+
 	// parse
 	var v map[string]interface{}
 	if err := json.Unmarshal(data, &v); err != nil {
@@ -55,7 +70,9 @@ func (store *InMemStore) UnmarshalJSON(data []byte) error {
 			return err
 		}
 
-		store.network = core.NetworkFromString(string(byts))
+		if store.network, err = core.NetworkFromString(string(byts)); err != nil {
+			return err
+		}
 	} else {
 		return fmt.Errorf("could not find var: network")
 	}
@@ -66,9 +83,11 @@ func (store *InMemStore) UnmarshalJSON(data []byte) error {
 		if err != nil {
 			return err
 		}
-		err = json.Unmarshal(byts, &store.wallet)
-		if err != nil {
-			return err
+		if string(byts) != "null" {
+			store.wallet, err = codec.DecodeWallet(byts)
+			if err != nil {
+				return err
+			}
 		}
 	} else {
 		return fmt.Errorf("could not find var: wallet")
@@ -80,7 +99,9 @@ func (store *InMemStore) UnmarshalJSON(data []byte) error {
 		if err != nil {
 			return err
 		}
-		err = json.Unmarshal(byts, &store.accounts)
+		// decoded (and decrypted) on first open as the encryptor might not be set yet
+		store.accounts = make(map[string]core.ValidatorAccount)
+		err = json.Unmarshal(byts, &store.rawAccounts)
 		if err != nil {
 			return err
 		}
@@ -101,6 +122,9 @@ func (store *InMemStore) UnmarshalJSON(data []byte) error {
 	} else {
 		return fmt.Errorf("could not find var: attMemory")
 	}
+	// spans aren't marshaled, they are rebuilt from the attestations when first needed
+	store.spanMemory = make(map[string]*core.EpochSpan)
+	store.spanBounds = make(map[string]*core.SpanBounds)
 
 	// proposalMemory
 	if val, exists := v["proposalMemory"]; exists {
@@ -116,5 +140,44 @@ func (store *InMemStore) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("could not find var: proposalMemory")
 	}
 
+	// watermarks, optional as older stores didn't have them
+	store.watermarks = make(map[string]*core.Watermark)
+	if val, exists := v["watermarks"]; exists {
+		byts, err := hex.DecodeString(val.(string))
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(byts, &store.watermarks)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// marshalAccounts encrypts the private keys of all accounts if an encryptor is set
+func (store *InMemStore) marshalAccounts() ([]byte, error) {
+	accounts := make(map[string]json.RawMessage)
+	for id, raw := range store.rawAccounts {
+		accounts[id] = raw
+	}
+	for id, account := range store.accounts {
+		byts, err := json.Marshal(account)
+		if err != nil {
+			return nil, err
+		}
+		accounts[id] = byts
+	}
+
+	if store.canEncrypt() {
+		for id, byts := range accounts {
+			encrypted, err := codec.EncryptAccount(byts, store.encryptor, store.encryptionPassword)
+			if err != nil {
+				return nil, err
+			}
+			accounts[id] = encrypted
+		}
+	}
+	return json.Marshal(accounts)
+}
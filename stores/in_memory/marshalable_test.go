@@ -1,10 +1,14 @@
 package in_memory
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
 
 	"github.com/bloxapp/eth2-key-manager/core"
 	"github.com/bloxapp/eth2-key-manager/wallet_hd"
@@ -82,3 +86,80 @@ func TestMarshaling(t *testing.T) {
 		require.Equal(t, prop.StateRoot, prop2.StateRoot)
 	})
 }
+
+func TestMarshalingWithEncryption(t *testing.T) {
+	store := NewInMemStoreWithEncryptor(core.MainNetwork, keystorev4.New(), []byte("password"))
+	wallet := wallet_hd.NewHDWallet(&core.WalletContext{Storage: store})
+	require.NoError(t, store.SaveWallet(wallet))
+	acc, err := wallet.CreateValidatorAccount(_byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff"), nil)
+	require.NoError(t, err)
+	require.NoError(t, store.SaveAccount(acc))
+
+	byts, err := json.Marshal(store)
+	require.NoError(t, err)
+
+	// the private key is never marshaled in plaintext
+	v := make(map[string]string)
+	require.NoError(t, json.Unmarshal(byts, &v))
+	accounts, err := hex.DecodeString(v["accounts"])
+	require.NoError(t, err)
+	require.False(t, strings.Contains(string(accounts), `"privKey"`))
+
+	t.Run("correct password", func(t *testing.T) {
+		var store2 InMemStore
+		require.NoError(t, json.Unmarshal(byts, &store2))
+		store2.SetEncryptor(keystorev4.New(), []byte("password"))
+		acc2, err := store2.OpenAccount(acc.ID())
+		require.NoError(t, err)
+		require.Equal(t, acc.ValidatorPublicKey().Marshal(), acc2.ValidatorPublicKey().Marshal())
+	})
+	t.Run("wrong password", func(t *testing.T) {
+		var store2 InMemStore
+		require.NoError(t, json.Unmarshal(byts, &store2))
+		store2.SetEncryptor(keystorev4.New(), []byte("wrong"))
+		_, err := store2.OpenAccount(acc.ID())
+		require.EqualError(t, err, "failed to decrypt account: invalid password, failed to decrypt key material")
+	})
+	t.Run("no encryptor", func(t *testing.T) {
+		var store2 InMemStore
+		require.NoError(t, json.Unmarshal(byts, &store2))
+		_, err := store2.OpenAccount(acc.ID())
+		require.EqualError(t, err, "failed to decrypt account: key material is encrypted but no encryptor was set")
+	})
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	store := NewInMemStore(core.MainNetwork)
+	wallet := wallet_hd.NewHDWallet(&core.WalletContext{Storage: store})
+	require.NoError(t, store.SaveWallet(wallet))
+	acc, err := wallet.CreateValidatorAccount(_byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff"), nil)
+	require.NoError(t, err)
+	require.NoError(t, store.SaveAccount(acc))
+	byts, err := json.Marshal(store)
+	require.NoError(t, err)
+
+	// accounts are decoded on first open, while slashing records are read and written
+	var store2 InMemStore
+	require.NoError(t, json.Unmarshal(byts, &store2))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(slot uint64) {
+			defer wg.Done()
+			if _, err := store2.OpenAccount(acc.ID()); err != nil {
+				t.Error(err)
+			}
+			if err := store2.SaveProposal(acc.ValidatorPublicKey(), &core.BeaconBlockHeader{Slot: slot}); err != nil {
+				t.Error(err)
+			}
+			if _, err := store2.ListProposals(acc.ValidatorPublicKey(), 0, 10); err != nil {
+				t.Error(err)
+			}
+		}(uint64(i))
+	}
+	wg.Wait()
+
+	proposals, err := store2.ListProposals(acc.ValidatorPublicKey(), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, proposals, 10)
+}
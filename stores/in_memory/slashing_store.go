@@ -3,6 +3,9 @@ package in_memory
 import (
 	"encoding/hex"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	e2types "github.com/wealdtech/go-eth2-types/v2"
 
@@ -10,50 +13,214 @@ import (
 )
 
 func (store *InMemStore) SaveAttestation(key e2types.PublicKey, req *core.BeaconAttestation) error {
+	defer store.lockSlashing()()
+
 	store.attMemory[attestationKey(key, req.Target.Epoch)] = req
 	return nil
 }
 
 func (store *InMemStore) RetrieveAttestation(key e2types.PublicKey, epoch uint64) (*core.BeaconAttestation, error) {
+	defer store.lockSlashing()()
+
 	ret := store.attMemory[attestationKey(key, epoch)]
 	if ret == nil {
-		return nil, fmt.Errorf("attestation not found")
+		return nil, &core.NotFoundError{Kind: "attestation"}
 	}
 	return ret, nil
 }
 
 func (store *InMemStore) ListAttestations(key e2types.PublicKey, epochStart uint64, epochEnd uint64) ([]*core.BeaconAttestation, error) {
+	defer store.lockSlashing()()
+
+	// ranges can span every epoch (e.g. rebuilding the spans of a loaded store), only the key's records are visited
 	ret := make([]*core.BeaconAttestation, 0)
-	for i := epochStart; i <= epochEnd; i++ {
-		if val, err := store.RetrieveAttestation(key, i); val != nil && err == nil {
+	for k, val := range store.attMemory {
+		if epoch, ok := recordIndex(key, k); ok && epoch >= epochStart && epoch <= epochEnd {
 			ret = append(ret, val)
 		}
 	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Target.Epoch < ret[j].Target.Epoch
+	})
 	return ret, nil
 }
 
 func (store *InMemStore) SaveProposal(key e2types.PublicKey, req *core.BeaconBlockHeader) error {
+	defer store.lockSlashing()()
+
 	store.proposalMemory[proposalKey(key, req.Slot)] = req
 	return nil
 }
 
 func (store *InMemStore) RetrieveProposal(key e2types.PublicKey, slot uint64) (*core.BeaconBlockHeader, error) {
+	defer store.lockSlashing()()
+
 	ret := store.proposalMemory[proposalKey(key, slot)]
 	if ret == nil {
-		return nil, fmt.Errorf("proposal not found")
+		return nil, &core.NotFoundError{Kind: "proposal"}
 	}
 	return ret, nil
 }
 
+func (store *InMemStore) ListProposals(key e2types.PublicKey, slotStart uint64, slotEnd uint64) ([]*core.BeaconBlockHeader, error) {
+	defer store.lockSlashing()()
+
+	prefix := hex.EncodeToString(key.Marshal()) + "_"
+	ret := make([]*core.BeaconBlockHeader, 0)
+	for k, val := range store.proposalMemory {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		if val.Slot >= slotStart && val.Slot <= slotEnd {
+			ret = append(ret, val)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Slot < ret[j].Slot
+	})
+	return ret, nil
+}
+
 func (store *InMemStore) SaveLatestAttestation(key e2types.PublicKey, req *core.BeaconAttestation) error {
+	defer store.lockSlashing()()
+
 	store.attMemory[hex.EncodeToString(key.Marshal())+"_latest"] = req
 	return nil
 }
 
 func (store *InMemStore) RetrieveLatestAttestation(key e2types.PublicKey) (*core.BeaconAttestation, error) {
+	defer store.lockSlashing()()
+
 	return store.attMemory[hex.EncodeToString(key.Marshal())+"_latest"], nil
 }
 
+func (store *InMemStore) SaveAttestationSpans(key e2types.PublicKey, bounds *core.SpanBounds, spans map[uint64]*core.EpochSpan) error {
+	defer store.lockSlashing()()
+
+	for epoch, span := range spans {
+		store.spanMemory[attestationKey(key, epoch)] = span
+	}
+	store.spanBounds[hex.EncodeToString(key.Marshal())] = bounds
+	return nil
+}
+
+func (store *InMemStore) RetrieveAttestationSpanBounds(key e2types.PublicKey) (*core.SpanBounds, error) {
+	defer store.lockSlashing()()
+
+	return store.spanBounds[hex.EncodeToString(key.Marshal())], nil
+}
+
+func (store *InMemStore) RetrieveAttestationSpan(key e2types.PublicKey, epoch uint64) (*core.EpochSpan, error) {
+	defer store.lockSlashing()()
+
+	return store.spanMemory[attestationKey(key, epoch)], nil
+}
+
+func (store *InMemStore) SaveWatermark(key e2types.PublicKey, watermark *core.Watermark) error {
+	defer store.lockSlashing()()
+
+	store.watermarks[hex.EncodeToString(key.Marshal())] = watermark
+	return nil
+}
+
+func (store *InMemStore) RetrieveWatermark(key e2types.PublicKey) (*core.Watermark, error) {
+	defer store.lockSlashing()()
+
+	return store.watermarks[hex.EncodeToString(key.Marshal())], nil
+}
+
+func (store *InMemStore) ListSlashingKeys() ([]e2types.PublicKey, error) {
+	defer store.lockSlashing()()
+
+	ids := make(map[string]bool)
+	for k := range store.attMemory {
+		ids[k[:strings.Index(k, "_")]] = true
+	}
+	for k := range store.proposalMemory {
+		ids[k[:strings.Index(k, "_")]] = true
+	}
+
+	ret := make([]e2types.PublicKey, 0)
+	for id := range ids {
+		byts, err := hex.DecodeString(id)
+		if err != nil {
+			return nil, err
+		}
+		key, err := e2types.BLSPublicKeyFromBytes(byts)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, key)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return hex.EncodeToString(ret[i].Marshal()) < hex.EncodeToString(ret[j].Marshal())
+	})
+	return ret, nil
+}
+
+func (store *InMemStore) PruneAttestations(key e2types.PublicKey, targetEpoch uint64, spanEpoch uint64) error {
+	defer store.lockSlashing()()
+
+	for k := range store.attMemory {
+		if epoch, ok := recordIndex(key, k); ok && epoch < targetEpoch {
+			delete(store.attMemory, k)
+		}
+	}
+	for k := range store.spanMemory {
+		if epoch, ok := recordIndex(key, k); ok && epoch < spanEpoch {
+			delete(store.spanMemory, k)
+		}
+	}
+	return nil
+}
+
+func (store *InMemStore) PruneProposals(key e2types.PublicKey, slot uint64) error {
+	defer store.lockSlashing()()
+
+	for k := range store.proposalMemory {
+		if recordSlot, ok := recordIndex(key, k); ok && recordSlot < slot {
+			delete(store.proposalMemory, k)
+		}
+	}
+	return nil
+}
+
+// Atomically holds the slashing lock while fn runs, what fn writes isn't undone if it fails.
+func (store *InMemStore) Atomically(key e2types.PublicKey, fn func(store core.SlashingStore) error) error {
+	defer store.lockSlashing()()
+	return fn(&InMemStore{
+		network:        store.network,
+		attMemory:      store.attMemory,
+		proposalMemory: store.proposalMemory,
+		spanMemory:     store.spanMemory,
+		spanBounds:     store.spanBounds,
+		watermarks:     store.watermarks,
+		atomic:         true,
+	})
+}
+
+// lockSlashing locks the slashing records and returns the unlock func, the store given by Atomically already holds it.
+func (store *InMemStore) lockSlashing() func() {
+	if store.atomic {
+		return func() {}
+	}
+	store.slashingLock.Lock()
+	return store.slashingLock.Unlock
+}
+
+// recordIndex returns the epoch/ slot of a memory key if it's one of the public key's records
+func recordIndex(key e2types.PublicKey, memoryKey string) (uint64, bool) {
+	prefix := hex.EncodeToString(key.Marshal()) + "_"
+	if !strings.HasPrefix(memoryKey, prefix) {
+		return 0, false
+	}
+	ret, err := strconv.ParseUint(strings.TrimPrefix(memoryKey, prefix), 10, 64)
+	if err != nil {
+		return 0, false // the latest attestation
+	}
+	return ret, true
+}
+
 func attestationKey(key e2types.PublicKey, targetEpoch uint64) string {
 	return fmt.Sprintf("%s_%d", hex.EncodeToString(key.Marshal()), targetEpoch)
 }
@@ -19,6 +19,10 @@ func TestSavingAttestation(t *testing.T) {
 	stores.TestingSaveAttestation(getSlashingStorage(), t)
 }
 
+func TestRetrieveMissingRecords(t *testing.T) {
+	stores.TestingRetrieveMissingRecords(getSlashingStorage(), t)
+}
+
 func TestSavingLatestAttestation(t *testing.T) {
 	stores.TestingSaveLatestAttestation(getSlashingStorage(), t)
 }
@@ -30,3 +34,23 @@ func TestRetrieveEmptyLatestAttestation(t *testing.T) {
 func TestListingAttestation(t *testing.T) {
 	stores.TestingListingAttestation(getSlashingStorage(), t)
 }
+
+func TestListingProposals(t *testing.T) {
+	stores.TestingListingProposals(getSlashingStorage(), t)
+}
+
+func TestSavingAttestationSpans(t *testing.T) {
+	stores.TestingSaveAttestationSpans(getSlashingStorage(), t)
+}
+
+func TestSavingWatermark(t *testing.T) {
+	stores.TestingSaveWatermark(getSlashingStorage(), t)
+}
+
+func TestPruningHistory(t *testing.T) {
+	stores.TestingPruneHistory(getSlashingStorage(), t)
+}
+
+func TestAtomically(t *testing.T) {
+	stores.TestingAtomically(getSlashingStorage(), t)
+}
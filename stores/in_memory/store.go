@@ -1,24 +1,35 @@
 package in_memory
 
 import (
-	"fmt"
+	"encoding/json"
+	"sync"
 
 	uuid "github.com/google/uuid"
+	"github.com/pkg/errors"
 	types "github.com/wealdtech/go-eth2-wallet-types/v2"
 
 	"github.com/bloxapp/eth2-key-manager/core"
-	"github.com/bloxapp/eth2-key-manager/wallet_hd"
+	"github.com/bloxapp/eth2-key-manager/stores/codec"
 )
 
 // InMemStore implements core.Storage using in-memory store.
 type InMemStore struct {
 	network            core.Network
-	wallet             *wallet_hd.HDWallet
-	accounts           map[string]*wallet_hd.HDAccount
+	wallet             core.Wallet
+	accounts           map[string]core.ValidatorAccount
+	rawAccounts        map[string]json.RawMessage // loaded by UnmarshalJSON, possibly encrypted, decoded on first open
 	attMemory          map[string]*core.BeaconAttestation
 	proposalMemory     map[string]*core.BeaconBlockHeader
+	spanMemory         map[string]*core.EpochSpan
+	spanBounds         map[string]*core.SpanBounds
+	watermarks         map[string]*core.Watermark
 	encryptor          types.Encryptor
 	encryptionPassword []byte
+
+	// lock guards the wallet, the accounts and the encryptor, slashingLock the slashing records.
+	lock         sync.Mutex
+	slashingLock sync.Mutex
+	atomic       bool // set on the store given to Atomically, which holds slashingLock
 }
 
 // NewInMemStore is the constructor of InMemStore.
@@ -30,9 +41,13 @@ func NewInMemStore(network core.Network) *InMemStore {
 func NewInMemStoreWithEncryptor(network core.Network, encryptor types.Encryptor, password []byte) *InMemStore {
 	return &InMemStore{
 		network:            network,
-		accounts:           make(map[string]*wallet_hd.HDAccount),
+		accounts:           make(map[string]core.ValidatorAccount),
+		rawAccounts:        make(map[string]json.RawMessage),
 		attMemory:          make(map[string]*core.BeaconAttestation),
 		proposalMemory:     make(map[string]*core.BeaconBlockHeader),
+		spanMemory:         make(map[string]*core.EpochSpan),
+		spanBounds:         make(map[string]*core.SpanBounds),
+		watermarks:         make(map[string]*core.Watermark),
 		encryptor:          encryptor,
 		encryptionPassword: password,
 	}
@@ -50,17 +65,21 @@ func (store *InMemStore) Network() core.Network {
 
 // SaveWallet implements core.Storage interface.
 func (store *InMemStore) SaveWallet(wallet core.Wallet) error {
-	store.wallet = wallet.(*wallet_hd.HDWallet)
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.wallet = wallet
 	return nil
 }
 
-// will return nil,nil if no wallet was found
+// will return a core.NotFoundError if no wallet was found
 func (store *InMemStore) OpenWallet() (core.Wallet, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
 	if store.wallet != nil {
 		store.wallet.SetContext(store.freshContext())
 		return store.wallet, nil
 	}
-	return nil, fmt.Errorf("wallet not found")
+	return nil, &core.NotFoundError{Kind: "wallet"}
 }
 
 // will return an empty array for no accounts
@@ -74,29 +93,55 @@ func (store *InMemStore) ListAccounts() ([]core.ValidatorAccount, error) {
 }
 
 func (store *InMemStore) SaveAccount(account core.ValidatorAccount) error {
-	store.accounts[account.ID().String()] = account.(*wallet_hd.HDAccount)
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.accounts[account.ID().String()] = account
+	delete(store.rawAccounts, account.ID().String())
 	return nil
 }
 
 func (store *InMemStore) DeleteAccount(accountId uuid.UUID) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
 	_, exists := store.accounts[accountId.String()]
-	if !exists {
-		return fmt.Errorf("account not found")
+	_, rawExists := store.rawAccounts[accountId.String()]
+	if !exists && !rawExists {
+		return &core.NotFoundError{Kind: "account"}
 	}
 	delete(store.accounts, accountId.String())
+	delete(store.rawAccounts, accountId.String())
 	return nil
 }
 
-// will return nil,nil if no account was found
+// will return a core.NotFoundError if no account was found
 func (store *InMemStore) OpenAccount(accountId uuid.UUID) (core.ValidatorAccount, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
 	if val := store.accounts[accountId.String()]; val != nil {
 		return val, nil
-	} else {
-		return nil, nil
 	}
+
+	raw, exists := store.rawAccounts[accountId.String()]
+	if !exists {
+		return nil, &core.NotFoundError{Kind: "account"}
+	}
+	data, _, err := codec.DecryptAccount(raw, store.encryptor, store.encryptionPassword)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt account")
+	}
+	ret, err := codec.DecodeAccount(data)
+	if err != nil {
+		return nil, err
+	}
+	store.accounts[accountId.String()] = ret
+	delete(store.rawAccounts, accountId.String())
+	return ret, nil
 }
 
 func (store *InMemStore) SetEncryptor(encryptor types.Encryptor, password []byte) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
 	store.encryptor = encryptor
 	store.encryptionPassword = password
 }
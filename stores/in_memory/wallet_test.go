@@ -22,3 +22,7 @@ func TestNonExistingWallet(t *testing.T) {
 func TestWalletStorage(t *testing.T) {
 	stores.TestingWalletStorage(getStorage(), t)
 }
+
+func TestNDWalletStorage(t *testing.T) {
+	stores.TestingNDWalletStorage(getStorage(), t)
+}
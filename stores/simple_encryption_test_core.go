@@ -2,8 +2,10 @@ package stores
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
 	types "github.com/wealdtech/go-eth2-wallet-types/v2"
@@ -16,6 +18,17 @@ func encryptor() types.Encryptor {
 	return keystorev4.New()
 }
 
+// countingEncryptor counts decryptions, they're slow on purpose so stores must not repeat them
+type countingEncryptor struct {
+	types.Encryptor
+	decrypts int
+}
+
+func (encryptor *countingEncryptor) Decrypt(input map[string]interface{}, passphrase string) ([]byte, error) {
+	encryptor.decrypts++
+	return encryptor.Encryptor.Decrypt(input, passphrase)
+}
+
 func TestingWalletStorageWithEncryption(storage core.Storage, t *testing.T) {
 	tests := []struct {
 		testName string
@@ -64,3 +77,59 @@ func TestingWalletStorageWithEncryption(storage core.Storage, t *testing.T) {
 		})
 	}
 }
+
+// TestingAccountEncryptionAtRest saves the account in plaintext, then verifies it gets encrypted once an encryptor is set.
+// raw returns the account as persisted by the store.
+func TestingAccountEncryptionAtRest(storage core.Storage, account core.ValidatorAccount, raw func() []byte, t *testing.T) {
+	isPlain := func() bool {
+		return strings.Contains(string(raw()), `"privKey"`)
+	}
+
+	storage.SetEncryptor(nil, nil)
+	require.NoError(t, storage.SaveAccount(account))
+	require.True(t, isPlain())
+
+	t.Run("plaintext migration", func(t *testing.T) {
+		storage.SetEncryptor(encryptor(), []byte("password"))
+		a1, err := storage.OpenAccount(account.ID())
+		require.NoError(t, err)
+		require.Equal(t, account.ValidatorPublicKey().Marshal(), a1.ValidatorPublicKey().Marshal())
+		require.False(t, isPlain())
+	})
+
+	t.Run("saving encrypts", func(t *testing.T) {
+		storage.SetEncryptor(encryptor(), []byte("password"))
+		require.NoError(t, storage.SaveAccount(account))
+		require.False(t, isPlain())
+
+		a1, err := storage.OpenAccount(account.ID())
+		require.NoError(t, err)
+		require.Equal(t, account.ValidatorPublicKey().Marshal(), a1.ValidatorPublicKey().Marshal())
+		require.Equal(t, account.WithdrawalPublicKey().Marshal(), a1.WithdrawalPublicKey().Marshal())
+	})
+
+	t.Run("decrypts once", func(t *testing.T) {
+		counter := &countingEncryptor{Encryptor: encryptor()}
+		storage.SetEncryptor(counter, []byte("password"))
+		for i := 0; i < 3; i++ {
+			a1, err := storage.OpenAccount(account.ID())
+			require.NoError(t, err)
+			require.Equal(t, account.ValidatorPublicKey().Marshal(), a1.ValidatorPublicKey().Marshal())
+		}
+		require.EqualValues(t, 1, counter.decrypts)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		storage.SetEncryptor(encryptor(), []byte("wrong"))
+		_, err := storage.OpenAccount(account.ID())
+		require.EqualError(t, err, "failed to decrypt account: invalid password, failed to decrypt key material")
+		require.True(t, errors.Is(err, core.ErrDecryptionFailed))
+	})
+
+	t.Run("no encryptor", func(t *testing.T) {
+		storage.SetEncryptor(nil, nil)
+		_, err := storage.OpenAccount(account.ID())
+		require.EqualError(t, err, "failed to decrypt account: key material is encrypted but no encryptor was set")
+		require.True(t, errors.Is(err, core.ErrDecryptionFailed))
+	})
+}
@@ -1,10 +1,14 @@
 package stores
 
 import (
+	"fmt"
+	"math"
 	"math/big"
+	"sync"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	e2types "github.com/wealdtech/go-eth2-types/v2"
 
@@ -30,9 +34,16 @@ func (a *mockAccount) ValidatorPublicKey() e2types.PublicKey {
 }
 func (a *mockAccount) WithdrawalPublicKey() e2types.PublicKey                   { return nil }
 func (a *mockAccount) ValidationKeySign(data []byte) (e2types.Signature, error) { return nil, nil }
-func (a *mockAccount) WithdrawalKeySign(data []byte) (e2types.Signature, error) { return nil, nil }
-func (a *mockAccount) GetDepositData() (map[string]interface{}, error)          { return nil, nil }
-func (a *mockAccount) SetContext(ctx *core.WalletContext)                       {}
+func (a *mockAccount) WithdrawalKeySign(seed []byte, data []byte) (e2types.Signature, error) {
+	return nil, nil
+}
+func (a *mockAccount) GetDepositData(executionAddress []byte, amountInGwei uint64) (map[string]interface{}, error) {
+	return nil, nil
+}
+func (a *mockAccount) ExportKeystore(password string, kdf string) (map[string]interface{}, error) {
+	return nil, nil
+}
+func (a *mockAccount) SetContext(ctx *core.WalletContext) {}
 
 func TestingSaveProposal(storage core.SlashingStore, t *testing.T) {
 	tests := []struct {
@@ -172,6 +183,24 @@ func TestingRetrieveEmptyLatestAttestation(storage core.SlashingStore, t *testin
 	}
 }
 
+func TestingRetrieveMissingRecords(storage core.SlashingStore, t *testing.T) {
+	account := &mockAccount{
+		id:            uuid.New(),
+		validationKey: _bigInt("5467048590701165350380985526996487573957450279098876378395441669247373404218"),
+	}
+
+	att, err := storage.RetrieveAttestation(account.ValidatorPublicKey(), 1)
+	require.Nil(t, att)
+	require.EqualError(t, err, "attestation not found")
+	require.True(t, errors.Is(err, core.ErrNotFound))
+
+	proposal, err := storage.RetrieveProposal(account.ValidatorPublicKey(), 1)
+	require.Nil(t, proposal)
+	require.EqualError(t, err, "proposal not found")
+	require.True(t, errors.Is(err, core.ErrNotFound))
+	require.False(t, errors.Is(err, &core.NotFoundError{Kind: "attestation"}))
+}
+
 func TestingSaveLatestAttestation(storage core.SlashingStore, t *testing.T) {
 	tests := []struct {
 		name    string
@@ -339,6 +368,12 @@ func TestingListingAttestation(storage core.SlashingStore, t *testing.T) {
 			end:         10,
 			expectedCnt: 3,
 		},
+		{
+			name:        "every epoch",
+			start:       0,
+			end:         math.MaxUint64,
+			expectedCnt: 3,
+		},
 	}
 
 	for _, test := range tests {
@@ -368,3 +403,277 @@ func TestingListingAttestation(storage core.SlashingStore, t *testing.T) {
 		})
 	}
 }
+
+func TestingListingProposals(storage core.SlashingStore, t *testing.T) {
+	proposals := []*core.BeaconBlockHeader{
+		&core.BeaconBlockHeader{
+			Slot:          10,
+			ProposerIndex: 1,
+			ParentRoot:    []byte("A"),
+			StateRoot:     []byte("A"),
+			BodyRoot:      []byte("A"),
+		},
+		&core.BeaconBlockHeader{
+			Slot:          12,
+			ProposerIndex: 1,
+			ParentRoot:    []byte("B"),
+			StateRoot:     []byte("B"),
+			BodyRoot:      []byte("B"),
+		},
+		&core.BeaconBlockHeader{
+			Slot:          40,
+			ProposerIndex: 1,
+			ParentRoot:    []byte("C"),
+			StateRoot:     []byte("C"),
+			BodyRoot:      []byte("C"),
+		},
+	}
+	account := &mockAccount{
+		id:            uuid.New(),
+		validationKey: _bigInt("5467048590701165350380985526996487573957450279098876378395441669247373404218"),
+	}
+
+	// save
+	for _, proposal := range proposals {
+		err := storage.SaveProposal(account.ValidatorPublicKey(), proposal)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	tests := []struct {
+		name        string
+		start       uint64
+		end         uint64
+		expectedCnt int
+	}{
+		{
+			name:        "empty list 1",
+			start:       0,
+			end:         9,
+			expectedCnt: 0,
+		},
+		{
+			name:        "empty list 2",
+			start:       1000,
+			end:         10010,
+			expectedCnt: 0,
+		},
+		{
+			name:        "simple list 1",
+			start:       10,
+			end:         11,
+			expectedCnt: 1,
+		},
+		{
+			name:        "simple list 2",
+			start:       10,
+			end:         12,
+			expectedCnt: 2,
+		},
+		{
+			name:        "all",
+			start:       0,
+			end:         100,
+			expectedCnt: 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// list
+			list, err := storage.ListProposals(account.ValidatorPublicKey(), test.start, test.end)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if list == nil {
+				t.Errorf("list proposals returns nil")
+				return
+			}
+			if len(list) != test.expectedCnt {
+				t.Errorf("list proposals returns %d elements, expectd: %d", len(list), test.expectedCnt)
+				return
+			}
+
+			// iterate all and compare
+			for _, proposal := range list {
+				if proposal.Slot > test.end || proposal.Slot < test.start {
+					t.Errorf("list proposals returned an element outside what was requested. start: %d end:%d, returned: %d", test.start, test.end, proposal.Slot)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestingSaveAttestationSpans(storage core.SlashingStore, t *testing.T) {
+	account := &mockAccount{
+		id:            uuid.New(),
+		validationKey: _bigInt("5467048590701165350380985526996487573957450279098876378395441669247373404218"),
+	}
+	key := account.ValidatorPublicKey()
+
+	// nothing saved
+	bounds, err := storage.RetrieveAttestationSpanBounds(key)
+	require.NoError(t, err)
+	require.Nil(t, bounds)
+	span, err := storage.RetrieveAttestationSpan(key, 2)
+	require.NoError(t, err)
+	require.Nil(t, span)
+
+	require.NoError(t, storage.SaveAttestationSpans(key, &core.SpanBounds{LowestSource: 1, LowestTarget: 4}, map[uint64]*core.EpochSpan{
+		2: {MinSpan: 0, MaxSpan: 2},
+		3: {MinSpan: 5, MaxSpan: 1},
+	}))
+	// later saves replace the given epochs only
+	require.NoError(t, storage.SaveAttestationSpans(key, &core.SpanBounds{LowestSource: 1, LowestTarget: 3}, map[uint64]*core.EpochSpan{
+		3: {MinSpan: 2, MaxSpan: 1},
+	}))
+
+	bounds, err = storage.RetrieveAttestationSpanBounds(key)
+	require.NoError(t, err)
+	require.EqualValues(t, &core.SpanBounds{LowestSource: 1, LowestTarget: 3}, bounds)
+	span, err = storage.RetrieveAttestationSpan(key, 2)
+	require.NoError(t, err)
+	require.EqualValues(t, &core.EpochSpan{MinSpan: 0, MaxSpan: 2}, span)
+	span, err = storage.RetrieveAttestationSpan(key, 3)
+	require.NoError(t, err)
+	require.EqualValues(t, &core.EpochSpan{MinSpan: 2, MaxSpan: 1}, span)
+	span, err = storage.RetrieveAttestationSpan(key, 4)
+	require.NoError(t, err)
+	require.Nil(t, span)
+}
+
+func TestingSaveWatermark(storage core.SlashingStore, t *testing.T) {
+	account := &mockAccount{
+		id:            uuid.New(),
+		validationKey: _bigInt("5467048590701165350380985526996487573957450279098876378395441669247373404218"),
+	}
+	key := account.ValidatorPublicKey()
+
+	watermark, err := storage.RetrieveWatermark(key)
+	require.NoError(t, err)
+	require.Nil(t, watermark)
+
+	require.NoError(t, storage.SaveWatermark(key, &core.Watermark{MinSourceEpoch: 1, MinTargetEpoch: uint64Ptr(2), MinProposalSlot: uint64Ptr(3)}))
+	require.NoError(t, storage.SaveWatermark(key, &core.Watermark{MinSourceEpoch: 4, MinTargetEpoch: uint64Ptr(5), MinProposalSlot: uint64Ptr(6)}))
+	watermark, err = storage.RetrieveWatermark(key)
+	require.NoError(t, err)
+	require.EqualValues(t, &core.Watermark{MinSourceEpoch: 4, MinTargetEpoch: uint64Ptr(5), MinProposalSlot: uint64Ptr(6)}, watermark)
+
+	t.Run("zero is kept apart from unset", func(t *testing.T) {
+		require.NoError(t, storage.SaveWatermark(key, &core.Watermark{MinTargetEpoch: uint64Ptr(0)}))
+		watermark, err := storage.RetrieveWatermark(key)
+		require.NoError(t, err)
+		require.EqualValues(t, &core.Watermark{MinTargetEpoch: uint64Ptr(0)}, watermark)
+	})
+}
+
+func uint64Ptr(val uint64) *uint64 {
+	return &val
+}
+
+func TestingPruneHistory(storage core.SlashingStore, t *testing.T) {
+	account := &mockAccount{
+		id:            uuid.New(),
+		validationKey: _bigInt("5467048590701165350380985526996487573957450279098876378395441669247373404218"),
+	}
+	key := account.ValidatorPublicKey()
+
+	keys, err := storage.ListSlashingKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 0)
+
+	latest := &core.BeaconAttestation{Source: &core.Checkpoint{Epoch: 9}, Target: &core.Checkpoint{Epoch: 10}}
+	for epoch := uint64(1); epoch <= 10; epoch++ {
+		require.NoError(t, storage.SaveAttestation(key, &core.BeaconAttestation{Source: &core.Checkpoint{Epoch: epoch - 1}, Target: &core.Checkpoint{Epoch: epoch}}))
+		require.NoError(t, storage.SaveProposal(key, &core.BeaconBlockHeader{Slot: epoch * 10}))
+	}
+	require.NoError(t, storage.SaveLatestAttestation(key, latest))
+	spans := make(map[uint64]*core.EpochSpan)
+	for epoch := uint64(0); epoch < 10; epoch++ {
+		spans[epoch] = &core.EpochSpan{MinSpan: 1}
+	}
+	bounds := &core.SpanBounds{LowestSource: 0, LowestTarget: 1}
+	require.NoError(t, storage.SaveAttestationSpans(key, bounds, spans))
+
+	keys, err = storage.ListSlashingKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.EqualValues(t, key.Marshal(), keys[0].Marshal())
+
+	require.NoError(t, storage.PruneAttestations(key, 6, 4))
+	require.NoError(t, storage.PruneProposals(key, 75))
+
+	atts, err := storage.ListAttestations(key, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, atts, 5)
+	require.EqualValues(t, 6, atts[0].Target.Epoch)
+	proposals, err := storage.ListProposals(key, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, proposals, 3)
+	require.EqualValues(t, 80, proposals[0].Slot)
+
+	span, err := storage.RetrieveAttestationSpan(key, 3)
+	require.NoError(t, err)
+	require.Nil(t, span)
+	span, err = storage.RetrieveAttestationSpan(key, 4)
+	require.NoError(t, err)
+	require.EqualValues(t, &core.EpochSpan{MinSpan: 1}, span)
+
+	// kept as is
+	fetchedLatest, err := storage.RetrieveLatestAttestation(key)
+	require.NoError(t, err)
+	require.True(t, latest.Compare(fetchedLatest))
+	fetchedBounds, err := storage.RetrieveAttestationSpanBounds(key)
+	require.NoError(t, err)
+	require.EqualValues(t, bounds, fetchedBounds)
+}
+
+func TestingAtomically(storage core.SlashingStore, t *testing.T) {
+	account := &mockAccount{
+		id:            uuid.New(),
+		validationKey: _bigInt("5467048590701165350380985526996487573957450279098876378395441669247373404218"),
+	}
+	key := account.ValidatorPublicKey()
+
+	// check-then-save from concurrent callers, only one of them can find the epoch empty
+	saved := make(chan bool, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(root string) {
+			defer wg.Done()
+			err := storage.Atomically(key, func(store core.SlashingStore) error {
+				existing, err := store.ListAttestations(key, 1, 1)
+				if err != nil || len(existing) > 0 {
+					return err
+				}
+				saved <- true
+				return store.SaveAttestation(key, &core.BeaconAttestation{
+					Source:          &core.Checkpoint{Epoch: 0},
+					Target:          &core.Checkpoint{Epoch: 1},
+					BeaconBlockRoot: []byte(root),
+				})
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(fmt.Sprintf("%d", i))
+	}
+	wg.Wait()
+	close(saved)
+	require.Len(t, saved, 1)
+
+	atts, err := storage.ListAttestations(key, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, atts, 1)
+
+	// errors are returned as is
+	err = storage.Atomically(key, func(store core.SlashingStore) error {
+		return fmt.Errorf("test")
+	})
+	require.EqualError(t, err, "test")
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	e2types "github.com/wealdtech/go-eth2-types/v2"
 	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
@@ -13,6 +14,7 @@ import (
 
 	eth2keymanager "github.com/bloxapp/eth2-key-manager"
 	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/wallet_nd"
 )
 
 func _byteArray(input string) []byte {
@@ -78,6 +80,7 @@ func TestingNonExistingWallet(storage core.Storage, t *testing.T) {
 	w, err := storage.OpenWallet()
 	require.NotNil(t, err)
 	require.EqualError(t, err, "wallet not found")
+	require.True(t, errors.Is(err, core.ErrNotFound))
 	require.Nil(t, w)
 }
 
@@ -155,3 +158,42 @@ func TestingWalletStorage(storage core.Storage, t *testing.T) {
 	// reset
 	storage.SetEncryptor(nil, nil)
 }
+
+func TestingNDWalletStorage(storage core.Storage, t *testing.T) {
+	options := &eth2keymanager.KeyVaultOptions{}
+	options.SetStorage(storage)
+	options.SetWalletType(core.ND)
+	options.SetEncryptor(keystorev4.New())
+	options.SetPassword("password")
+	_, err := eth2keymanager.NewKeyVault(options)
+	require.NoError(t, err)
+
+	w, err := storage.OpenWallet()
+	require.NoError(t, err)
+	require.Equal(t, core.ND, w.Type())
+	ndWallet, ok := w.(*wallet_nd.NDWallet)
+	require.True(t, ok)
+
+	keys := make([]e2types.PrivateKey, 0)
+	for i := 0; i < 3; i++ {
+		priv, err := e2types.GenerateBLSPrivateKey()
+		require.NoError(t, err)
+		_, err = ndWallet.AddValidatorAccount(priv.Marshal(), nil)
+		require.NoError(t, err)
+		keys = append(keys, priv)
+	}
+
+	// reopen and verify every imported key is there and signs
+	w, err = storage.OpenWallet()
+	require.NoError(t, err)
+	require.Equal(t, core.ND, w.Type())
+	require.Len(t, w.Accounts(), len(keys))
+	for _, priv := range keys {
+		account, err := w.AccountByPublicKey(hex.EncodeToString(priv.PublicKey().Marshal()))
+		require.NoError(t, err)
+		require.NotNil(t, account)
+		sig, err := account.ValidationKeySign([]byte("data"))
+		require.NoError(t, err)
+		require.EqualValues(t, priv.Sign([]byte("data")).Marshal(), sig.Marshal())
+	}
+}
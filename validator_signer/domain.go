@@ -0,0 +1,62 @@
+package validator_signer
+
+import (
+	"bytes"
+	"fmt"
+
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// forkSchedule is what the signer needs to compute the signing domains itself.
+type forkSchedule struct {
+	network               core.Network
+	genesisValidatorsRoot []byte
+}
+
+// SetForkSchedule makes the signer compute the domains of attestations, proposals and aggregations from the network's
+// fork schedule and the genesis validators root (the network's one if nil), at the epoch of the signed data.
+// Requests without a domain are signed with the computed domain, requests with another domain are refused.
+// It must be called before the signer is used.
+func (signer *SimpleSigner) SetForkSchedule(network core.Network, genesisValidatorsRoot []byte) error {
+	if _, err := core.NetworkFromString(string(network)); err != nil {
+		return err
+	}
+	if genesisValidatorsRoot == nil {
+		genesisValidatorsRoot = network.GenesisValidatorsRoot()
+	}
+	if len(genesisValidatorsRoot) != 32 {
+		return fmt.Errorf("genesis validators root is required to compute domains")
+	}
+
+	signer.forkSchedule = &forkSchedule{
+		network:               network,
+		genesisValidatorsRoot: genesisValidatorsRoot,
+	}
+	return nil
+}
+
+// domain returns the domain to sign with, without a fork schedule the given domain is used as is.
+func (signer *SimpleSigner) domain(domain []byte, domainType e2types.DomainType, epoch uint64) ([]byte, error) {
+	if signer.forkSchedule == nil {
+		return domain, nil
+	}
+
+	fork := signer.forkSchedule.network.ForkAtEpoch(epoch)
+	if domainType == e2types.DomainVoluntaryExit {
+		fork = signer.forkSchedule.network.VoluntaryExitFork(epoch)
+	}
+	forkInfo := &core.ForkInfo{
+		Fork:                  fork,
+		GenesisValidatorsRoot: signer.forkSchedule.genesisValidatorsRoot,
+	}
+	expected := forkInfo.Domain(domainType, epoch)
+	if len(domain) == 0 {
+		return expected, nil
+	}
+	if !bytes.Equal(domain, expected) {
+		return nil, fmt.Errorf("domain %x does not match the expected domain %x at epoch %d", domain, expected, epoch)
+	}
+	return domain, nil
+}
@@ -0,0 +1,174 @@
+package validator_signer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+func TestSetForkSchedule(t *testing.T) {
+	signer := setupAggregator(t).(*SimpleSigner)
+
+	require.EqualError(t, signer.SetForkSchedule(core.Network("unknown"), nil), "undefined network unknown")
+	require.EqualError(t, signer.SetForkSchedule(core.MainNetwork, nil), "genesis validators root is required to compute domains")
+	require.EqualError(t, signer.SetForkSchedule(core.MainNetwork, []byte{1, 2}), "genesis validators root is required to compute domains")
+	require.NoError(t, signer.SetForkSchedule(core.TestNetwork, nil))
+}
+
+func TestSignWithForkSchedule(t *testing.T) {
+	network, err := core.RegisterNetwork(&core.NetworkConfig{
+		Name:               "fork-schedule-test",
+		GenesisForkVersion: []byte{0, 0, 0, 0x10},
+		Forks: []*core.ScheduledFork{
+			{Name: "altair", Version: []byte{1, 0, 0, 0x10}, Epoch: 100},
+		},
+	})
+	require.NoError(t, err)
+	genesisValidatorsRoot := _byteArray("04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673")
+	expectedDomain := func(domainType e2types.DomainType, forkVersion []byte) []byte {
+		return e2types.Domain(domainType, forkVersion, genesisValidatorsRoot)
+	}
+
+	t.Run("empty domain is computed", func(t *testing.T) {
+		signer := setupAggregator(t).(*SimpleSigner)
+		require.NoError(t, signer.SetForkSchedule(network, genesisValidatorsRoot))
+
+		// slot 284115 is after the altair fork
+		req := slotSelectionFixture()
+		req.Domain = nil
+		res, err := signer.SignSlotSelection(req)
+		require.NoError(t, err)
+		require.EqualValues(t, expectedDomain(e2types.DomainSelectionProof, []byte{1, 0, 0, 0x10}), req.Domain)
+
+		expected, err := setupAggregator(t).SignSlotSelection(&SignSlotSelectionRequest{
+			PublicKey: req.PublicKey,
+			Domain:    expectedDomain(e2types.DomainSelectionProof, []byte{1, 0, 0, 0x10}),
+			Slot:      req.Slot,
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, expected.GetSignature(), res.GetSignature())
+	})
+
+	t.Run("matching domain", func(t *testing.T) {
+		signer := setupAggregator(t).(*SimpleSigner)
+		require.NoError(t, signer.SetForkSchedule(network, genesisValidatorsRoot))
+
+		req := aggregateAndProofFixture()
+		req.Domain = expectedDomain(e2types.DomainAggregateAndProof, []byte{1, 0, 0, 0x10})
+		_, err := signer.SignAggregateAndProof(req)
+		require.NoError(t, err)
+	})
+
+	t.Run("attestation with a wrong fork domain", func(t *testing.T) {
+		signer := setupAggregator(t).(*SimpleSigner)
+		require.NoError(t, signer.SetForkSchedule(network, genesisValidatorsRoot))
+
+		req := &pb.SignBeaconAttestationRequest{
+			Id:     &pb.SignBeaconAttestationRequest_PublicKey{PublicKey: _byteArray(aggregatorPubKey)},
+			Domain: expectedDomain(e2types.DomainBeaconAttester, []byte{0, 0, 0, 0x10}),
+			Data: &pb.AttestationData{
+				Slot:            3200,
+				BeaconBlockRoot: _byteArray("7b5679277ca45ea74e1deebc9d3e8c0e7d6c570b3cfaf6884be144a81dac9a0e"),
+				Source:          &pb.Checkpoint{Epoch: 99, Root: _byteArray("7402fdc1ce16d449d637c34a172b349a12b2bae8d6d77e401006594d8057c33d")},
+				Target:          &pb.Checkpoint{Epoch: 100, Root: _byteArray("17959acc370274756fa5e9fdd7e7adf17204f49cc8457e49438c42c4883cbfb0")},
+			},
+		}
+		_, err := signer.SignBeaconAttestation(req)
+		require.EqualError(t, err, fmt.Sprintf("domain %x does not match the expected domain %x at epoch 100", req.Domain, expectedDomain(e2types.DomainBeaconAttester, []byte{1, 0, 0, 0x10})))
+
+		// not recorded by the slashing protection, the right domain can still be signed
+		req.Domain = nil
+		_, err = signer.SignBeaconAttestation(req)
+		require.NoError(t, err)
+	})
+
+	t.Run("voluntary exit with a stale fork", func(t *testing.T) {
+		signer := setupAggregator(t).(*SimpleSigner)
+		require.NoError(t, signer.SetForkSchedule(network, genesisValidatorsRoot))
+
+		exit := &core.VoluntaryExit{Epoch: 100, ValidatorIndex: 1}
+		forkInfo := &core.ForkInfo{
+			Fork:                  &core.Fork{PreviousVersion: []byte{0, 0, 0, 0x10}, CurrentVersion: []byte{0, 0, 0, 0x10}},
+			GenesisValidatorsRoot: genesisValidatorsRoot,
+		}
+		_, err := signer.SignVoluntaryExit(_byteArray(aggregatorPubKey), exit, forkInfo)
+		require.EqualError(t, err, fmt.Sprintf("domain %x does not match the expected domain %x at epoch 100", expectedDomain(e2types.DomainVoluntaryExit, []byte{0, 0, 0, 0x10}), expectedDomain(e2types.DomainVoluntaryExit, []byte{1, 0, 0, 0x10})))
+
+		forkInfo.Fork = network.ForkAtEpoch(exit.Epoch)
+		_, err = signer.SignVoluntaryExit(_byteArray(aggregatorPubKey), exit, forkInfo)
+		require.NoError(t, err)
+	})
+
+	t.Run("randao reveal with a stale fork", func(t *testing.T) {
+		signer := setupAggregator(t).(*SimpleSigner)
+		require.NoError(t, signer.SetForkSchedule(network, genesisValidatorsRoot))
+
+		forkInfo := &core.ForkInfo{
+			Fork:                  &core.Fork{PreviousVersion: []byte{0, 0, 0, 0x10}, CurrentVersion: []byte{0, 0, 0, 0x10}},
+			GenesisValidatorsRoot: genesisValidatorsRoot,
+		}
+		_, err := signer.SignRandaoReveal(_byteArray(aggregatorPubKey), 100, forkInfo)
+		require.EqualError(t, err, fmt.Sprintf("domain %x does not match the expected domain %x at epoch 100", expectedDomain(e2types.DomainRANDAO, []byte{0, 0, 0, 0x10}), expectedDomain(e2types.DomainRANDAO, []byte{1, 0, 0, 0x10})))
+
+		// a wrong genesis validators root is refused as well
+		forkInfo.Fork = network.ForkAtEpoch(100)
+		forkInfo.GenesisValidatorsRoot = make([]byte, 32)
+		_, err = signer.SignRandaoReveal(_byteArray(aggregatorPubKey), 100, forkInfo)
+		require.Error(t, err)
+
+		forkInfo.GenesisValidatorsRoot = genesisValidatorsRoot
+		_, err = signer.SignRandaoReveal(_byteArray(aggregatorPubKey), 100, forkInfo)
+		require.NoError(t, err)
+	})
+
+	t.Run("voluntary exit after deneb", func(t *testing.T) {
+		deneb, err := core.RegisterNetwork(&core.NetworkConfig{
+			Name:               "deneb-exit-test",
+			GenesisForkVersion: []byte{0, 0, 0, 0x20},
+			Forks: []*core.ScheduledFork{
+				{Name: "capella", Version: []byte{3, 0, 0, 0x20}, Epoch: 10},
+				{Name: "deneb", Version: []byte{4, 0, 0, 0x20}, Epoch: 20},
+			},
+		})
+		require.NoError(t, err)
+		signer := setupAggregator(t).(*SimpleSigner)
+		require.NoError(t, signer.SetForkSchedule(deneb, genesisValidatorsRoot))
+
+		exit := &core.VoluntaryExit{Epoch: 30, ValidatorIndex: 1}
+		forkInfo := &core.ForkInfo{
+			Fork:                  deneb.ForkAtEpoch(exit.Epoch),
+			GenesisValidatorsRoot: genesisValidatorsRoot,
+		}
+		_, err = signer.SignVoluntaryExit(_byteArray(aggregatorPubKey), exit, forkInfo)
+		require.EqualError(t, err, fmt.Sprintf("domain %x does not match the expected domain %x at epoch 30", expectedDomain(e2types.DomainVoluntaryExit, []byte{4, 0, 0, 0x20}), expectedDomain(e2types.DomainVoluntaryExit, []byte{3, 0, 0, 0x20})))
+
+		// EIP-7044, pinned to capella
+		forkInfo.Fork = deneb.VoluntaryExitFork(exit.Epoch)
+		_, err = signer.SignVoluntaryExit(_byteArray(aggregatorPubKey), exit, forkInfo)
+		require.NoError(t, err)
+	})
+
+	t.Run("proposal before the fork", func(t *testing.T) {
+		signer := setupAggregator(t).(*SimpleSigner)
+		require.NoError(t, signer.SetForkSchedule(network, genesisValidatorsRoot))
+
+		req := &pb.SignBeaconProposalRequest{
+			Id:     &pb.SignBeaconProposalRequest_PublicKey{PublicKey: _byteArray(aggregatorPubKey)},
+			Domain: expectedDomain(e2types.DomainBeaconProposer, []byte{0, 0, 0, 0x10}),
+			Data: &pb.BeaconBlockHeader{
+				Slot:          3199,
+				ProposerIndex: 1,
+				ParentRoot:    _byteArray("7b5679277ca45ea74e1deebc9d3e8c0e7d6c570b3cfaf6884be144a81dac9a0e"),
+				StateRoot:     _byteArray("7402fdc1ce16d449d637c34a172b349a12b2bae8d6d77e401006594d8057c33d"),
+				BodyRoot:      _byteArray("17959acc370274756fa5e9fdd7e7adf17204f49cc8457e49438c42c4883cbfb0"),
+			},
+		}
+		_, err := signer.SignBeaconProposal(req)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,119 @@
+package validator_signer
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// SignSlotSelectionRequest is a request to sign a slot, the aggregator selection proof.
+// Domain is expected to be of type DOMAIN_SELECTION_PROOF.
+type SignSlotSelectionRequest struct {
+	PublicKey []byte
+	Domain    []byte
+	Slot      uint64
+}
+
+// SignAggregateAndProofRequest is a request to sign an aggregator's AggregateAndProof.
+// Domain is expected to be of type DOMAIN_AGGREGATE_AND_PROOF.
+type SignAggregateAndProofRequest struct {
+	PublicKey []byte
+	Domain    []byte
+	Data      *ethpb.AggregateAttestationAndProof
+}
+
+// SignSlotSelection signs the slot, used by validators to find out if they are aggregators and as their selection proof.
+// Not slashable so it doesn't go through the slashing protection.
+func (signer *SimpleSigner) SignSlotSelection(req *SignSlotSelectionRequest) (*pb.SignResponse, error) {
+	// 1. get the account
+	if req.PublicKey == nil {
+		return nil, fmt.Errorf("account was not supplied")
+	}
+	account, err := signer.wallet.AccountByPublicKey(hex.EncodeToString(req.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+	domain, err := signer.domain(req.Domain, e2types.DomainSelectionProof, core.EpochAtSlot(req.Slot))
+	if err != nil {
+		return nil, err
+	}
+	req.Domain = domain
+
+	// 2. Prepare and sign data
+	forSig, err := PrepareSlotSelectionReqForSigning(req)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := account.ValidationKeySign(forSig)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.SignResponse{
+		State:     pb.ResponseState_SUCCEEDED,
+		Signature: sig.Marshal(),
+	}
+
+	return res, nil
+}
+
+// SignAggregateAndProof signs an aggregated attestation and its selection proof.
+// Aggregations are not slashable so it doesn't go through the slashing protection.
+func (signer *SimpleSigner) SignAggregateAndProof(req *SignAggregateAndProofRequest) (*pb.SignResponse, error) {
+	// 1. get the account
+	if req.PublicKey == nil {
+		return nil, fmt.Errorf("account was not supplied")
+	}
+	if req.Data == nil || req.Data.Aggregate == nil || req.Data.Aggregate.Data == nil {
+		return nil, fmt.Errorf("aggregate and proof was not supplied")
+	}
+	account, err := signer.wallet.AccountByPublicKey(hex.EncodeToString(req.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+	domain, err := signer.domain(req.Domain, e2types.DomainAggregateAndProof, core.EpochAtSlot(req.Data.Aggregate.Data.Slot))
+	if err != nil {
+		return nil, err
+	}
+	req.Domain = domain
+
+	// 2. Prepare and sign data
+	forSig, err := PrepareAggregateAndProofReqForSigning(req)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := account.ValidationKeySign(forSig)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.SignResponse{
+		State:     pb.ResponseState_SUCCEEDED,
+		Signature: sig.Marshal(),
+	}
+
+	return res, nil
+}
+
+// PrepareSlotSelectionReqForSigning prepares the given slot selection request for signing.
+// This is exported to allow use it by custom signing mechanism.
+func PrepareSlotSelectionReqForSigning(req *SignSlotSelectionRequest) ([]byte, error) {
+	forSig, err := prepareForSig(req.Slot, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+	return forSig[:], nil
+}
+
+// PrepareAggregateAndProofReqForSigning prepares the given aggregate and proof request for signing.
+// This is exported to allow use it by custom signing mechanism.
+func PrepareAggregateAndProofReqForSigning(req *SignAggregateAndProofRequest) ([]byte, error) {
+	forSig, err := prepareForSig(req.Data, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+	return forSig[:], nil
+}
@@ -0,0 +1,122 @@
+package validator_signer
+
+import (
+	"encoding/hex"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	eth2keymanager "github.com/bloxapp/eth2-key-manager"
+	"github.com/bloxapp/eth2-key-manager/core"
+	prot "github.com/bloxapp/eth2-key-manager/slashing_protection"
+	"github.com/bloxapp/eth2-key-manager/wallet_nd"
+)
+
+// interop validator 0 key, vectors were computed with an independent BLS and SSZ implementation
+const (
+	aggregatorPrivKey = "25295f0d1d592a90b333e26e85149708208e9f8e8bc18f6c77bd62f8ad7a6866"
+	aggregatorPubKey  = "a99a76ed7796f7be22d5b7e85deeb7c5677e88e511e0b337618f8c4eb61349b4bf2d153f649f7b53359fe8b94a38e44c"
+)
+
+func setupAggregator(t *testing.T) ValidatorSigner {
+	require.NoError(t, e2types.InitBLS())
+	store := inmemStorage()
+	options := &eth2keymanager.KeyVaultOptions{}
+	options.SetStorage(store)
+	options.SetWalletType(core.ND)
+	vault, err := eth2keymanager.NewKeyVault(options)
+	require.NoError(t, err)
+	wallet, err := vault.Wallet()
+	require.NoError(t, err)
+
+	account, err := wallet.(*wallet_nd.NDWallet).AddValidatorAccount(_byteArray(aggregatorPrivKey), nil)
+	require.NoError(t, err)
+	require.EqualValues(t, aggregatorPubKey, hex.EncodeToString(account.ValidatorPublicKey().Marshal()))
+
+	return NewSimpleSigner(wallet, prot.NewNormalProtection(store))
+}
+
+func slotSelectionFixture() *SignSlotSelectionRequest {
+	return &SignSlotSelectionRequest{
+		PublicKey: _byteArray(aggregatorPubKey),
+		Domain:    _byteArray("05000000e7a75d5a9f3f331a669f33fe87d35b0735dce47180b272ed244e3053"),
+		Slot:      284115,
+	}
+}
+
+func aggregateAndProofFixture() *SignAggregateAndProofRequest {
+	return &SignAggregateAndProofRequest{
+		PublicKey: _byteArray(aggregatorPubKey),
+		Domain:    _byteArray("06000000e7a75d5a9f3f331a669f33fe87d35b0735dce47180b272ed244e3053"),
+		Data: &ethpb.AggregateAttestationAndProof{
+			AggregatorIndex: 1,
+			Aggregate: &ethpb.Attestation{
+				AggregationBits: _byteArray("0f"),
+				Data: &ethpb.AttestationData{
+					Slot:            284115,
+					CommitteeIndex:  2,
+					BeaconBlockRoot: _byteArray("7b5679277ca45ea74e1deebc9d3e8c0e7d6c570b3cfaf6884be144a81dac9a0e"),
+					Source: &ethpb.Checkpoint{
+						Epoch: 8877,
+						Root:  _byteArray("7402fdc1ce16d449d637c34a172b349a12b2bae8d6d77e401006594d8057c33d"),
+					},
+					Target: &ethpb.Checkpoint{
+						Epoch: 8878,
+						Root:  _byteArray("17959acc370274756fa5e9fdd7e7adf17204f49cc8457e49438c42c4883cbfb0"),
+					},
+				},
+				Signature: _byteArray("a4e5007a85f22430e10e1ff8e7ecbfa07cbea4dec9dc737bfacecb5ce3c2e8ef7f75483afa26edef13a36500c3bc4f110a8cbe4594ed8a87ae6e18d4274592e3d3ba2492c1d1fe0e8f41d5f5cfcf042e5de8589fa57585511d0c61ccbd38a3f3"),
+			},
+			SelectionProof: _byteArray("859c14d7171e3c1a33d22a58bfe00576d6bb1277755561444df708496c05ccc8b85761ac3cde882f14b57777eed4e942076c2f871194b5eb53914f575fce92a01f40d6886d85e446fe1723995e3ca97dec6933309ce93e007b9c0d4ace0f466d"),
+		},
+	}
+}
+
+func TestSlotSelectionRootComputation(t *testing.T) {
+	root, err := PrepareSlotSelectionReqForSigning(slotSelectionFixture())
+	require.NoError(t, err)
+	require.EqualValues(t, "bb619f9e5ddf5940775c61ae9f63caad4b3c52d30871c5b73d7958f4d950e35c", hex.EncodeToString(root))
+}
+
+func TestAggregateAndProofRootComputation(t *testing.T) {
+	root, err := PrepareAggregateAndProofReqForSigning(aggregateAndProofFixture())
+	require.NoError(t, err)
+	require.EqualValues(t, "94543f619b095053688bc86e634357855fd912bfa337e596ca6f98306e05745c", hex.EncodeToString(root))
+}
+
+func TestSignSlotSelection(t *testing.T) {
+	signer := setupAggregator(t)
+
+	res, err := signer.SignSlotSelection(slotSelectionFixture())
+	require.NoError(t, err)
+	require.EqualValues(t, "859c14d7171e3c1a33d22a58bfe00576d6bb1277755561444df708496c05ccc8b85761ac3cde882f14b57777eed4e942076c2f871194b5eb53914f575fce92a01f40d6886d85e446fe1723995e3ca97dec6933309ce93e007b9c0d4ace0f466d", hex.EncodeToString(res.Signature))
+
+	t.Run("missing public key", func(t *testing.T) {
+		req := slotSelectionFixture()
+		req.PublicKey = nil
+		_, err := signer.SignSlotSelection(req)
+		require.EqualError(t, err, "account was not supplied")
+	})
+}
+
+func TestSignAggregateAndProof(t *testing.T) {
+	signer := setupAggregator(t)
+
+	res, err := signer.SignAggregateAndProof(aggregateAndProofFixture())
+	require.NoError(t, err)
+	require.EqualValues(t, "b51baee5c695ad9ee6abaf1f5815428f49e8b9c114d52bf88f30d7d917ac78b694d2ed2f68b93be4a6801eef4b1afb990097a7f64a174a82e9c76efd4334fb1baf38dc4b063299c74f945128c70a03011759548144f74d2febfcea04410688c8", hex.EncodeToString(res.Signature))
+
+	t.Run("signing again is not slashable", func(t *testing.T) {
+		_, err := signer.SignAggregateAndProof(aggregateAndProofFixture())
+		require.NoError(t, err)
+	})
+
+	t.Run("missing aggregate", func(t *testing.T) {
+		req := aggregateAndProofFixture()
+		req.Data.Aggregate = nil
+		_, err := signer.SignAggregateAndProof(req)
+		require.EqualError(t, err, "aggregate and proof was not supplied")
+	})
+}
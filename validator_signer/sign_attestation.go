@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
 
 	"github.com/bloxapp/eth2-key-manager/core"
 )
@@ -18,6 +19,11 @@ func (signer *SimpleSigner) SignBeaconAttestation(req *pb.SignBeaconAttestationR
 	if err != nil {
 		return nil, err
 	}
+	domain, err := signer.domain(req.GetDomain(), e2types.DomainBeaconAttester, req.GetData().GetTarget().GetEpoch())
+	if err != nil {
+		return nil, err
+	}
+	req.Domain = domain
 
 	// 2. lock for current account
 	signer.lock(account.ID(), "attestation")
@@ -25,23 +31,19 @@ func (signer *SimpleSigner) SignBeaconAttestation(req *pb.SignBeaconAttestationR
 		signer.unlock(account.ID(), "attestation")
 	}()
 
-	// 3. check we can even sign this
-	if val, err := signer.slashingProtector.IsSlashableAttestation(account.ValidatorPublicKey(), req); err != nil || len(val) != 0 {
+	// 3. check we can even sign this and add to protection storage, atomically so concurrent signers sharing the
+	// store can't both sign
+	if val, err := signer.slashingProtector.CheckAndRecordAttestation(account.ValidatorPublicKey(), req); err != nil || len(val) != 0 {
 		if err != nil {
 			return nil, err
 		}
-		if len(val) > 0 {
-
+		return &pb.SignResponse{State: pb.ResponseState_DENIED}, &SlashableError{
+			Msg:      fmt.Sprintf("slashable attestation (%s), not signing", val[0].Status),
+			Verdicts: core.AttestationVerdicts(core.ToCoreAttestationData(req), val),
 		}
-		return nil, fmt.Errorf("slashable attestation (%s), not signing", val[0].Status)
-	}
-
-	// 4. add to protection storage
-	if err := signer.slashingProtector.SaveAttestation(account.ValidatorPublicKey(), req); err != nil {
-		return nil, err
 	}
 
-	// 5. Prepare and sign data
+	// 4. Prepare and sign data
 	forSig, err := PrepareAttestationReqForSigning(req)
 	if err != nil {
 		return nil, err
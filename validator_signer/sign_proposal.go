@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
 
 	"github.com/bloxapp/eth2-key-manager/core"
 )
@@ -18,22 +19,25 @@ func (signer *SimpleSigner) SignBeaconProposal(req *pb.SignBeaconProposalRequest
 	if err != nil {
 		return nil, err
 	}
+	domain, err := signer.domain(req.GetDomain(), e2types.DomainBeaconProposer, core.EpochAtSlot(req.GetData().GetSlot()))
+	if err != nil {
+		return nil, err
+	}
+	req.Domain = domain
 
 	// 2. lock for current account
 	signer.lock(account.ID(), "proposal")
 	defer signer.unlock(account.ID(), "proposal")
 
-	// 2. check we can even sign this
-	if status := signer.slashingProtector.IsSlashableProposal(account.ValidatorPublicKey(), req); status.Status != core.ValidProposal {
+	// 3. check we can even sign this and add to protection storage, see SignBeaconAttestation
+	if status := signer.slashingProtector.CheckAndRecordProposal(account.ValidatorPublicKey(), req); status.Status != core.ValidProposal {
 		if status.Error != nil {
 			return nil, status.Error
 		}
-		return nil, fmt.Errorf("err, slashable proposal: %s", status.Status)
-	}
-
-	// 3. add to protection storage
-	if err := signer.slashingProtector.SaveProposal(account.ValidatorPublicKey(), req); err != nil {
-		return nil, err
+		return &pb.SignResponse{State: pb.ResponseState_DENIED}, &SlashableError{
+			Msg:      fmt.Sprintf("err, slashable proposal: %s", status.Status),
+			Verdicts: []*core.SlashingVerdict{core.ProposalVerdict(core.ToCoreBlockData(req), status)},
+		}
 	}
 
 	// 4. generate ssz root hash and sign
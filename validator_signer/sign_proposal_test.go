@@ -2,9 +2,13 @@ package validator_signer
 
 import (
 	"encoding/hex"
+	"testing"
+
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"github.com/wealdtech/eth2-signer-api/pb/v1"
-	"testing"
+
+	"github.com/bloxapp/eth2-key-manager/core"
 )
 
 //func TestMultipleConcurrentProposalSignatures(t *testing.T) {
@@ -123,7 +127,7 @@ func TestProposalSlashingSignatures(t *testing.T) {
 	})
 
 	t.Run("double proposal, different state root. Should error", func(t *testing.T) {
-		_, err = signer.SignBeaconProposal(&v1.SignBeaconProposalRequest{
+		res, err := signer.SignBeaconProposal(&v1.SignBeaconProposalRequest{
 			Id:     &v1.SignBeaconProposalRequest_PublicKey{PublicKey: _byteArray("83e04069ed28b637f113d272a235af3e610401f252860ed2063d87d985931229458e3786e9b331cd73d9fc58863d9e4b")},
 			Domain: []byte("domain"),
 			Data: &v1.BeaconBlockHeader{
@@ -136,6 +140,14 @@ func TestProposalSlashingSignatures(t *testing.T) {
 		})
 		require.NotNil(t, err)
 		require.EqualError(t, err, "err, slashable proposal: DoubleProposal")
+		var slashable *core.SlashableError
+		require.True(t, errors.As(err, &slashable))
+		require.Len(t, slashable.Verdicts, 1)
+		require.EqualValues(t, core.DoubleProposal, slashable.Verdicts[0].Detection)
+		require.EqualValues(t, []byte("A"), slashable.Verdicts[0].Proposal.StateRoot)
+		require.EqualValues(t, 99, slashable.Verdicts[0].ConflictingProposal.Slot)
+		require.EqualValues(t, []byte("Z"), slashable.Verdicts[0].ConflictingProposal.StateRoot)
+		require.EqualValues(t, v1.ResponseState_DENIED, res.GetState())
 	})
 
 	t.Run("double proposal, different body root. Should error", func(t *testing.T) {
@@ -0,0 +1,68 @@
+package validator_signer
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// SignRandaoReveal signs the epoch's RANDAO reveal needed to propose a block during that epoch.
+// Reveals for an epoch lower than the highest signed proposal's can't be used to propose and are refused.
+func (signer *SimpleSigner) SignRandaoReveal(pubKey []byte, epoch uint64, forkInfo *core.ForkInfo) (*pb.SignResponse, error) {
+	// 1. get the account
+	if pubKey == nil {
+		return nil, fmt.Errorf("account was not supplied")
+	}
+	if forkInfo == nil || forkInfo.Fork == nil {
+		return nil, fmt.Errorf("fork info was not supplied")
+	}
+	account, err := signer.wallet.AccountByPublicKey(hex.EncodeToString(pubKey))
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. check the epoch against the last proposal
+	highest, err := signer.slashingProtector.RetrieveHighestProposal(account.ValidatorPublicKey())
+	if err != nil {
+		return nil, err
+	}
+	if highest != nil && epoch < core.EpochAtSlot(highest.Slot) {
+		return &pb.SignResponse{State: pb.ResponseState_DENIED}, &SlashableError{
+			Msg: fmt.Sprintf("randao reveal epoch %d is lower than the highest proposal epoch %d, not signing", epoch, core.EpochAtSlot(highest.Slot)),
+		}
+	}
+
+	// 3. Prepare and sign data
+	domain, err := signer.domain(forkInfo.Domain(e2types.DomainRANDAO, epoch), e2types.DomainRANDAO, epoch)
+	if err != nil {
+		return nil, err
+	}
+	forSig, err := PrepareRandaoRevealForSigning(epoch, domain)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := account.ValidationKeySign(forSig)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.SignResponse{
+		State:     pb.ResponseState_SUCCEEDED,
+		Signature: sig.Marshal(),
+	}
+
+	return res, nil
+}
+
+// PrepareRandaoRevealForSigning prepares the epoch for signing with the given DOMAIN_RANDAO domain.
+// This is exported to allow use it by custom signing mechanism.
+func PrepareRandaoRevealForSigning(epoch uint64, domain []byte) ([]byte, error) {
+	forSig, err := prepareForSig(epoch, domain)
+	if err != nil {
+		return nil, err
+	}
+	return forSig[:], nil
+}
@@ -0,0 +1,80 @@
+package validator_signer
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+func randaoForkInfo() *core.ForkInfo {
+	return &core.ForkInfo{
+		Fork: &core.Fork{
+			PreviousVersion: _byteArray("00000001"),
+			CurrentVersion:  _byteArray("00000001"),
+			Epoch:           0,
+		},
+		GenesisValidatorsRoot: _byteArray("04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673"),
+	}
+}
+
+func TestRandaoRevealRootComputation(t *testing.T) {
+	root, err := PrepareRandaoRevealForSigning(8878, _byteArray("02000000e7a75d5a9f3f331a669f33fe87d35b0735dce47180b272ed244e3053"))
+	require.NoError(t, err)
+	require.EqualValues(t, "d9940dbabd946595f10d3602454e8f84b2c469045a09c922d317a55a498ad12e", hex.EncodeToString(root))
+}
+
+func TestSignRandaoReveal(t *testing.T) {
+	signer := setupAggregator(t)
+	pubKey := _byteArray(aggregatorPubKey)
+
+	res, err := signer.SignRandaoReveal(pubKey, 8878, randaoForkInfo())
+	require.NoError(t, err)
+	require.EqualValues(t, "987d1e6ce24eda5d4b18c55f81c4787ca2f73c0ae7d06c9d3629e4686551ecf07ef53cb7de97eea4d9a4c38304dcbcd5094307f3635f9bea11756828ae3e6b231a1dff642612e8a405b24867e1f17193d759dd4bc2a2fab5e0ca98446128e11e", hex.EncodeToString(res.Signature))
+}
+
+func TestSignRandaoRevealAfterProposal(t *testing.T) {
+	seed := _byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+	signer, err := setupWithSlashingProtection(seed)
+	require.NoError(t, err)
+	accounts, err := signer.ListAccounts()
+	require.NoError(t, err)
+	pubKey := accounts.Accounts[0].PublicKey
+
+	_, err = signer.SignBeaconProposal(&pb.SignBeaconProposalRequest{
+		Id:     &pb.SignBeaconProposalRequest_PublicKey{PublicKey: pubKey},
+		Domain: _byteArray("0000000000000000000000000000000000000000000000000000000000000000"),
+		Data: &pb.BeaconBlockHeader{
+			Slot:          10 * core.SlotsPerEpoch,
+			ProposerIndex: 1,
+			ParentRoot:    _byteArray("A000000000000000000000000000000000000000000000000000000000000000"),
+			StateRoot:     _byteArray("A000000000000000000000000000000000000000000000000000000000000000"),
+			BodyRoot:      _byteArray("A000000000000000000000000000000000000000000000000000000000000000"),
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("same epoch", func(t *testing.T) {
+		_, err := signer.SignRandaoReveal(pubKey, 10, randaoForkInfo())
+		require.NoError(t, err)
+	})
+
+	t.Run("higher epoch", func(t *testing.T) {
+		_, err := signer.SignRandaoReveal(pubKey, 11, randaoForkInfo())
+		require.NoError(t, err)
+	})
+
+	t.Run("lower epoch", func(t *testing.T) {
+		_, err := signer.SignRandaoReveal(pubKey, 9, randaoForkInfo())
+		require.EqualError(t, err, "randao reveal epoch 9 is lower than the highest proposal epoch 10, not signing")
+		require.IsType(t, &SlashableError{}, err)
+	})
+
+	t.Run("missing fork info", func(t *testing.T) {
+		_, err := signer.SignRandaoReveal(pubKey, 11, nil)
+		require.EqualError(t, err, "fork info was not supplied")
+	})
+}
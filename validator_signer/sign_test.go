@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
 	e2types "github.com/wealdtech/go-eth2-types/v2"
 	util "github.com/wealdtech/go-eth2-util"
@@ -13,6 +14,7 @@ import (
 	"github.com/bloxapp/eth2-key-manager/core"
 	prot "github.com/bloxapp/eth2-key-manager/slashing_protection"
 	"github.com/bloxapp/eth2-key-manager/stores/in_memory"
+	"github.com/bloxapp/eth2-key-manager/wallet_nd"
 )
 
 func inmemStorage() *in_memory.InMemStore {
@@ -166,3 +168,37 @@ func TestSignatures(t *testing.T) {
 		})
 	}
 }
+
+func TestSignWithNDWallet(t *testing.T) {
+	require.NoError(t, e2types.InitBLS())
+	store := inmemStorage()
+	options := &eth2keymanager.KeyVaultOptions{}
+	options.SetStorage(store)
+	options.SetWalletType(core.ND)
+	vault, err := eth2keymanager.NewKeyVault(options)
+	require.NoError(t, err)
+	wallet, err := vault.Wallet()
+	require.NoError(t, err)
+
+	priv, err := e2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+	_, err = wallet.(*wallet_nd.NDWallet).AddValidatorAccount(priv.Marshal(), nil)
+	require.NoError(t, err)
+
+	signer := NewSimpleSigner(wallet, prot.NewNormalProtection(store))
+	req := &pb.SignRequest{
+		Id:     &pb.SignRequest_PublicKey{PublicKey: priv.PublicKey().Marshal()},
+		Data:   _byteArray("0000000000000000000000000000000000000000000000000000000000000000"),
+		Domain: _byteArray("0000000000000000000000000000000000000000000000000000000000000000"),
+	}
+	res, err := signer.Sign(req)
+	require.NoError(t, err)
+
+	forSig, err := PrepareReqForSigning(req)
+	require.NoError(t, err)
+	require.EqualValues(t, priv.Sign(forSig[:]).Marshal(), res.Signature)
+
+	accounts, err := signer.ListAccounts()
+	require.NoError(t, err)
+	require.Len(t, accounts.Accounts, 1)
+}
@@ -0,0 +1,60 @@
+package validator_signer
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// SignVoluntaryExit signs a voluntary exit with the DOMAIN_VOLUNTARY_EXIT domain at the exit's epoch.
+// Exits are not slashable so it doesn't go through the slashing protection.
+func (signer *SimpleSigner) SignVoluntaryExit(pubKey []byte, exit *core.VoluntaryExit, forkInfo *core.ForkInfo) (*pb.SignResponse, error) {
+	// 1. get the account
+	if pubKey == nil {
+		return nil, fmt.Errorf("account was not supplied")
+	}
+	if exit == nil {
+		return nil, fmt.Errorf("voluntary exit was not supplied")
+	}
+	if forkInfo == nil || forkInfo.Fork == nil {
+		return nil, fmt.Errorf("fork info was not supplied")
+	}
+	account, err := signer.wallet.AccountByPublicKey(hex.EncodeToString(pubKey))
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Prepare and sign data
+	domain, err := signer.domain(forkInfo.Domain(e2types.DomainVoluntaryExit, exit.Epoch), e2types.DomainVoluntaryExit, exit.Epoch)
+	if err != nil {
+		return nil, err
+	}
+	forSig, err := PrepareVoluntaryExitForSigning(exit, domain)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := account.ValidationKeySign(forSig)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.SignResponse{
+		State:     pb.ResponseState_SUCCEEDED,
+		Signature: sig.Marshal(),
+	}
+
+	return res, nil
+}
+
+// PrepareVoluntaryExitForSigning prepares the voluntary exit for signing with the given DOMAIN_VOLUNTARY_EXIT domain.
+// This is exported to allow use it by custom signing mechanism.
+func PrepareVoluntaryExitForSigning(exit *core.VoluntaryExit, domain []byte) ([]byte, error) {
+	forSig, err := prepareForSig(exit, domain)
+	if err != nil {
+		return nil, err
+	}
+	return forSig[:], nil
+}
@@ -0,0 +1,49 @@
+package validator_signer
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+func TestVoluntaryExitRootComputation(t *testing.T) {
+	root, err := PrepareVoluntaryExitForSigning(&core.VoluntaryExit{Epoch: 8878, ValidatorIndex: 25}, _byteArray("04000000e7a75d5a9f3f331a669f33fe87d35b0735dce47180b272ed244e3053"))
+	require.NoError(t, err)
+	require.EqualValues(t, "b61e5dd240b4e1661e110c313c5c2304ad09153cbc49c665767716cd3d62b224", hex.EncodeToString(root))
+}
+
+func TestSignVoluntaryExit(t *testing.T) {
+	signer := setupAggregator(t)
+	pubKey := _byteArray(aggregatorPubKey)
+
+	res, err := signer.SignVoluntaryExit(pubKey, &core.VoluntaryExit{Epoch: 8878, ValidatorIndex: 25}, randaoForkInfo())
+	require.NoError(t, err)
+	require.EqualValues(t, "8c07ff4383b9cf35e38c51c1d9e0152a405c36359392d3a967c5b2e8620f46f376ce98799b58a8931d90f8eafa64726706abbf95b06d4d41ffe82e7af640f5d9652fcc2698d06d2072af542ec1d3d0bea9a70d348bce8f1a0d5075d2cb3c33c6", hex.EncodeToString(res.Signature))
+}
+
+func TestSignVoluntaryExitErrors(t *testing.T) {
+	seed := _byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+	signer, err := setupWithSlashingProtection(seed)
+	require.NoError(t, err)
+	accounts, err := signer.ListAccounts()
+	require.NoError(t, err)
+	pubKey := accounts.Accounts[0].PublicKey
+
+	t.Run("missing exit", func(t *testing.T) {
+		_, err := signer.SignVoluntaryExit(pubKey, nil, randaoForkInfo())
+		require.EqualError(t, err, "voluntary exit was not supplied")
+	})
+
+	t.Run("missing fork info", func(t *testing.T) {
+		_, err := signer.SignVoluntaryExit(pubKey, &core.VoluntaryExit{Epoch: 1}, nil)
+		require.EqualError(t, err, "fork info was not supplied")
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		_, err := signer.SignVoluntaryExit(make([]byte, 48), &core.VoluntaryExit{Epoch: 1}, randaoForkInfo())
+		require.Error(t, err)
+	})
+}
@@ -14,9 +14,17 @@ type ValidatorSigner interface {
 	ListAccounts() (*pb.ListAccountsResponse, error)
 	SignBeaconProposal(req *pb.SignBeaconProposalRequest) (*pb.SignResponse, error)
 	SignBeaconAttestation(req *pb.SignBeaconAttestationRequest) (*pb.SignResponse, error)
+	SignSlotSelection(req *SignSlotSelectionRequest) (*pb.SignResponse, error)
+	SignAggregateAndProof(req *SignAggregateAndProofRequest) (*pb.SignResponse, error)
+	SignRandaoReveal(pubKey []byte, epoch uint64, forkInfo *core.ForkInfo) (*pb.SignResponse, error)
+	SignVoluntaryExit(pubKey []byte, exit *core.VoluntaryExit, forkInfo *core.ForkInfo) (*pb.SignResponse, error)
 	Sign(req *pb.SignRequest) (*pb.SignResponse, error)
 }
 
+// SlashableError is returned when the slashing protector refuses to sign a message, see core.SlashableError.
+// The sign methods return it along with a DENIED response.
+type SlashableError = core.SlashableError
+
 type signingRoot struct {
 	Hash   [32]byte `ssz-size:"32"`
 	Domain []byte   `ssz-size:"32"`
@@ -26,6 +34,8 @@ type SimpleSigner struct {
 	wallet            core.Wallet
 	slashingProtector core.SlashingProtector
 	signLocks         map[string]*sync.RWMutex
+	signLocksLock     sync.Mutex
+	forkSchedule      *forkSchedule // domains are checked only when set
 }
 
 func NewSimpleSigner(wallet core.Wallet, slashingProtector core.SlashingProtector) *SimpleSigner {
@@ -39,17 +49,22 @@ func NewSimpleSigner(wallet core.Wallet, slashingProtector core.SlashingProtecto
 // if already locked, will lock until released
 func (signer *SimpleSigner) lock(accountId uuid.UUID, operation string) {
 	k := accountId.String() + "_" + operation
-	if val, ok := signer.signLocks[k]; ok {
-		val.Lock()
-	} else {
-		signer.signLocks[k] = &sync.RWMutex{}
-		signer.signLocks[k].Lock()
+	signer.signLocksLock.Lock()
+	val, ok := signer.signLocks[k]
+	if !ok {
+		val = &sync.RWMutex{}
+		signer.signLocks[k] = val
 	}
+	signer.signLocksLock.Unlock()
+	val.Lock()
 }
 
 func (signer *SimpleSigner) unlock(accountId uuid.UUID, operation string) {
 	k := accountId.String() + "_" + operation
-	if val, ok := signer.signLocks[k]; ok {
+	signer.signLocksLock.Lock()
+	val, ok := signer.signLocks[k]
+	signer.signLocksLock.Unlock()
+	if ok {
 		val.Unlock()
 	}
 }
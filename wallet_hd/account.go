@@ -1,6 +1,7 @@
 package wallet_hd
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -146,13 +147,18 @@ func (account *HDAccount) ValidationKeySign(data []byte) (e2types.Signature, err
 	return account.validationKey.Sign(data)
 }
 
-// Get Deposit Data
-func (account *HDAccount) GetDepositData() (map[string]interface{}, error) {
+// GetDepositData returns the deposit data of amountInGwei, withdrawing to executionAddress (0x01 credentials) if given
+// or to the withdrawal key (0x00 credentials) otherwise.
+func (account *HDAccount) GetDepositData(executionAddress []byte, amountInGwei uint64) (map[string]interface{}, error) {
+	withdrawalCredentials, err := account.withdrawalCredentials(executionAddress)
+	if err != nil {
+		return nil, err
+	}
 	depositData, root, err := eth1_deposit.DepositData(
 		account.validationKey,
-		account.withdrawalPubKey.Marshal(),
+		withdrawalCredentials,
 		account.context.Storage.Network(),
-		eth1_deposit.MaxEffectiveBalanceInGwei,
+		amountInGwei,
 	)
 	if err != nil {
 		return nil, err
@@ -166,6 +172,49 @@ func (account *HDAccount) GetDepositData() (map[string]interface{}, error) {
 	}, nil
 }
 
+func (account *HDAccount) withdrawalCredentials(executionAddress []byte) ([]byte, error) {
+	if executionAddress != nil {
+		if len(executionAddress) != 20 {
+			return nil, fmt.Errorf("execution address must be 20 bytes")
+		}
+		return eth1_deposit.ExecutionAddressWithdrawalCredentials(executionAddress), nil
+	}
+	return eth1_deposit.BLSWithdrawalCredentials(account.withdrawalPubKey.Marshal()), nil
+}
+
+// ExportKeystore encrypts the validation key into an EIP-2335 keystore.
+func (account *HDAccount) ExportKeystore(password string, kdf string) (map[string]interface{}, error) {
+	return core.KeystoreFromHDKey(account.validationKey, password, kdf)
+}
+
+// WithdrawalKeySign signs data with the withdrawal key, derived on demand from the seed as it is never stored.
+func (account *HDAccount) WithdrawalKeySign(seed []byte, data []byte) (e2types.Signature, error) {
+	key, err := account.withdrawalKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	return key.Sign(data)
+}
+
+// withdrawalKey derives the withdrawal key (m/12381/3600/<index>/0) and checks it matches the account's.
+func (account *HDAccount) withdrawalKey(seed []byte) (*core.HDKey, error) {
+	if len(seed) == 0 {
+		return nil, fmt.Errorf("seed was not supplied")
+	}
+	master, err := core.MasterKeyFromSeed(seed, account.context.Storage.Network())
+	if err != nil {
+		return nil, err
+	}
+	key, err := master.Derive(account.basePath + "/0")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(key.PublicKey().Marshal(), account.withdrawalPubKey.Marshal()) {
+		return nil, ErrSeedMismatch
+	}
+	return key, nil
+}
+
 func (account *HDAccount) SetContext(ctx *core.WalletContext) {
 	account.context = ctx
 }
@@ -0,0 +1,81 @@
+package wallet_hd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	ssz "github.com/prysmaticlabs/go-ssz"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// executionAddressLength is the length of an execution layer (eth1) address
+const executionAddressLength = 20
+
+// SignBLSToExecutionChange signs, with the withdrawal key derived from the seed, a change of the validator's 0x00
+// withdrawal credentials to 0x01 credentials pointing to toExecutionAddress.
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/capella/beacon-chain.md#new-process_bls_to_execution_change
+func (account *HDAccount) SignBLSToExecutionChange(seed []byte, validatorIndex uint64, toExecutionAddress []byte, genesisValidatorsRoot []byte) (*core.SignedBLSToExecutionChange, error) {
+	if len(toExecutionAddress) != executionAddressLength {
+		return nil, fmt.Errorf("execution address must be %d bytes", executionAddressLength)
+	}
+	if len(genesisValidatorsRoot) != 32 {
+		return nil, fmt.Errorf("genesis validators root must be 32 bytes")
+	}
+
+	change := &core.BLSToExecutionChange{
+		ValidatorIndex:     validatorIndex,
+		FromBLSPubkey:      account.withdrawalPubKey.Marshal(),
+		ToExecutionAddress: toExecutionAddress,
+	}
+	objRoot, err := ssz.HashTreeRoot(change)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine the root hash of bls to execution change")
+	}
+
+	// Prepare for sig
+	signingContainer := struct {
+		Root   []byte `json:"object_root,omitempty" ssz-size:"32"`
+		Domain []byte `json:"domain,omitempty" ssz-size:"32"`
+	}{
+		Root:   objRoot[:],
+		Domain: core.BLSToExecutionChangeDomain(account.context.Storage.Network(), genesisValidatorsRoot),
+	}
+	root, err := ssz.HashTreeRoot(signingContainer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine the root hash of signing container")
+	}
+
+	sig, err := account.WithdrawalKeySign(seed, root[:])
+	if err != nil {
+		return nil, err
+	}
+	return core.NewSignedBLSToExecutionChange(change, sig.Marshal()), nil
+}
+
+// SignBLSToExecutionChanges signs a BLS to execution change for each of the given accounts (hex validator public key
+// -> beacon chain validator index), all withdrawing to toExecutionAddress. Changes are sorted by validator index.
+func (wallet *HDWallet) SignBLSToExecutionChanges(seed []byte, validatorIndices map[string]uint64, toExecutionAddress []byte, genesisValidatorsRoot []byte) ([]*core.SignedBLSToExecutionChange, error) {
+	ret := make([]*core.SignedBLSToExecutionChange, 0, len(validatorIndices))
+	for pubKey, index := range validatorIndices {
+		account, err := wallet.AccountByPublicKey(pubKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get account %s", pubKey)
+		}
+		hdAccount, ok := account.(*HDAccount)
+		if !ok {
+			return nil, fmt.Errorf("account %s is not derived from the wallet's seed", pubKey)
+		}
+		change, err := hdAccount.SignBLSToExecutionChange(seed, index, toExecutionAddress, genesisValidatorsRoot)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to sign bls to execution change for account %s", pubKey)
+		}
+		ret = append(ret, change)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Message.ValidatorIndex < ret[j].Message.ValidatorIndex
+	})
+	return ret, nil
+}
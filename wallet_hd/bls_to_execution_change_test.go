@@ -0,0 +1,81 @@
+package wallet_hd
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+var (
+	executionAddress      = _byteArray("8ba1f109551bd432803012645ac136ddd64dba72")
+	genesisValidatorsRoot = _byteArray("4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95")
+)
+
+func blsChangeWallet(t *testing.T) (*HDWallet, []byte) {
+	require.NoError(t, e2types.InitBLS())
+	w := NewHDWallet(&core.WalletContext{Storage: &mapStorage{accounts: make(map[uuid.UUID]core.ValidatorAccount)}})
+	seed := _byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+	for i := 0; i < 2; i++ {
+		_, err := w.CreateValidatorAccount(seed, nil)
+		require.NoError(t, err)
+	}
+	return w, seed
+}
+
+func TestSignBLSToExecutionChange(t *testing.T) {
+	w, seed := blsChangeWallet(t)
+	account := w.Accounts()[1].(*HDAccount) // account-0
+
+	change, err := account.SignBLSToExecutionChange(seed, 25, executionAddress, genesisValidatorsRoot)
+	require.NoError(t, err)
+	require.EqualValues(t, 25, change.Message.ValidatorIndex)
+	require.EqualValues(t, "b323537b2867d9f2bae068f93e75a9e2e1c8d594e3696c34dc8010dc403eaeeaf43756a440fc82e1c6f45c6e8348343f", hex.EncodeToString(change.Message.FromBLSPubkey))
+	require.EqualValues(t, executionAddress, change.Message.ToExecutionAddress)
+	require.EqualValues(t, "90334b21d6560ab9f5eede3f494a4c9b6a14e0a8ef9e42eb12782bff1669a6641b19f303d1613503a8bc63e0dcff4857007fbb2f66b1a005d6ff769325101c63d8775d590d27726dd9931b9a4e90eae74433a0746194faffb9640eaa5ccc2a03", hex.EncodeToString(change.Signature))
+}
+
+func TestSignBLSToExecutionChangeErrors(t *testing.T) {
+	w, seed := blsChangeWallet(t)
+	account := w.Accounts()[0].(*HDAccount)
+
+	_, err := account.SignBLSToExecutionChange(seed, 25, executionAddress[1:], genesisValidatorsRoot)
+	require.EqualError(t, err, "execution address must be 20 bytes")
+
+	_, err = account.SignBLSToExecutionChange(seed, 25, executionAddress, genesisValidatorsRoot[1:])
+	require.EqualError(t, err, "genesis validators root must be 32 bytes")
+
+	_, err = account.SignBLSToExecutionChange(nil, 25, executionAddress, genesisValidatorsRoot)
+	require.EqualError(t, err, "seed was not supplied")
+
+	otherSeed := _byteArray("ff02030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+	_, err = account.SignBLSToExecutionChange(otherSeed, 25, executionAddress, genesisValidatorsRoot)
+	require.EqualError(t, err, ErrSeedMismatch.Error())
+}
+
+func TestSignBLSToExecutionChanges(t *testing.T) {
+	w, seed := blsChangeWallet(t)
+	accounts := w.Accounts()
+
+	changes, err := w.SignBLSToExecutionChanges(seed, map[string]uint64{
+		hex.EncodeToString(accounts[0].ValidatorPublicKey().Marshal()): 30,
+		hex.EncodeToString(accounts[1].ValidatorPublicKey().Marshal()): 12,
+	}, executionAddress, genesisValidatorsRoot)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	// sorted by validator index
+	require.EqualValues(t, 12, changes[0].Message.ValidatorIndex)
+	require.EqualValues(t, accounts[1].WithdrawalPublicKey().Marshal(), changes[0].Message.FromBLSPubkey)
+	require.EqualValues(t, 30, changes[1].Message.ValidatorIndex)
+	require.EqualValues(t, accounts[0].WithdrawalPublicKey().Marshal(), changes[1].Message.FromBLSPubkey)
+
+	t.Run("unknown account", func(t *testing.T) {
+		_, err := w.SignBLSToExecutionChanges(seed, map[string]uint64{"aa": 1}, executionAddress, genesisValidatorsRoot)
+		require.EqualError(t, err, "failed to get account aa: account not found")
+	})
+}
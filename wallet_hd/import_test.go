@@ -0,0 +1,76 @@
+package wallet_hd
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// keeps accounts so the wallet can open what it saved
+type mapStorage struct {
+	dummyStorage
+	accounts map[uuid.UUID]core.ValidatorAccount
+}
+
+func (s *mapStorage) SaveAccount(account core.ValidatorAccount) error {
+	s.accounts[account.ID()] = account
+	return nil
+}
+func (s *mapStorage) OpenAccount(accountId uuid.UUID) (core.ValidatorAccount, error) {
+	return s.accounts[accountId], nil
+}
+
+func TestImportValidatorAccount(t *testing.T) {
+	require.NoError(t, e2types.InitBLS())
+	w := NewHDWallet(&core.WalletContext{Storage: &mapStorage{accounts: make(map[uuid.UUID]core.ValidatorAccount)}})
+	seed := _byteArray("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1fff")
+
+	priv, err := e2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+	crypto, err := keystorev4.New().Encrypt(priv.Marshal(), "password")
+	require.NoError(t, err)
+	keystore := map[string]interface{}{
+		"crypto":  crypto,
+		"pubkey":  hex.EncodeToString(priv.PublicKey().Marshal()),
+		"path":    "m/12381/3600/7/0/0",
+		"uuid":    uuid.New().String(),
+		"version": float64(4),
+	}
+
+	_, err = w.ImportValidatorAccount(keystore, "wrong")
+	require.True(t, errors.Is(err, core.ErrDecryptionFailed))
+
+	imported, err := w.ImportValidatorAccount(keystore, "password")
+	require.NoError(t, err)
+	require.EqualValues(t, priv.PublicKey().Marshal(), imported.ValidatorPublicKey().Marshal())
+	require.Empty(t, imported.BasePath())
+
+	_, err = w.ImportValidatorAccount(keystore, "password")
+	require.EqualError(t, err, ErrAccountExists.Error())
+
+	// imported accounts don't take an index
+	require.EqualValues(t, 0, w.GetNextAccountIndex())
+	derived, err := w.CreateValidatorAccount(seed, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, "/0", derived.BasePath())
+	require.EqualValues(t, 1, w.GetNextAccountIndex())
+
+	// derived accounts first
+	accounts := w.Accounts()
+	require.Len(t, accounts, 2)
+	require.Equal(t, derived.ID(), accounts[0].ID())
+	require.Equal(t, imported.ID(), accounts[1].ID())
+
+	fetched, err := w.AccountByPublicKey(hex.EncodeToString(priv.PublicKey().Marshal()))
+	require.NoError(t, err)
+	sig, err := fetched.ValidationKeySign([]byte("data"))
+	require.NoError(t, err)
+	require.EqualValues(t, priv.Sign([]byte("data")).Marshal(), sig.Marshal())
+}
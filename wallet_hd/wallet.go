@@ -3,6 +3,7 @@ package wallet_hd
 import (
 	"encoding/hex"
 	"fmt"
+	"log"
 	"sort"
 	"strconv"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/wallet_nd"
 )
 
 // according to https://github.com/ethereum/EIPs/blob/master/EIPS/eip-2334.md
@@ -22,7 +24,11 @@ const (
 // Predefined errors
 var (
 	// ErrAccountNotFound is the error when account not found
-	ErrAccountNotFound = errors.New("account not found")
+	ErrAccountNotFound = &core.NotFoundError{Kind: "account"}
+	// ErrAccountExists is the error when importing a key the wallet already holds
+	ErrAccountExists = wallet_nd.ErrAccountExists
+	// ErrSeedMismatch is the error when the given seed isn't the one the account was derived from
+	ErrSeedMismatch = errors.New("seed does not match the account's withdrawal key")
 )
 
 // an hierarchical deterministic wallet
@@ -57,7 +63,11 @@ func (wallet *HDWallet) GetNextAccountIndex() int {
 	if len(wallet.indexMapper) == 0 {
 		return 0
 	}
+	// imported accounts are sorted last, if the first has no index there are no derived accounts
 	accounts := wallet.Accounts()
+	if len(accounts) == 0 || !isDerived(accounts[0]) {
+		return 0
+	}
 	index, _ := strconv.ParseInt(accounts[0].BasePath()[1:], 0, 64)
 	return int(index) + 1
 }
@@ -131,6 +141,12 @@ func (wallet *HDWallet) CreateValidatorAccount(seed []byte, indexPointer *int) (
 	return ret, nil
 }
 
+// ImportValidatorAccount adds an account from an EIP-2335 keystore.
+// The key can't be derived from the wallet's seed so the account has no base path (nor a withdrawal key).
+func (wallet *HDWallet) ImportValidatorAccount(keystore map[string]interface{}, password string) (core.ValidatorAccount, error) {
+	return wallet_nd.ImportKeystoreAccount(wallet, wallet.indexMapper, wallet.context, keystore, password, nil)
+}
+
 func (wallet *HDWallet) DeleteAccountByPublicKey(pubKey string) error {
 	account, err := wallet.AccountByPublicKey(pubKey)
 	if err != nil {
@@ -156,11 +172,20 @@ func (wallet *HDWallet) Accounts() []core.ValidatorAccount {
 		id := wallet.indexMapper[pubKey]
 		account, err := wallet.AccountByID(id)
 		if err != nil {
+			// e.g. a wrong storage password, the other accounts are still listed
+			log.Printf("failed to open account %s: %v", id, err)
 			continue
 		}
 		accounts = append(accounts, account)
 	}
+	// derived accounts by index (descending) followed by imported accounts by name
 	sort.Slice(accounts, func(i, j int) bool {
+		if !isDerived(accounts[i]) || !isDerived(accounts[j]) {
+			if isDerived(accounts[i]) != isDerived(accounts[j]) {
+				return isDerived(accounts[i])
+			}
+			return accounts[i].Name() < accounts[j].Name()
+		}
 		a, _ := strconv.ParseInt(accounts[i].BasePath()[1:], 0, 64)
 		b, _ := strconv.ParseInt(accounts[j].BasePath()[1:], 0, 64)
 		return a > b
@@ -168,6 +193,11 @@ func (wallet *HDWallet) Accounts() []core.ValidatorAccount {
 	return accounts
 }
 
+// imported accounts have no base path
+func isDerived(account core.ValidatorAccount) bool {
+	return len(account.BasePath()) > 0
+}
+
 // AccountByID provides a single account from the wallet given its ID.
 // This will error if the account is not found.
 func (wallet *HDWallet) AccountByID(id uuid.UUID) (core.ValidatorAccount, error) {
@@ -175,9 +205,6 @@ func (wallet *HDWallet) AccountByID(id uuid.UUID) (core.ValidatorAccount, error)
 	if err != nil {
 		return nil, err
 	}
-	if ret == nil {
-		return nil, nil
-	}
 	ret.SetContext(wallet.context)
 	return ret, nil
 }
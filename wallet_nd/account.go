@@ -0,0 +1,200 @@
+package wallet_nd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/eth1_deposit"
+)
+
+// NDAccount holds an imported validation key, it has no base path as it wasn't derived from the wallet's seed.
+type NDAccount struct {
+	name          string
+	id            uuid.UUID
+	validationKey *core.HDKey
+	// optional, imported keys might not come with their withdrawal key
+	withdrawalPubKey e2types.PublicKey
+	context          *core.WalletContext
+}
+
+func (account *NDAccount) MarshalJSON() ([]byte, error) {
+	data := make(map[string]interface{})
+
+	data["id"] = account.id
+	data["type"] = core.ND
+	data["name"] = account.name
+	data["validationKey"] = account.validationKey
+	if account.withdrawalPubKey != nil {
+		data["withdrawalPubKey"] = hex.EncodeToString(account.withdrawalPubKey.Marshal())
+	}
+	return json.Marshal(data)
+}
+
+func (account *NDAccount) UnmarshalJSON(data []byte) error {
+	// parse
+	var v map[string]interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	var err error
+
+	// id
+	if val, exists := v["id"]; exists {
+		account.id, err = uuid.Parse(val.(string))
+		if err != nil {
+			return err
+		}
+	} else {
+		return fmt.Errorf("could not find var: id")
+	}
+
+	// name
+	if val, exists := v["name"]; exists {
+		account.name = val.(string)
+	} else {
+		return fmt.Errorf("could not find var: name")
+	}
+
+	// validation key
+	if val, exists := v["validationKey"]; exists {
+		byts, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		key := &core.HDKey{}
+		err = json.Unmarshal(byts, key)
+		if err != nil {
+			return err
+		}
+		account.validationKey = key
+	} else {
+		return fmt.Errorf("could not find var: validationKey")
+	}
+
+	// withdrawal pub Key, optional
+	if val, exists := v["withdrawalPubKey"]; exists {
+		byts, err := hex.DecodeString(val.(string))
+		if err != nil {
+			return err
+		}
+		account.withdrawalPubKey, err = e2types.BLSPublicKeyFromBytes(byts)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func NewValidatorAccount(
+	name string,
+	validationKey *core.HDKey,
+	withdrawalPubKey e2types.PublicKey,
+	context *core.WalletContext,
+) (*NDAccount, error) {
+	return &NDAccount{
+		name:             name,
+		id:               uuid.New(),
+		validationKey:    validationKey,
+		withdrawalPubKey: withdrawalPubKey,
+		context:          context,
+	}, nil
+}
+
+// NewImportedAccount creates an account for a key imported into a wallet, it's named after its public key.
+func NewImportedAccount(validationKey *core.HDKey, withdrawalPubKey e2types.PublicKey, context *core.WalletContext) (*NDAccount, error) {
+	validatorPublicKey := hex.EncodeToString(validationKey.PublicKey().Marshal())
+	return NewValidatorAccount(
+		fmt.Sprintf("account-%s", validatorPublicKey[:8]),
+		validationKey,
+		withdrawalPubKey,
+		context,
+	)
+}
+
+// ID provides the ID for the account.
+func (account *NDAccount) ID() uuid.UUID {
+	return account.id
+}
+
+// Name provides the name for the account.
+func (account *NDAccount) Name() string {
+	return account.name
+}
+
+// BasePath is always empty as ND accounts are not derived.
+func (account *NDAccount) BasePath() string {
+	return ""
+}
+
+// ValidatorPublicKey provides the public key for the account.
+func (account *NDAccount) ValidatorPublicKey() e2types.PublicKey {
+	return account.validationKey.PublicKey()
+}
+
+// WithdrawalPublicKey provides the withdrawal public key for the account, nil if it wasn't imported.
+func (account *NDAccount) WithdrawalPublicKey() e2types.PublicKey {
+	return account.withdrawalPubKey
+}
+
+// Sign signs data with the account.
+func (account *NDAccount) ValidationKeySign(data []byte) (e2types.Signature, error) {
+	return account.validationKey.Sign(data)
+}
+
+// GetDepositData returns the deposit data of amountInGwei, withdrawing to executionAddress (0x01 credentials) if given
+// or to the withdrawal key (0x00 credentials) otherwise.
+func (account *NDAccount) GetDepositData(executionAddress []byte, amountInGwei uint64) (map[string]interface{}, error) {
+	withdrawalCredentials, err := account.withdrawalCredentials(executionAddress)
+	if err != nil {
+		return nil, err
+	}
+	depositData, root, err := eth1_deposit.DepositData(
+		account.validationKey,
+		withdrawalCredentials,
+		account.context.Storage.Network(),
+		amountInGwei,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"amount":                depositData.GetAmount(),
+		"publicKey":             hex.EncodeToString(depositData.GetPublicKey()),
+		"signature":             hex.EncodeToString(depositData.GetSignature()),
+		"withdrawalCredentials": hex.EncodeToString(depositData.GetWithdrawalCredentials()),
+		"depositDataRoot":       hex.EncodeToString(root[:]),
+	}, nil
+}
+
+func (account *NDAccount) withdrawalCredentials(executionAddress []byte) ([]byte, error) {
+	if executionAddress != nil {
+		if len(executionAddress) != 20 {
+			return nil, fmt.Errorf("execution address must be 20 bytes")
+		}
+		return eth1_deposit.ExecutionAddressWithdrawalCredentials(executionAddress), nil
+	}
+	if account.withdrawalPubKey == nil {
+		return nil, fmt.Errorf("account has no withdrawal public key")
+	}
+	return eth1_deposit.BLSWithdrawalCredentials(account.withdrawalPubKey.Marshal()), nil
+}
+
+// ExportKeystore encrypts the validation key into an EIP-2335 keystore.
+func (account *NDAccount) ExportKeystore(password string, kdf string) (map[string]interface{}, error) {
+	return core.KeystoreFromHDKey(account.validationKey, password, kdf)
+}
+
+// WithdrawalKeySign always fails as ND accounts are not derived, their withdrawal key can't be derived from a seed.
+func (account *NDAccount) WithdrawalKeySign(seed []byte, data []byte) (e2types.Signature, error) {
+	return nil, fmt.Errorf("account is not derived from a seed, can't sign with its withdrawal key")
+}
+
+func (account *NDAccount) SetContext(ctx *core.WalletContext) {
+	account.context = ctx
+}
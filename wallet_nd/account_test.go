@@ -0,0 +1,56 @@
+package wallet_nd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+func TestAccountMarshaling(t *testing.T) {
+	require.NoError(t, e2types.InitBLS())
+
+	priv, err := e2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+	withdrawal, err := e2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+	key, err := core.NewHDKeyFromPrivateKey(priv.Marshal(), "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		testName         string
+		withdrawalPubKey e2types.PublicKey
+	}{
+		{
+			testName:         "with withdrawal key",
+			withdrawalPubKey: withdrawal.PublicKey(),
+		},
+		{
+			testName: "without withdrawal key",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			a, err := NewValidatorAccount("account1", key, test.withdrawalPubKey, nil)
+			require.NoError(t, err)
+
+			byts, err := json.Marshal(a)
+			require.NoError(t, err)
+			a1 := &NDAccount{}
+			require.NoError(t, json.Unmarshal(byts, a1))
+
+			require.Equal(t, a.id, a1.id)
+			require.Equal(t, a.name, a1.name)
+			require.Equal(t, a.validationKey.PublicKey().Marshal(), a1.validationKey.PublicKey().Marshal())
+			if test.withdrawalPubKey != nil {
+				require.Equal(t, a.withdrawalPubKey.Marshal(), a1.withdrawalPubKey.Marshal())
+			} else {
+				require.Nil(t, a1.withdrawalPubKey)
+			}
+		})
+	}
+}
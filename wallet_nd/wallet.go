@@ -0,0 +1,186 @@
+package wallet_nd
+
+import (
+	"encoding/hex"
+	"log"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+)
+
+// Predefined errors
+var (
+	// ErrAccountNotFound is the error when account not found
+	ErrAccountNotFound = &core.NotFoundError{Kind: "account"}
+	// ErrAccountExists is the error when importing a key the wallet already holds
+	ErrAccountExists = &core.AlreadyExistsError{Kind: "account"}
+	// ErrNotDerivable is the error when trying to derive an account from a seed
+	ErrNotDerivable = errors.New("non deterministic wallet can't derive accounts, import them instead")
+)
+
+// a non deterministic wallet, holds imported keys which can't be derived from a seed
+type NDWallet struct {
+	id          uuid.UUID
+	walletType  core.WalletType
+	indexMapper map[string]uuid.UUID
+	context     *core.WalletContext
+}
+
+func NewNDWallet(context *core.WalletContext) *NDWallet {
+	return &NDWallet{
+		id:          uuid.New(),
+		walletType:  core.ND,
+		indexMapper: make(map[string]uuid.UUID),
+		context:     context,
+	}
+}
+
+// ID provides the ID for the wallet.
+func (wallet *NDWallet) ID() uuid.UUID {
+	return wallet.id
+}
+
+// Type provides the type of the wallet.
+func (wallet *NDWallet) Type() core.WalletType {
+	return wallet.walletType
+}
+
+// CreateValidatorAccount always fails, accounts are added with AddValidatorAccount.
+func (wallet *NDWallet) CreateValidatorAccount(seed []byte, indexPointer *int) (core.ValidatorAccount, error) {
+	return nil, ErrNotDerivable
+}
+
+// AddValidatorAccount adds an account from a raw BLS private key.
+// withdrawalPubKey is optional, without it the account can't produce deposit data.
+func (wallet *NDWallet) AddValidatorAccount(privKey []byte, withdrawalPubKey e2types.PublicKey) (core.ValidatorAccount, error) {
+	key, err := core.NewHDKeyFromPrivateKey(privKey, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid private key")
+	}
+	return wallet.addAccount(key, withdrawalPubKey)
+}
+
+// AddValidatorAccountFromKeystore adds an account from an EIP-2335 keystore.
+func (wallet *NDWallet) AddValidatorAccountFromKeystore(keystore map[string]interface{}, password string, withdrawalPubKey e2types.PublicKey) (core.ValidatorAccount, error) {
+	return ImportKeystoreAccount(wallet, wallet.indexMapper, wallet.context, keystore, password, withdrawalPubKey)
+}
+
+// ImportValidatorAccount adds an account from an EIP-2335 keystore, without a withdrawal key.
+func (wallet *NDWallet) ImportValidatorAccount(keystore map[string]interface{}, password string) (core.ValidatorAccount, error) {
+	return wallet.AddValidatorAccountFromKeystore(keystore, password, nil)
+}
+
+func (wallet *NDWallet) addAccount(key *core.HDKey, withdrawalPubKey e2types.PublicKey) (core.ValidatorAccount, error) {
+	return AddImportedAccount(wallet, wallet.indexMapper, wallet.context, key, withdrawalPubKey)
+}
+
+// ImportKeystoreAccount decrypts an EIP-2335 keystore and adds its key to the wallet, see AddImportedAccount.
+func ImportKeystoreAccount(wallet core.Wallet, indexMapper map[string]uuid.UUID, context *core.WalletContext, keystore map[string]interface{}, password string, withdrawalPubKey e2types.PublicKey) (core.ValidatorAccount, error) {
+	key, err := core.HDKeyFromKeystore(keystore, password)
+	if err != nil {
+		return nil, err
+	}
+	return AddImportedAccount(wallet, indexMapper, context, key, withdrawalPubKey)
+}
+
+// AddImportedAccount adds a key that isn't derived from a seed to the wallet, used by every wallet holding imported
+// accounts. indexMapper is the wallet's public key -> account id map, the account is added to it and the account and
+// the wallet are saved to the context's storage.
+func AddImportedAccount(wallet core.Wallet, indexMapper map[string]uuid.UUID, context *core.WalletContext, key *core.HDKey, withdrawalPubKey e2types.PublicKey) (core.ValidatorAccount, error) {
+	validatorPublicKey := hex.EncodeToString(key.PublicKey().Marshal())
+	if _, exists := indexMapper[validatorPublicKey]; exists {
+		return nil, ErrAccountExists
+	}
+
+	ret, err := NewImportedAccount(key, withdrawalPubKey, context)
+	if err != nil {
+		return nil, err
+	}
+
+	// Register new account
+	reset := func() {
+		delete(indexMapper, validatorPublicKey)
+	}
+	indexMapper[validatorPublicKey] = ret.ID()
+
+	// Store account
+	if err = context.Storage.SaveAccount(ret); err != nil {
+		reset()
+		return nil, err
+	}
+
+	// Store wallet
+	err = context.Storage.SaveWallet(wallet)
+	if err != nil {
+		reset()
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (wallet *NDWallet) DeleteAccountByPublicKey(pubKey string) error {
+	account, err := wallet.AccountByPublicKey(pubKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to get account by public key")
+	}
+
+	err = wallet.context.Storage.DeleteAccount(account.ID())
+	if err != nil {
+		return errors.Wrap(err, "failed to delete account from store")
+	}
+	delete(wallet.indexMapper, pubKey)
+	err = wallet.context.Storage.SaveWallet(wallet)
+	if err != nil {
+		return errors.Wrap(err, "failed to save wallet")
+	}
+	return nil
+}
+
+// Accounts provides all accounts in the wallet, sorted by name.
+func (wallet *NDWallet) Accounts() []core.ValidatorAccount {
+	accounts := make([]core.ValidatorAccount, 0)
+	for pubKey := range wallet.indexMapper {
+		id := wallet.indexMapper[pubKey]
+		account, err := wallet.AccountByID(id)
+		if err != nil {
+			// e.g. a wrong storage password, the other accounts are still listed
+			log.Printf("failed to open account %s: %v", id, err)
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Name() < accounts[j].Name()
+	})
+	return accounts
+}
+
+// AccountByID provides a single account from the wallet given its ID.
+// This will error if the account is not found.
+func (wallet *NDWallet) AccountByID(id uuid.UUID) (core.ValidatorAccount, error) {
+	ret, err := wallet.context.Storage.OpenAccount(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.SetContext(wallet.context)
+	return ret, nil
+}
+
+func (wallet *NDWallet) SetContext(ctx *core.WalletContext) {
+	wallet.context = ctx
+}
+
+// AccountByPublicKey provides a single account from the wallet given its public key.
+// This will error if the account is not found.
+func (wallet *NDWallet) AccountByPublicKey(pubKey string) (core.ValidatorAccount, error) {
+	id, exists := wallet.indexMapper[pubKey]
+	if !exists {
+		return nil, ErrAccountNotFound
+	}
+	return wallet.AccountByID(id)
+}
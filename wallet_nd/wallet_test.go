@@ -0,0 +1,163 @@
+package wallet_nd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	e2types "github.com/wealdtech/go-eth2-types/v2"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	types "github.com/wealdtech/go-eth2-wallet-types/v2"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/eth1_deposit"
+)
+
+// keeps accounts in a map so the wallet can open what it saved
+type dummyStorage struct {
+	accounts map[uuid.UUID]core.ValidatorAccount
+}
+
+func (s *dummyStorage) Name() string                        { return "" }
+func (s *dummyStorage) Network() core.Network               { return core.MainNetwork }
+func (s *dummyStorage) SaveWallet(wallet core.Wallet) error { return nil }
+func (s *dummyStorage) OpenWallet() (core.Wallet, error)    { return nil, nil }
+func (s *dummyStorage) ListAccounts() ([]core.ValidatorAccount, error) {
+	return nil, nil
+}
+func (s *dummyStorage) SaveAccount(account core.ValidatorAccount) error {
+	s.accounts[account.ID()] = account
+	return nil
+}
+func (s *dummyStorage) OpenAccount(accountId uuid.UUID) (core.ValidatorAccount, error) {
+	return s.accounts[accountId], nil
+}
+func (s *dummyStorage) DeleteAccount(accountId uuid.UUID) error {
+	delete(s.accounts, accountId)
+	return nil
+}
+func (s *dummyStorage) SetEncryptor(encryptor types.Encryptor, password []byte) {}
+
+func storage() core.Storage {
+	return &dummyStorage{accounts: make(map[uuid.UUID]core.ValidatorAccount)}
+}
+
+func newWallet() *NDWallet {
+	return NewNDWallet(&core.WalletContext{Storage: storage()})
+}
+
+func TestAddValidatorAccount(t *testing.T) {
+	require.NoError(t, e2types.InitBLS())
+	w := newWallet()
+
+	priv, err := e2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+	withdrawal, err := e2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+
+	account, err := w.AddValidatorAccount(priv.Marshal(), withdrawal.PublicKey())
+	require.NoError(t, err)
+	require.EqualValues(t, priv.PublicKey().Marshal(), account.ValidatorPublicKey().Marshal())
+	require.EqualValues(t, withdrawal.PublicKey().Marshal(), account.WithdrawalPublicKey().Marshal())
+	require.Empty(t, account.BasePath())
+
+	t.Run("fetch", func(t *testing.T) {
+		fetched, err := w.AccountByPublicKey(hex.EncodeToString(priv.PublicKey().Marshal()))
+		require.NoError(t, err)
+		require.Equal(t, account.ID(), fetched.ID())
+		require.Len(t, w.Accounts(), 1)
+	})
+
+	t.Run("sign", func(t *testing.T) {
+		sig, err := account.ValidationKeySign([]byte("data"))
+		require.NoError(t, err)
+		require.EqualValues(t, priv.Sign([]byte("data")).Marshal(), sig.Marshal())
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		_, err := w.AddValidatorAccount(priv.Marshal(), nil)
+		require.EqualError(t, err, ErrAccountExists.Error())
+		require.Len(t, w.Accounts(), 1)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, w.DeleteAccountByPublicKey(hex.EncodeToString(priv.PublicKey().Marshal())))
+		require.Len(t, w.Accounts(), 0)
+		_, err := w.AccountByPublicKey(hex.EncodeToString(priv.PublicKey().Marshal()))
+		require.EqualError(t, err, ErrAccountNotFound.Error())
+	})
+}
+
+func TestAddValidatorAccountFromKeystore(t *testing.T) {
+	require.NoError(t, e2types.InitBLS())
+	w := newWallet()
+
+	priv, err := e2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+	crypto, err := keystorev4.New().Encrypt(priv.Marshal(), "password")
+	require.NoError(t, err)
+	keystore := map[string]interface{}{
+		"crypto":  crypto,
+		"pubkey":  hex.EncodeToString(priv.PublicKey().Marshal()),
+		"path":    "",
+		"uuid":    uuid.New().String(),
+		"version": float64(4),
+	}
+
+	_, err = w.AddValidatorAccountFromKeystore(keystore, "wrong", nil)
+	require.Error(t, err)
+	require.Len(t, w.Accounts(), 0)
+
+	account, err := w.AddValidatorAccountFromKeystore(keystore, "password", nil)
+	require.NoError(t, err)
+	require.EqualValues(t, priv.PublicKey().Marshal(), account.ValidatorPublicKey().Marshal())
+	require.Nil(t, account.WithdrawalPublicKey())
+
+	_, err = account.GetDepositData(nil, eth1_deposit.MaxEffectiveBalanceInGwei)
+	require.EqualError(t, err, "account has no withdrawal public key")
+
+	// execution address credentials don't need a withdrawal key
+	address, _ := hex.DecodeString("8ba1f109551bd432803012645ac136ddd64dba72")
+	depositData, err := account.GetDepositData(address, eth1_deposit.MaxEffectiveBalanceInGwei)
+	require.NoError(t, err)
+	require.EqualValues(t, "0100000000000000000000008ba1f109551bd432803012645ac136ddd64dba72", depositData["withdrawalCredentials"])
+	require.EqualValues(t, eth1_deposit.MaxEffectiveBalanceInGwei, depositData["amount"])
+
+	// partial deposits and top-ups
+	depositData, err = account.GetDepositData(address, 1500000000)
+	require.NoError(t, err)
+	require.EqualValues(t, 1500000000, depositData["amount"])
+
+	_, err = account.GetDepositData(address, 100)
+	require.EqualError(t, err, "deposit amount 100 gwei is lower than the minimum of 1000000000 gwei")
+}
+
+func TestCreateValidatorAccount(t *testing.T) {
+	_, err := newWallet().CreateValidatorAccount([]byte("seed"), nil)
+	require.EqualError(t, err, ErrNotDerivable.Error())
+}
+
+func TestWalletMarshaling(t *testing.T) {
+	require.NoError(t, e2types.InitBLS())
+	w := newWallet()
+	priv, err := e2types.GenerateBLSPrivateKey()
+	require.NoError(t, err)
+	_, err = w.AddValidatorAccount(priv.Marshal(), nil)
+	require.NoError(t, err)
+
+	byts, err := json.Marshal(w)
+	require.NoError(t, err)
+
+	w1 := &NDWallet{}
+	require.NoError(t, json.Unmarshal(byts, w1))
+	require.Equal(t, w.id, w1.id)
+	require.Equal(t, core.ND, w1.walletType)
+	require.Equal(t, w.indexMapper, w1.indexMapper)
+
+	t.Run("wrong type", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`{"id":"`+uuid.New().String()+`","type":"HD","indexMapper":{}}`), &NDWallet{})
+		require.EqualError(t, err, "wallet type HD is not ND")
+	})
+}